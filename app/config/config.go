@@ -29,6 +29,7 @@ const (
 	Notes
 	Editor
 	BreadCrumb
+	Highlights
 )
 
 // Map of Section enum values to their string representations
@@ -39,6 +40,7 @@ var sections = map[Section]string{
 	Notes:      "Notes",
 	Editor:     "Editor",
 	BreadCrumb: "Breadcrumb",
+	Highlights: "Highlights",
 }
 
 // String returns the string representation of a Section
@@ -63,8 +65,25 @@ const (
 	NerdFonts
 	Border
 	SearchIgnoreCase
+	SearchSmartCase
+	SearchRegex
 	AutoOpenNewNote
 	IndentLines
+	TimeoutLen
+	FlushDelay
+	PersistSettings
+	AutoIndent
+	TabStop
+	ShiftWidth
+	ExpandTab
+	TaskDate
+	Spell
+	SpellLang
+	Syntax
+	TextWidth
+	Wrap
+	BreakIndent
+	ShowBreak
 )
 
 // Map of Option enum values to their string names as used in the ini file
@@ -83,8 +102,25 @@ var options = map[Option]string{
 	NerdFonts:        "NerdFonts",
 	Border:           "Border",
 	SearchIgnoreCase: "SearchIgnoreCase",
+	SearchSmartCase:  "SearchSmartCase",
+	SearchRegex:      "SearchRegex",
 	AutoOpenNewNote:  "AutoOpenNewNote",
 	IndentLines:      "IndentLines",
+	TimeoutLen:       "TimeoutLen",
+	FlushDelay:       "FlushDelay",
+	PersistSettings:  "PersistSettings",
+	AutoIndent:       "AutoIndent",
+	TabStop:          "TabStop",
+	ShiftWidth:       "ShiftWidth",
+	ExpandTab:        "ExpandTab",
+	TaskDate:         "TaskDate",
+	Spell:            "Spell",
+	SpellLang:        "SpellLang",
+	Syntax:           "Syntax",
+	TextWidth:        "TextWidth",
+	Wrap:             "Wrap",
+	BreakIndent:      "BreakIndent",
+	ShowBreak:        "ShowBreak",
 }
 
 // String returns the string representation of an Option
@@ -131,6 +167,10 @@ type Config struct {
 
 	// cached nerdFonts config value
 	nerdFonts *bool
+
+	// overrides holds the values set with `:set` that
+	// haven't been written to the config file
+	overrides map[optionKey]string
 }
 
 func (conf *Config) File() string { return conf.filePath }
@@ -178,6 +218,12 @@ func New() *Config {
 	config.userFile = userConf
 	config.flushDelay = 400 * time.Millisecond
 
+	if delay, err := config.Value(General, FlushDelay); err == nil {
+		if ms, err := delay.GetInt(); err == nil {
+			config.flushDelay = time.Duration(ms) * time.Millisecond
+		}
+	}
+
 	// Meta info file
 	metaFilePath, err := config.MetaFile()
 	if err != nil {
@@ -208,7 +254,12 @@ func (c *Config) Reload() {
 }
 
 // Value retrieves the value of a configuration option in a given section.
+// Values set with `:set` take precedence over the config file
 func (conf *Config) Value(section Section, option Option) (Value, error) {
+	if value, ok := conf.overrides[optionKey{section, option}]; ok {
+		return Value{value}, nil
+	}
+
 	if sect := conf.userFile.Section(section.String()); sect != nil {
 		if opt := sect.Key(option.String()); opt.String() != "" {
 			return Value{opt.String()}, nil
@@ -232,6 +283,46 @@ func (conf *Config) Value(section Section, option Option) (Value, error) {
 	}
 }
 
+// Entry is an option of a section whose option names
+// are chosen by the user, e.g. the names of highlights
+type Entry struct {
+	Name  string
+	Value string
+}
+
+// Entries returns the options of a section in the order of the default
+// config followed by the options only the user's config file has.
+// Options of the user's config file replace those of the same name
+func (conf *Config) Entries(section Section) []Entry {
+	entries := []Entry{}
+	index := map[string]int{}
+
+	for _, file := range []*ini.File{conf.file, conf.userFile} {
+		if file == nil {
+			continue
+		}
+
+		sect, err := file.GetSection(section.String())
+		if err != nil {
+			continue
+		}
+
+		for _, key := range sect.Keys() {
+			entry := Entry{Name: key.Name(), Value: key.String()}
+
+			if i, ok := index[entry.Name]; ok {
+				entries[i] = entry
+				continue
+			}
+
+			index[entry.Name] = len(entries)
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
 // MetaValue retrieves a metadata value by a section and option.
 func (conf *Config) MetaValue(section string, option Option) (string, error) {
 	if conf.file == nil {
@@ -260,6 +351,8 @@ func (conf *Config) MetaValue(section string, option Option) (string, error) {
 // SetValue sets a configuration option value in the specified section
 // and saves the config file immediately
 func (conf *Config) SetValue(section Section, option Option, value string) {
+	delete(conf.overrides, optionKey{section, option})
+
 	conf.userFile.
 		Section(section.String()).
 		Key(option.String()).
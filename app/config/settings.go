@@ -0,0 +1,296 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// SettingType is the type of a setting's value
+type SettingType int
+
+const (
+	BoolSetting SettingType = iota
+	IntSetting
+	StringSetting
+	EnumSetting
+)
+
+// Scope determines whether a setting can have a value per buffer
+type Scope int
+
+const (
+	GlobalScope Scope = iota
+	BufferScope
+)
+
+// Setting describes a config option that can be changed at runtime
+// with `:set`
+type Setting struct {
+	// Name is the name used with `:set`, Alias an optional short name
+	Name, Alias string
+
+	Type  SettingType
+	Scope Scope
+
+	// Section and Option are the entry in the config file
+	// that holds the setting's value
+	Section Section
+	Option  Option
+
+	// Values are the allowed values of enum settings
+	Values []string
+
+	// Min is the smallest allowed value of int settings
+	Min int
+}
+
+// Settings holds every setting that can be changed with `:set`
+var Settings = []Setting{
+	{
+		Name:    "number",
+		Alias:   "nu",
+		Type:    BoolSetting,
+		Scope:   BufferScope,
+		Section: Editor,
+		Option:  LineNumbers,
+	},
+	{
+		Name:    "autoindent",
+		Alias:   "ai",
+		Type:    BoolSetting,
+		Scope:   BufferScope,
+		Section: Editor,
+		Option:  AutoIndent,
+	},
+	{
+		Name:    "tabstop",
+		Alias:   "ts",
+		Type:    IntSetting,
+		Scope:   BufferScope,
+		Section: Editor,
+		Option:  TabStop,
+		Min:     1,
+	},
+	{
+		Name:    "shiftwidth",
+		Alias:   "sw",
+		Type:    IntSetting,
+		Scope:   BufferScope,
+		Section: Editor,
+		Option:  ShiftWidth,
+	},
+	{
+		Name:    "expandtab",
+		Alias:   "et",
+		Type:    BoolSetting,
+		Scope:   BufferScope,
+		Section: Editor,
+		Option:  ExpandTab,
+	},
+	{
+		Name:    "taskdate",
+		Type:    BoolSetting,
+		Section: Editor,
+		Option:  TaskDate,
+	},
+	{
+		Name:    "spell",
+		Type:    BoolSetting,
+		Scope:   BufferScope,
+		Section: Editor,
+		Option:  Spell,
+	},
+	{
+		Name:    "spelllang",
+		Alias:   "spl",
+		Type:    StringSetting,
+		Section: Editor,
+		Option:  SpellLang,
+	},
+	{
+		Name:    "syntax",
+		Alias:   "syn",
+		Type:    BoolSetting,
+		Scope:   BufferScope,
+		Section: Editor,
+		Option:  Syntax,
+	},
+	{
+		Name:    "textwidth",
+		Alias:   "tw",
+		Type:    IntSetting,
+		Scope:   BufferScope,
+		Section: Editor,
+		Option:  TextWidth,
+	},
+	{
+		Name:    "wrap",
+		Type:    BoolSetting,
+		Scope:   BufferScope,
+		Section: Editor,
+		Option:  Wrap,
+	},
+	{
+		Name:    "breakindent",
+		Alias:   "bri",
+		Type:    BoolSetting,
+		Scope:   BufferScope,
+		Section: Editor,
+		Option:  BreakIndent,
+	},
+	{
+		Name:    "showbreak",
+		Alias:   "sbr",
+		Type:    StringSetting,
+		Section: Editor,
+		Option:  ShowBreak,
+	},
+	{
+		Name:    "ignorecase",
+		Alias:   "ic",
+		Type:    BoolSetting,
+		Section: Editor,
+		Option:  SearchIgnoreCase,
+	},
+	{
+		Name:    "smartcase",
+		Alias:   "scs",
+		Type:    BoolSetting,
+		Section: Editor,
+		Option:  SearchSmartCase,
+	},
+	{
+		Name:    "regex",
+		Type:    BoolSetting,
+		Section: Editor,
+		Option:  SearchRegex,
+	},
+	{
+		Name:    "indentlines",
+		Type:    BoolSetting,
+		Section: Folders,
+		Option:  IndentLines,
+	},
+	{
+		Name:    "autoopen",
+		Type:    BoolSetting,
+		Section: General,
+		Option:  AutoOpenNewNote,
+	},
+	{
+		Name:    "border",
+		Type:    EnumSetting,
+		Section: Theme,
+		Option:  Border,
+		Values:  []string{"none", "normal", "thick", "block", "rounded", "double"},
+	},
+	{
+		Name:    "timeoutlen",
+		Alias:   "tm",
+		Type:    IntSetting,
+		Section: General,
+		Option:  TimeoutLen,
+	},
+	{
+		Name:    "flushdelay",
+		Type:    IntSetting,
+		Section: General,
+		Option:  FlushDelay,
+	},
+}
+
+// LookupSetting returns the setting with the given name or alias
+func LookupSetting(name string) (Setting, bool) {
+	for _, s := range Settings {
+		if name != "" && (s.Name == name || s.Alias == name) {
+			return s, true
+		}
+	}
+
+	return Setting{}, false
+}
+
+// Parse validates a value for the setting and returns it the way
+// it's stored in the config file
+func (s Setting) Parse(value string) (string, error) {
+	switch s.Type {
+	case BoolSetting:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid argument: %s=%s", s.Name, value)
+		}
+		return strconv.FormatBool(b), nil
+
+	case IntSetting:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < max(s.Min, 0) {
+			return "", fmt.Errorf("number required after =: %s=%s", s.Name, value)
+		}
+		return strconv.Itoa(n), nil
+
+	case EnumSetting:
+		if !slices.Contains(s.Values, value) {
+			return "", fmt.Errorf("invalid argument: %s=%s", s.Name, value)
+		}
+	}
+
+	return value, nil
+}
+
+// Format returns the setting with its value the way `:set` shows it,
+// e.g. `nonumber` or `border=rounded`
+func (s Setting) Format(value string) string {
+	if s.Type == BoolSetting {
+		if value == "true" {
+			return s.Name
+		}
+		return "no" + s.Name
+	}
+
+	return s.Name + "=" + value
+}
+
+// optionKey identifies an option of the config file
+type optionKey struct {
+	section Section
+	option  Option
+}
+
+// Override sets the value of an option for the current session
+// without changing the config file
+func (conf *Config) Override(section Section, option Option, value string) {
+	if conf.overrides == nil {
+		conf.overrides = map[optionKey]string{}
+	}
+
+	conf.overrides[optionKey{section, option}] = value
+}
+
+// PersistSettings returns whether settings changed with `:set`
+// should be written to the config file
+func (conf *Config) PersistSettings() bool {
+	persist, err := conf.Value(General, PersistSettings)
+	return err == nil && persist.GetBool()
+}
+
+// SetFlushDelay sets the delay before changes of the meta infos
+// are written to disk
+func (conf *Config) SetFlushDelay(delay time.Duration) {
+	conf.flushMu.Lock()
+	defer conf.flushMu.Unlock()
+
+	conf.flushDelay = delay
+}
+
+// GetInt returns the value as an integer
+func (v Value) GetInt() (int, error) {
+	n, err := strconv.Atoi(v.Value)
+	if err != nil {
+		return 0, errors.New("not a number: " + v.Value)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestSettingParse(t *testing.T) {
+	tests := []struct {
+		name, value, want string
+		ok                bool
+	}{
+		{"nu", "1", "true", true},
+		{"number", "maybe", "", false},
+		{"ts", "4", "4", true},
+		{"ts", "0", "", false},
+		{"tw", "0", "0", true},
+		{"tm", "-1", "", false},
+		{"border", "rounded", "rounded", true},
+		{"border", "round", "", false},
+	}
+
+	for _, tt := range tests {
+		s, ok := LookupSetting(tt.name)
+		if !ok {
+			t.Fatalf("%s: expected the setting to exist", tt.name)
+		}
+
+		got, err := s.Parse(tt.value)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("%s=%s: expected %q (%v), got %q (%v)", tt.name, tt.value, tt.want, tt.ok, got, err)
+		}
+	}
+
+	if _, ok := LookupSetting(""); ok {
+		t.Error("expected no setting without a name")
+	}
+}
+
+func TestSettingFormat(t *testing.T) {
+	number, _ := LookupSetting("nu")
+	border, _ := LookupSetting("border")
+
+	tests := []struct {
+		setting     Setting
+		value, want string
+	}{
+		{number, "true", "number"},
+		{number, "false", "nonumber"},
+		{border, "rounded", "border=rounded"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.setting.Format(tt.value); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
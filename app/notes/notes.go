@@ -3,6 +3,7 @@ package notes
 import (
 	"errors"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -263,6 +264,36 @@ func CheckPath(path string) string {
 	return path + Ext
 }
 
+// Paths returns the paths of all notes within the given directory and
+// its sub directories in lexical order. Hidden files and directories
+// are skipped, just like config files
+func Paths(root string) ([]string, error) {
+	paths := []string{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path != root && isHidden(d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.IsDir() &&
+			(strings.HasSuffix(path, Ext) || strings.HasSuffix(path, LegacyExt)) {
+
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+
+	return paths, err
+}
+
 // isHidden returns true if the file or directory is hidden
 func isHidden(path string) bool {
 	return path[0] == 46
@@ -3,6 +3,7 @@ package notes_test
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"bellbird-notes/app/notes"
@@ -95,3 +96,39 @@ func TestListFiltersOnlyNotes(t *testing.T) {
 		t.Errorf("Expected 'test_note.txt', got '%s'", notesList[0].Name())
 	}
 }
+
+func TestOpenTasks(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":          "- [ ] first\n- [x] done\ntext\n  1. [ ] nested",
+		"sub/b.txt":      "* [X] done\n- [ ]   second",
+		".hidden/c.txt":  "- [ ] hidden",
+		"config.conf":    "- [ ] config",
+		"sub/plain.text": "- [ ] no note",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tasks, err := notes.OpenTasks(dir)
+	if err != nil {
+		t.Fatalf("OpenTasks failed: %v", err)
+	}
+
+	want := []notes.Task{
+		{Path: filepath.Join(dir, "a.txt"), Line: 1, Text: "first"},
+		{Path: filepath.Join(dir, "a.txt"), Line: 4, Text: "nested"},
+		{Path: filepath.Join(dir, "sub", "b.txt"), Line: 2, Text: "second"},
+	}
+
+	if !slices.Equal(tasks, want) {
+		t.Errorf("expected %v, got %v", want, tasks)
+	}
+}
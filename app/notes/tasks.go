@@ -0,0 +1,85 @@
+package notes
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// taskRegex matches markdown list items with a checkbox, e.g. `- [ ] Task`
+var taskRegex = regexp.MustCompile(`^\s*(?:[-*+]|\d{1,9}[.)])\s+\[([ xX])\](?:\s+(.*))?$`)
+
+// Task is a list item with a checkbox within a note
+type Task struct {
+	// Path is the path of the note the task belongs to
+	Path string
+
+	// Line is the line number of the task, starting at 1
+	Line int
+
+	Text string
+	Done bool
+}
+
+// ParseTask returns the task the given line holds
+func ParseTask(line string) (Task, bool) {
+	m := taskRegex.FindStringSubmatch(line)
+	if m == nil {
+		return Task{}, false
+	}
+
+	return Task{
+		Text: strings.TrimSpace(m[2]),
+		Done: m[1] != " ",
+	}, true
+}
+
+// OpenTasks returns the tasks that aren't done of every note within
+// the given directory and its sub directories. Hidden files and
+// directories are skipped. The tasks are ordered by path and line
+func OpenTasks(root string) ([]Task, error) {
+	paths, err := Paths(root)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []Task{}
+
+	for _, path := range paths {
+		noteTasks, err := openTasksOf(path)
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, noteTasks...)
+	}
+
+	return tasks, nil
+}
+
+// openTasksOf returns the tasks of the note at the given path
+// that aren't done
+func openTasksOf(path string) ([]Task, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tasks := []Task{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		task, ok := ParseTask(scanner.Text())
+
+		if ok && !task.Done {
+			task.Path = path
+			task.Line = line
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, scanner.Err()
+}
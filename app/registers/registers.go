@@ -0,0 +1,356 @@
+package registers
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"bellbird-notes/app/debug"
+	"bellbird-notes/app/utils/clipboard"
+)
+
+const (
+	// Unnamed holds the text of the last yank or delete
+	Unnamed = '"'
+
+	// LastYank holds the text of the last yank
+	LastYank = '0'
+
+	// SmallDelete holds deleted text that is less than a line
+	SmallDelete = '-'
+
+	// BlackHole discards everything that is written to it
+	BlackHole = '_'
+
+	// Clipboard reads from and writes to the system clipboard
+	Clipboard = '+'
+)
+
+// order is the order in which registers are listed
+const order = "\"0123456789abcdefghijklmnopqrstuvwxyz-+"
+
+type Register struct {
+	Name    rune
+	Content string
+
+	// Linewise indicates that the content consists of whole lines
+	// which are pasted below the current line
+	Linewise bool
+
+	// Blockwise indicates that the content is a block selection
+	// whose lines are pasted below each other at the cursor column
+	Blockwise bool
+}
+
+// Type returns a short string representation of the register type
+func (reg Register) Type() string {
+	switch {
+	case reg.Linewise:
+		return "l"
+	case reg.Blockwise:
+		return "b"
+	}
+	return "c"
+}
+
+type Registers struct {
+	registers map[rune]Register
+
+	// selected is the register selected with `"x` which is used
+	// by the next yank, delete or paste. It's 0 if none is selected
+	selected rune
+}
+
+func New() *Registers {
+	return &Registers{
+		registers: make(map[rune]Register),
+	}
+}
+
+// Valid returns whether the given name is a valid register name
+func Valid(name rune) bool {
+	switch {
+	case name == Unnamed,
+		name == SmallDelete,
+		name == BlackHole,
+		name == Clipboard,
+		name >= '0' && name <= '9',
+		name >= 'a' && name <= 'z',
+		name >= 'A' && name <= 'Z':
+		return true
+	}
+	return false
+}
+
+// isNamed returns whether name is one of the registers `a` to `z`
+func isNamed(name rune) bool {
+	return name >= 'a' && name <= 'z'
+}
+
+// Select selects the register the next yank, delete or paste uses.
+// Returns false if the name is not a valid register name
+func (r *Registers) Select(name rune) bool {
+	if !Valid(name) {
+		return false
+	}
+
+	r.selected = name
+	return true
+}
+
+// Deselect resets the register selection
+func (r *Registers) Deselect() {
+	r.selected = 0
+}
+
+// Selected returns the currently selected register or 0 if
+// no register has been selected
+func (r *Registers) Selected() rune {
+	return r.selected
+}
+
+// takeSelected returns the selected register and resets the selection
+func (r *Registers) takeSelected() rune {
+	name := r.selected
+	r.selected = 0
+
+	if name == Unnamed {
+		return 0
+	}
+
+	return name
+}
+
+// Yank stores yanked text in the selected register or, if none
+// is selected, in register `0`.
+// The unnamed register always points to the last yanked text
+func (r *Registers) Yank(content string, linewise bool) {
+	r.yank(Register{Content: content, Linewise: linewise})
+}
+
+// YankBlock stores a yanked block selection just like Yank
+func (r *Registers) YankBlock(content string) {
+	r.yank(Register{Content: content, Blockwise: true})
+}
+
+func (r *Registers) yank(reg Register) {
+	name := r.takeSelected()
+
+	switch name {
+	case BlackHole:
+		return
+	case 0:
+		r.set(LastYank, reg)
+	default:
+		reg = r.write(name, reg)
+	}
+
+	r.set(Unnamed, reg)
+}
+
+// Delete stores deleted or changed text in the selected register.
+// If no register is selected, deleted lines are stored in register `1`
+// shifting the previous contents of `1`-`8` one register up, and
+// deletions within a line are stored in register `-`.
+// The unnamed register always points to the last deleted text
+func (r *Registers) Delete(content string, linewise bool) {
+	r.delete(Register{Content: content, Linewise: linewise})
+}
+
+// DeleteBlock stores a deleted or changed block selection just like Delete
+func (r *Registers) DeleteBlock(content string) {
+	r.delete(Register{Content: content, Blockwise: true})
+}
+
+func (r *Registers) delete(reg Register) {
+	name := r.takeSelected()
+
+	switch {
+	case name == BlackHole:
+		return
+	case name != 0:
+		reg = r.write(name, reg)
+	case reg.Linewise || strings.Contains(reg.Content, "\n"):
+		r.shiftNumbered()
+		r.set('1', reg)
+	default:
+		r.set(SmallDelete, reg)
+	}
+
+	r.set(Unnamed, reg)
+}
+
+// Store writes content to the given register without touching the
+// unnamed register, e.g. a recorded macro.
+// Uppercase names append to the corresponding lowercase register
+func (r *Registers) Store(name rune, content string) {
+	if !Valid(name) || name == BlackHole {
+		return
+	}
+
+	r.write(name, Register{Content: content})
+}
+
+// Get returns the selected register or, if no register is selected,
+// the unnamed register and resets the selection.
+func (r *Registers) Get() (Register, bool) {
+	name := r.takeSelected()
+
+	if name == 0 {
+		name = Unnamed
+	}
+
+	if name == Clipboard {
+		content, err := clipboard.Read()
+		if err != nil {
+			debug.LogDebug(err)
+			return Register{}, false
+		}
+
+		return Register{
+			Name:     Clipboard,
+			Content:  content,
+			Linewise: strings.HasSuffix(content, "\n"),
+		}, content != ""
+	}
+
+	return r.Register(name)
+}
+
+// Register returns the register with the given name.
+// Uppercase names return the corresponding lowercase register
+func (r *Registers) Register(name rune) (Register, bool) {
+	if name >= 'A' && name <= 'Z' {
+		name += 'a' - 'A'
+	}
+
+	reg, ok := r.registers[name]
+	return reg, ok
+}
+
+// All returns all non-empty registers in the order `:registers` lists them
+func (r *Registers) All() []Register {
+	regs := []Register{}
+
+	for _, name := range order {
+		if reg, ok := r.registers[name]; ok && reg.Content != "" {
+			regs = append(regs, reg)
+		}
+	}
+
+	return regs
+}
+
+// write writes to a named or numbered register or the system clipboard.
+// Uppercase names append to the corresponding lowercase register.
+// Returns the register as it has been written
+func (r *Registers) write(name rune, reg Register) Register {
+	if name == Clipboard {
+		if err := clipboard.Write(reg.Content); err != nil {
+			debug.LogDebug(err)
+		}
+	}
+
+	if name >= 'A' && name <= 'Z' {
+		name += 'a' - 'A'
+
+		if prev, ok := r.registers[name]; ok {
+			reg = appendRegister(prev, reg)
+		}
+	}
+
+	r.set(name, reg)
+	return r.registers[name]
+}
+
+func (r *Registers) set(name rune, reg Register) {
+	reg.Name = name
+	r.registers[name] = reg
+}
+
+// shiftNumbered moves the content of the registers `1`-`8`
+// one register up. The content of register `9` is dropped
+func (r *Registers) shiftNumbered() {
+	for name := '9'; name > '1'; name-- {
+		if reg, ok := r.registers[name-1]; ok {
+			r.set(name, reg)
+		}
+	}
+}
+
+// appendRegister appends the content of reg to prev.
+// If either of them is linewise the result is linewise as well
+// and the appended content starts on a new line.
+// Blocks appended to blocks are added as additional lines
+func appendRegister(prev Register, reg Register) Register {
+	content := prev.Content
+
+	if prev.Blockwise && reg.Blockwise {
+		return Register{
+			Content:   content + "\n" + reg.Content,
+			Blockwise: true,
+		}
+	}
+
+	if reg.Linewise && !prev.Linewise {
+		content += "\n"
+	}
+
+	content += reg.Content
+
+	if prev.Linewise && !reg.Linewise {
+		content += "\n"
+	}
+
+	return Register{
+		Content:  content,
+		Linewise: prev.Linewise || reg.Linewise,
+	}
+}
+
+// Serialize returns the named registers `a`-`z` encoded as strings
+// so that they can be stored in the state file.
+// Format: <name><type><quoted content>, e.g. `al"some text\n"`
+func (r *Registers) Serialize() []string {
+	entries := []string{}
+
+	for _, reg := range r.All() {
+		if !isNamed(reg.Name) {
+			continue
+		}
+
+		entries = append(
+			entries,
+			string(reg.Name)+reg.Type()+strconv.Quote(reg.Content),
+		)
+	}
+
+	return entries
+}
+
+// Restore restores named registers from entries created by Serialize.
+// Invalid entries are skipped
+func (r *Registers) Restore(entries []string) {
+	for _, entry := range entries {
+		runes := []rune(entry)
+
+		if len(runes) < 3 ||
+			!isNamed(runes[0]) ||
+			!slices.Contains([]rune{'c', 'l', 'b'}, runes[1]) {
+
+			continue
+		}
+
+		content, err := strconv.Unquote(string(runes[2:]))
+		if err != nil {
+			debug.LogDebug(err)
+			continue
+		}
+
+		r.set(runes[0], Register{
+			Content:   content,
+			Linewise:  runes[1] == 'l',
+			Blockwise: runes[1] == 'b',
+		})
+	}
+}
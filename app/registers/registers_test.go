@@ -0,0 +1,88 @@
+package registers_test
+
+import (
+	"testing"
+
+	"bellbird-notes/app/registers"
+)
+
+func TestYankAndDelete(t *testing.T) {
+	r := registers.New()
+
+	r.Yank("yanked", false)
+	r.Delete("deleted line\n", true)
+	r.Delete("word", false)
+
+	if reg, _ := r.Register('0'); reg.Content != "yanked" {
+		t.Errorf("Expected register 0 to contain 'yanked', got '%s'", reg.Content)
+	}
+
+	if reg, _ := r.Register('1'); reg.Content != "deleted line\n" || !reg.Linewise {
+		t.Errorf("Expected register 1 to contain the deleted line, got '%s'", reg.Content)
+	}
+
+	if reg, _ := r.Register('-'); reg.Content != "word" {
+		t.Errorf("Expected register - to contain 'word', got '%s'", reg.Content)
+	}
+
+	if reg, _ := r.Get(); reg.Content != "word" {
+		t.Errorf("Expected unnamed register to contain 'word', got '%s'", reg.Content)
+	}
+
+	// deleting lines shifts the numbered registers
+	r.Delete("another line\n", true)
+
+	if reg, _ := r.Register('2'); reg.Content != "deleted line\n" {
+		t.Errorf("Expected register 2 to contain the deleted line, got '%s'", reg.Content)
+	}
+}
+
+func TestNamedRegisters(t *testing.T) {
+	r := registers.New()
+
+	r.Select('a')
+	r.Yank("foo", false)
+
+	r.Select('A')
+	r.Yank("bar", false)
+
+	if reg, _ := r.Register('a'); reg.Content != "foobar" {
+		t.Errorf("Expected register a to contain 'foobar', got '%s'", reg.Content)
+	}
+
+	// named registers don't touch register 0
+	if _, ok := r.Register('0'); ok {
+		t.Error("Expected register 0 to be empty")
+	}
+
+	r.Select('_')
+	r.Delete("gone", false)
+
+	if reg, _ := r.Get(); reg.Content != "foobar" {
+		t.Errorf("Expected black hole register to keep unnamed register, got '%s'", reg.Content)
+	}
+
+	if r.Select('!') {
+		t.Error("Expected '!' to be an invalid register")
+	}
+}
+
+func TestSerializeRestore(t *testing.T) {
+	r := registers.New()
+
+	r.Select('a')
+	r.Yank("line|with\nbreak\n", true)
+	r.Yank("not persisted", false)
+
+	restored := registers.New()
+	restored.Restore(r.Serialize())
+
+	reg, ok := restored.Register('a')
+	if !ok || reg.Content != "line|with\nbreak\n" || !reg.Linewise {
+		t.Errorf("Expected register a to be restored, got '%s'", reg.Content)
+	}
+
+	if _, ok := restored.Register('0'); ok {
+		t.Error("Expected only named registers to be restored")
+	}
+}
@@ -0,0 +1,356 @@
+package spell
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// affixes holds the rules of a hunspell `.aff` file that are needed to
+// expand the words of a `.dic` file. Only the basic prefix and suffix
+// rules are supported, compounding and twofold affixes are ignored
+type affixes struct {
+	// flagType is `long`, `num` or empty for single character flags
+	flagType string
+
+	prefixes map[string][]affixRule
+	suffixes map[string][]affixRule
+
+	// forbidden and needAffix are the flags of FORBIDDENWORD
+	// and NEEDAFFIX
+	forbidden, needAffix string
+
+	// latin1 is true if the files are ISO-8859 encoded instead of UTF-8
+	latin1 bool
+}
+
+// affixRule is a PFX or SFX rule of an `.aff` file
+type affixRule struct {
+	// strip is removed from the word before add is added
+	strip, add string
+
+	condition []charClass
+
+	// cross is true if the rule can be combined with
+	// a rule of the other kind
+	cross bool
+}
+
+// charClass is a single character of an affix condition,
+// e.g. `a`, `.` or `[^aeiou]`
+type charClass struct {
+	any, negated bool
+	runes        string
+}
+
+func (c charClass) matches(r rune) bool {
+	if c.any {
+		return true
+	}
+	return strings.ContainsRune(c.runes, r) != c.negated
+}
+
+// parseCondition parses the condition of an affix rule
+func parseCondition(cond string) []charClass {
+	classes := []charClass{}
+
+	if cond == "." {
+		return classes
+	}
+
+	runes := []rune(cond)
+
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.':
+			classes = append(classes, charClass{any: true})
+
+		case '[':
+			class := charClass{}
+			i++
+
+			if i < len(runes) && runes[i] == '^' {
+				class.negated = true
+				i++
+			}
+
+			start := i
+			for i < len(runes) && runes[i] != ']' {
+				i++
+			}
+
+			class.runes = string(runes[start:i])
+			classes = append(classes, class)
+
+		default:
+			classes = append(classes, charClass{runes: string(runes[i])})
+		}
+	}
+
+	return classes
+}
+
+// applies returns whether the rule can be applied to the given word
+func (rule affixRule) applies(word []rune, prefix bool) bool {
+	n := len(rule.condition)
+	str := string(word)
+
+	if len(word) < n || len(str) <= len(rule.strip) {
+		return false
+	}
+
+	if prefix && !strings.HasPrefix(str, rule.strip) ||
+		!prefix && !strings.HasSuffix(str, rule.strip) {
+
+		return false
+	}
+
+	// prefix conditions match the beginning of the word,
+	// suffix conditions its end
+	offset := 0
+	if !prefix {
+		offset = len(word) - n
+	}
+
+	for i, class := range rule.condition {
+		if !class.matches(word[offset+i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// apply returns the word with the prefix or suffix of the rule
+func (rule affixRule) apply(word string, prefix bool) string {
+	if prefix {
+		return rule.add + strings.TrimPrefix(word, rule.strip)
+	}
+	return strings.TrimSuffix(word, rule.strip) + rule.add
+}
+
+// loadAffixes reads the hunspell `.aff` file at the given path
+func loadAffixes(path string) (*affixes, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	aff := &affixes{
+		prefixes: map[string][]affixRule{},
+		suffixes: map[string][]affixRule{},
+	}
+
+	// the cross product flag of every affix flag
+	cross := map[string]bool{}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if aff.latin1 {
+			line = decodeLatin1(line)
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch fields[0] {
+		case "SET":
+			aff.latin1 = strings.HasPrefix(strings.ToUpper(fields[1]), "ISO8859")
+		case "FLAG":
+			aff.flagType = fields[1]
+		case "FORBIDDENWORD":
+			aff.forbidden = fields[1]
+		case "NEEDAFFIX":
+			aff.needAffix = fields[1]
+
+		case "PFX", "SFX":
+			rules := aff.suffixes
+			if fields[0] == "PFX" {
+				rules = aff.prefixes
+			}
+
+			flag := fields[1]
+
+			// the header of a group of rules, e.g. `SFX D Y 4`
+			if len(fields) == 4 {
+				if _, err := strconv.Atoi(fields[3]); err == nil {
+					cross[flag] = fields[2] == "Y"
+					continue
+				}
+			}
+
+			if len(fields) < 4 {
+				continue
+			}
+
+			strip, add := fields[2], fields[3]
+			if strip == "0" {
+				strip = ""
+			}
+
+			// continuation flags of twofold affixes are ignored
+			add, _, _ = strings.Cut(add, "/")
+			if add == "0" {
+				add = ""
+			}
+
+			cond := "."
+			if len(fields) > 4 {
+				cond = fields[4]
+			}
+
+			rules[flag] = append(rules[flag], affixRule{
+				strip:     strip,
+				add:       add,
+				condition: parseCondition(cond),
+				cross:     cross[flag],
+			})
+		}
+	}
+
+	return aff, scanner.Err()
+}
+
+// parseFlags splits the flags of a word of a `.dic` file
+func (aff *affixes) parseFlags(flags string) []string {
+	switch aff.flagType {
+	case "long":
+		runes := []rune(flags)
+		parsed := make([]string, 0, len(runes)/2)
+
+		for i := 0; i+1 < len(runes); i += 2 {
+			parsed = append(parsed, string(runes[i:i+2]))
+		}
+
+		return parsed
+
+	case "num":
+		return strings.Split(flags, ",")
+	}
+
+	parsed := make([]string, 0, utf8.RuneCountInString(flags))
+	for _, r := range flags {
+		parsed = append(parsed, string(r))
+	}
+
+	return parsed
+}
+
+// expand returns the word and every form the affixes of its
+// flags create. Returns false if the word is forbidden
+func (aff *affixes) expand(word string, flags []string) ([]string, bool) {
+	words := []string{}
+	needAffix := false
+
+	for _, flag := range flags {
+		switch {
+		case flag == "":
+			continue
+		case flag == aff.forbidden:
+			return nil, false
+		case flag == aff.needAffix:
+			needAffix = true
+		}
+	}
+
+	if !needAffix {
+		words = append(words, word)
+	}
+
+	runes := []rune(word)
+	crossPrefixes := []affixRule{}
+
+	for _, flag := range flags {
+		for _, rule := range aff.prefixes[flag] {
+			if rule.applies(runes, true) {
+				words = append(words, rule.apply(word, true))
+
+				if rule.cross {
+					crossPrefixes = append(crossPrefixes, rule)
+				}
+			}
+		}
+	}
+
+	for _, flag := range flags {
+		for _, rule := range aff.suffixes[flag] {
+			if !rule.applies(runes, false) {
+				continue
+			}
+
+			suffixed := rule.apply(word, false)
+			words = append(words, suffixed)
+
+			if !rule.cross {
+				continue
+			}
+
+			for _, prefix := range crossPrefixes {
+				if prefix.applies([]rune(suffixed), true) {
+					words = append(words, prefix.apply(suffixed, true))
+				}
+			}
+		}
+	}
+
+	return words, true
+}
+
+// readDic reads the words of a hunspell `.dic` file and passes them
+// with their flags to the given function
+func readDic(r io.Reader, latin1 bool, fn func(word, flags string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	first := true
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if latin1 {
+			line = decodeLatin1(line)
+		}
+
+		// the first line holds the approximate number of words
+		if first {
+			first = false
+			if _, err := strconv.Atoi(strings.TrimSpace(line)); err == nil {
+				continue
+			}
+		}
+
+		// morphological fields follow after white space
+		entry, _, _ := strings.Cut(strings.TrimSpace(line), "\t")
+		entry, _, _ = strings.Cut(entry, " ")
+
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		word, flags, _ := strings.Cut(entry, "/")
+		fn(word, flags)
+	}
+
+	return scanner.Err()
+}
+
+// decodeLatin1 converts an ISO-8859-1 encoded string to UTF-8
+func decodeLatin1(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,473 @@
+// Package spell checks the spelling of words offline with local
+// dictionaries. Dictionaries are hunspell `.dic` files, optionally
+// with their `.aff` file, or plain word lists with one word per line
+package spell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// GoodWordsFile is the name of the file within the dictionary
+// directory that holds the words added with `zg` and `zw`.
+// Words that are marked as wrong start with `!`
+const GoodWordsFile = "good.words"
+
+// maxCacheSize is the number of lines whose misspelled words are
+// remembered before the cache is cleared
+const maxCacheSize = 10000
+
+// Dictionary holds the words of the loaded dictionaries
+// and the good words of the user
+type Dictionary struct {
+	words map[string]bool
+
+	// good and bad are the words that have been added
+	// to the good words file
+	good, bad map[string]bool
+
+	goodWordsPath string
+
+	// alphabet are the lower case letters of all words
+	alphabet []rune
+
+	// cache holds the misspelled words of the lines checked so far
+	cache map[string][][2]int
+}
+
+// Load loads the dictionaries of the given languages from the given
+// directory. A language loads the files named like it, e.g. `en` loads
+// `en.dic` and `en_GB.dic` with their `.aff` files and the word list
+// `en.txt`. The good words file of the directory is loaded as well
+func Load(dir string, langs []string) (*Dictionary, error) {
+	d := &Dictionary{
+		words:         map[string]bool{},
+		good:          map[string]bool{},
+		bad:           map[string]bool{},
+		goodWordsPath: filepath.Join(dir, GoodWordsFile),
+		cache:         map[string][][2]int{},
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	loaded := false
+
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		base := strings.TrimSuffix(name, ext)
+
+		if entry.IsDir() || !matchesLang(base, langs) {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+
+		switch ext {
+		case ".dic":
+			err = d.loadDic(path, filepath.Join(dir, base+".aff"))
+		case ".txt":
+			err = d.loadWordList(path, d.words)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		loaded = true
+	}
+
+	if !loaded {
+		return nil, fmt.Errorf(
+			"no dictionary for %s in %s",
+			strings.Join(langs, ","),
+			dir,
+		)
+	}
+
+	if err := d.loadGoodWords(); err != nil {
+		return nil, err
+	}
+
+	d.updateAlphabet()
+
+	return d, nil
+}
+
+// matchesLang returns whether the name of a dictionary file
+// belongs to one of the given languages
+func matchesLang(name string, langs []string) bool {
+	for _, lang := range langs {
+		if strings.EqualFold(name, lang) ||
+			strings.HasPrefix(strings.ToLower(name), strings.ToLower(lang)+"_") ||
+			strings.HasPrefix(strings.ToLower(name), strings.ToLower(lang)+"-") {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadDic loads the words of a hunspell dictionary. Words are expanded
+// with the affixes of the `.aff` file if it exists
+func (d *Dictionary) loadDic(dicPath, affPath string) error {
+	aff, err := loadAffixes(affPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		aff = nil
+	}
+
+	file, err := os.Open(dicPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	latin1 := aff != nil && aff.latin1
+
+	return readDic(file, latin1, func(word, flags string) {
+		if aff == nil || flags == "" {
+			d.words[word] = true
+			return
+		}
+
+		// forbidden words are left out
+		words, ok := aff.expand(word, aff.parseFlags(flags))
+		if !ok {
+			return
+		}
+
+		for _, w := range words {
+			d.words[w] = true
+		}
+	})
+}
+
+// loadWordList adds the words of a file with one word per line
+// to the given set. Lines starting with `#` are ignored
+func (d *Dictionary) loadWordList(path string, words map[string]bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+
+		if word != "" && !strings.HasPrefix(word, "#") {
+			words[normalize(word)] = true
+		}
+	}
+
+	return scanner.Err()
+}
+
+// loadGoodWords loads the good words file if it exists
+func (d *Dictionary) loadGoodWords() error {
+	words := map[string]bool{}
+
+	err := d.loadWordList(d.goodWordsPath, words)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for word := range words {
+		if bad, ok := strings.CutPrefix(word, "!"); ok {
+			d.bad[bad] = true
+		} else {
+			d.good[word] = true
+		}
+	}
+
+	return nil
+}
+
+// updateAlphabet collects the letters suggestions are made of
+func (d *Dictionary) updateAlphabet() {
+	letters := map[rune]bool{}
+
+	for word := range d.words {
+		for _, r := range strings.ToLower(word) {
+			if unicode.IsLetter(r) {
+				letters[r] = true
+			}
+		}
+	}
+
+	d.alphabet = make([]rune, 0, len(letters))
+	for r := range letters {
+		d.alphabet = append(d.alphabet, r)
+	}
+
+	slices.Sort(d.alphabet)
+}
+
+// GoodWordsPath returns the path of the good words file
+func (d *Dictionary) GoodWordsPath() string {
+	return d.goodWordsPath
+}
+
+// Check returns whether the given word is spelled correctly.
+// Words of the dictionary match in title case and upper case as well,
+// e.g. `hello` matches `Hello` and `HELLO`, `Paris` matches `PARIS`
+func (d *Dictionary) Check(word string) bool {
+	word = normalize(word)
+
+	if d.bad[word] {
+		return false
+	}
+
+	if d.known(word) {
+		return true
+	}
+
+	lower := strings.ToLower(word)
+
+	switch caseOf(word) {
+	case titleCase:
+		return !d.bad[lower] && d.known(lower)
+	case upperCase:
+		title := toTitle(lower)
+		return !d.bad[lower] && d.known(lower) ||
+			!d.bad[title] && d.known(title)
+	}
+
+	return false
+}
+
+// known returns whether the word is part of the dictionary
+// or the good words
+func (d *Dictionary) known(word string) bool {
+	return d.words[word] || d.good[word]
+}
+
+// Misspelled returns the ranges of the misspelled words of the line
+func (d *Dictionary) Misspelled(line []rune) [][2]int {
+	key := string(line)
+
+	if ranges, ok := d.cache[key]; ok {
+		return ranges
+	}
+
+	var ranges [][2]int
+
+	for _, r := range Words(line) {
+		if !d.Check(string(line[r[0]:r[1]])) {
+			ranges = append(ranges, r)
+		}
+	}
+
+	if len(d.cache) >= maxCacheSize {
+		clear(d.cache)
+	}
+	d.cache[key] = ranges
+
+	return ranges
+}
+
+// AddGood adds a word to the good words file
+func (d *Dictionary) AddGood(word string) error {
+	word = normalize(word)
+
+	delete(d.bad, word)
+	d.good[word] = true
+
+	return d.saveGoodWords()
+}
+
+// AddBad removes a word from the good words file. If the word is
+// still part of a dictionary it's marked as wrong in the file instead
+func (d *Dictionary) AddBad(word string) error {
+	word = normalize(word)
+
+	delete(d.good, word)
+	if d.Check(word) {
+		d.bad[word] = true
+	}
+
+	return d.saveGoodWords()
+}
+
+// saveGoodWords writes the good words and the words marked as wrong
+// to the good words file
+func (d *Dictionary) saveGoodWords() error {
+	clear(d.cache)
+
+	lines := make([]string, 0, len(d.good)+len(d.bad))
+
+	for word := range d.good {
+		lines = append(lines, word)
+	}
+
+	for word := range d.bad {
+		lines = append(lines, "!"+word)
+	}
+
+	slices.Sort(lines)
+
+	if err := os.MkdirAll(filepath.Dir(d.goodWordsPath), 0755); err != nil {
+		return err
+	}
+
+	content := strings.Join(lines, "\n") + "\n"
+
+	return os.WriteFile(d.goodWordsPath, []byte(content), 0644)
+}
+
+// normalize replaces typographic apostrophes
+func normalize(word string) string {
+	return strings.ReplaceAll(word, "’", "'")
+}
+
+type wordCase int
+
+const (
+	lowerCase wordCase = iota
+	titleCase
+	upperCase
+	mixedCase
+)
+
+// caseOf returns the capitalisation of a word
+func caseOf(word string) wordCase {
+	upper, lower := 0, 0
+	firstUpper := false
+
+	for i, r := range []rune(word) {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+			firstUpper = firstUpper || i == 0
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return lowerCase
+	case lower == 0:
+		return upperCase
+	case upper == 1 && firstUpper:
+		return titleCase
+	}
+
+	return mixedCase
+}
+
+// toTitle returns the word with its first letter in upper case
+func toTitle(word string) string {
+	runes := []rune(word)
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
+
+// isWordRune returns whether a rune can be part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) ||
+		unicode.IsMark(r) ||
+		unicode.IsDigit(r) ||
+		r == '_' || r == '\'' || r == '’'
+}
+
+// Words returns the ranges of the words of a line that are spell
+// checked. Words with digits or underscores, links, e-mail addresses
+// and inline code are skipped
+func Words(line []rune) [][2]int {
+	var words [][2]int
+	inCode := false
+
+	for i := 0; i < len(line); {
+		r := line[i]
+
+		if r == '`' {
+			inCode = !inCode
+			i++
+			continue
+		}
+
+		if inCode || unicode.IsSpace(r) {
+			i++
+			continue
+		}
+
+		// the field of non white space the word belongs to
+		end := i
+		for end < len(line) && !unicode.IsSpace(line[end]) && line[end] != '`' {
+			end++
+		}
+
+		field := string(line[i:end])
+		if strings.Contains(field, "://") ||
+			strings.HasPrefix(field, "www.") ||
+			strings.Contains(field, "@") {
+
+			i = end
+			continue
+		}
+
+		for i < end {
+			if !isWordRune(line[i]) {
+				i++
+				continue
+			}
+
+			start := i
+			skip := false
+
+			for i < end && isWordRune(line[i]) {
+				skip = skip || unicode.IsDigit(line[i]) || line[i] == '_'
+				i++
+			}
+
+			// apostrophes only belong to the word between letters
+			s, e := start, i
+			for s < e && isApostrophe(line[s]) {
+				s++
+			}
+			for e > s && isApostrophe(line[e-1]) {
+				e--
+			}
+
+			if !skip && s < e {
+				words = append(words, [2]int{s, e})
+			}
+		}
+	}
+
+	return words
+}
+
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
+// WordAt returns the range of the word at the given column
+func WordAt(line []rune, col int) ([2]int, bool) {
+	for _, r := range Words(line) {
+		if col >= r[0] && col < r[1] {
+			return r, true
+		}
+	}
+
+	return [2]int{}, false
+}
@@ -0,0 +1,96 @@
+package spell
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+}
+
+func TestDictionary(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "en_GB.aff"), `SET UTF-8
+
+PFX U Y 1
+PFX U 0 un .
+
+SFX D Y 3
+SFX D 0 d e
+SFX D y ied [^aeiou]y
+SFX D 0 ed [^ey]
+
+SFX S N 1
+SFX S 0 s .
+`)
+	writeFile(t, filepath.Join(dir, "en_GB.dic"), "5\nhello/S\ntry/D\nlock/DU\nParis\ndon't\n")
+	writeFile(t, filepath.Join(dir, "en.txt"), "# words\nnote\n")
+	writeFile(t, filepath.Join(dir, "de.txt"), "hallo\n")
+
+	d, err := Load(dir, []string{"en"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	tests := map[string]bool{
+		"hello":    true,
+		"hellos":   true,
+		"Hello":    true,
+		"HELLO":    true,
+		"hELLO":    false,
+		"tried":    true,
+		"tryed":    false,
+		"unlocked": true,
+		"unlock":   true,
+		"Paris":    true,
+		"PARIS":    true,
+		"paris":    false,
+		"don’t":    true,
+		"note":     true,
+		"hallo":    false,
+	}
+
+	for word, want := range tests {
+		if got := d.Check(word); got != want {
+			t.Errorf("Check(%q): expected %v, got %v", word, want, got)
+		}
+	}
+
+	line := []rune("Helo `helo` http://helo.org 'tryed' note2 wrld")
+	want := [][2]int{{0, 4}, {29, 34}, {42, 46}}
+
+	if got := d.Misspelled(line); !slices.Equal(got, want) {
+		t.Errorf("expected misspelled words at %v, got %v", want, got)
+	}
+
+	if got := d.Suggest("Helo", 3); len(got) == 0 || got[0] != "Hello" {
+		t.Errorf("expected Hello to be suggested first, got %v", got)
+	}
+
+	if err := d.AddGood("wrld"); err != nil {
+		t.Fatalf("AddGood failed: %v", err)
+	}
+
+	if err := d.AddBad("note"); err != nil {
+		t.Fatalf("AddBad failed: %v", err)
+	}
+
+	d, err = Load(dir, []string{"en"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if !d.Check("wrld") || d.Check("note") {
+		t.Error("expected the good words file to be loaded")
+	}
+
+	if _, err := Load(dir, []string{"fr"}); err == nil {
+		t.Error("expected an error for a missing dictionary")
+	}
+}
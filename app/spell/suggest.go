@@ -0,0 +1,150 @@
+package spell
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxEditsLength is the length of the longest word whose suggestions
+// may be two edits away. Longer words would take too long
+const maxEditsLength = 16
+
+// Suggest returns up to n correctly spelled words that are similar to
+// the given word, the most similar first. The suggestions keep the
+// capitalisation of the word
+func (d *Dictionary) Suggest(word string, n int) []string {
+	word = normalize(word)
+	lower := strings.ToLower(word)
+	wc := caseOf(word)
+
+	seen := map[string]bool{}
+	suggestions := []string{}
+
+	add := func(candidates map[string]bool) {
+		found := []string{}
+
+		for candidate := range candidates {
+			if seen[candidate] {
+				continue
+			}
+			seen[candidate] = true
+
+			if s, ok := d.correct(candidate, wc); ok && s != word {
+				found = append(found, s)
+			}
+		}
+
+		slices.SortFunc(found, func(a, b string) int {
+			return compareSuggestions(lower, a, b)
+		})
+
+		suggestions = append(suggestions, found...)
+	}
+
+	edits := d.edits(lower)
+	add(edits)
+
+	if len(suggestions) < n && utf8.RuneCountInString(lower) <= maxEditsLength {
+		edits2 := map[string]bool{}
+
+		for edit := range edits {
+			for e := range d.edits(edit) {
+				edits2[e] = true
+			}
+		}
+
+		add(edits2)
+	}
+
+	return suggestions[:min(n, len(suggestions))]
+}
+
+// correct returns the lower case candidate in the capitalisation of
+// the misspelled word if it's spelled correctly
+func (d *Dictionary) correct(candidate string, wc wordCase) (string, bool) {
+	switch wc {
+	case titleCase:
+		candidate = toTitle(candidate)
+	case upperCase:
+		candidate = strings.ToUpper(candidate)
+	}
+
+	if d.Check(candidate) {
+		return candidate, true
+	}
+
+	// proper nouns are only known in title case
+	title := toTitle(candidate)
+	if wc != upperCase && d.Check(title) {
+		return title, true
+	}
+
+	return "", false
+}
+
+// edits returns every word that is one deletion, transposition,
+// replacement or insertion away from the given word
+func (d *Dictionary) edits(word string) map[string]bool {
+	runes := []rune(word)
+	edits := map[string]bool{}
+
+	for i := range len(runes) + 1 {
+		before, after := runes[:i], runes[i:]
+
+		if len(after) > 0 {
+			edits[string(before)+string(after[1:])] = true
+		}
+
+		if len(after) > 1 {
+			edits[string(before)+string(after[1])+string(after[0])+string(after[2:])] = true
+		}
+
+		for _, r := range d.alphabet {
+			if len(after) > 0 && r != after[0] {
+				edits[string(before)+string(r)+string(after[1:])] = true
+			}
+
+			edits[string(before)+string(r)+string(after)] = true
+		}
+	}
+
+	delete(edits, word)
+
+	return edits
+}
+
+// compareSuggestions orders suggestions by whether they start like the
+// misspelled word, then by the difference of their lengths
+func compareSuggestions(word, a, b string) int {
+	if sa, sb := sameStart(word, a), sameStart(word, b); sa != sb {
+		if sa {
+			return -1
+		}
+		return 1
+	}
+
+	n := utf8.RuneCountInString(word)
+	da := abs(utf8.RuneCountInString(a) - n)
+	db := abs(utf8.RuneCountInString(b) - n)
+
+	if da != db {
+		return da - db
+	}
+
+	return strings.Compare(a, b)
+}
+
+// sameStart returns whether both words start with the same letter
+func sameStart(word, suggestion string) bool {
+	w, _ := utf8.DecodeRuneInString(word)
+	s, _ := utf8.DecodeRuneInString(strings.ToLower(suggestion))
+	return w == s
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
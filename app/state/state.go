@@ -5,7 +5,9 @@ import (
 	"bellbird-notes/app/debug"
 	"bellbird-notes/app/utils"
 	"bufio"
+	"io"
 	"os"
+	"slices"
 	"strings"
 	"time"
 )
@@ -16,12 +18,14 @@ const (
 	Search HistoryType = iota
 	Command
 	Mark
+	Register
 )
 
 var historyTypes = map[HistoryType]string{
-	Search:  "SEARCH",
-	Command: "CMD",
-	Mark:    "MARK",
+	Search:   "SEARCH",
+	Command:  "CMD",
+	Mark:     "MARK",
+	Register: "REG",
 }
 
 func (t HistoryType) String() string {
@@ -137,6 +141,20 @@ func (state *State) Append(entry StateEntry) {
 	state.curIndex = len(state.entries) - 1
 }
 
+// ReplaceEntries replaces all entries of the given HistoryType
+// with new entries of the given contents.
+func (state *State) ReplaceEntries(st HistoryType, contents []string) {
+	state.entries = slices.DeleteFunc(state.entries, func(entry StateEntry) bool {
+		return entry.historyType == st
+	})
+
+	for _, content := range contents {
+		state.entries = append(state.entries, NewEntry(st, content))
+	}
+
+	state.curIndex = len(state.entries) - 1
+}
+
 // Read loads history entries from the state file.
 func (state *State) Read() error {
 	file, err := os.OpenFile(state.filePath, os.O_RDONLY, 0644)
@@ -149,32 +167,49 @@ func (state *State) Read() error {
 	reader := bufio.NewReader(file)
 
 	for {
-		line, err := reader.ReadSlice('\n')
-		if err != nil {
-			if err.Error() == "EOF" {
-				break
-			}
+		// entries like registers may be longer than the buffer of the
+		// reader, so unlike ReadSlice, ReadString reads the whole line
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
 		}
 
-		ln := strings.Split(string(line), "|")
-
-		var historyType HistoryType
-		for hisType, str := range historyTypes {
-			if ln[0] == str {
-				historyType = hisType
-			}
+		if entry, ok := parseEntry(line); ok {
+			state.entries = append(state.entries, entry)
 		}
 
-		state.entries = append(state.entries, StateEntry{
-			historyType: historyType,
-			timestamp:   ln[1],
-			content:     strings.TrimSuffix(ln[2], "\n"),
-		})
+		if err == io.EOF {
+			break
+		}
 	}
 
 	return nil
 }
 
+// parseEntry parses a line of the state file.
+// TYPE|TIMESTAMP|CONTENT
+func parseEntry(line string) (StateEntry, bool) {
+	// the content may contain the separator as well
+	ln := strings.SplitN(line, "|", 3)
+
+	if len(ln) < 3 {
+		return StateEntry{}, false
+	}
+
+	var historyType HistoryType
+	for hisType, str := range historyTypes {
+		if ln[0] == str {
+			historyType = hisType
+		}
+	}
+
+	return StateEntry{
+		historyType: historyType,
+		timestamp:   ln[1],
+		content:     strings.TrimSuffix(ln[2], "\n"),
+	}, true
+}
+
 // Write saves the current entries to the state file.
 // TYPE|TIMESTAMP|CONTENT
 func (state *State) Write() error {
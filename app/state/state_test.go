@@ -0,0 +1,59 @@
+package state_test
+
+import (
+	"strings"
+	"testing"
+
+	"bellbird-notes/app/registers"
+	"bellbird-notes/app/state"
+	"bellbird-notes/internal/testutil"
+)
+
+func TestRegistersRoundTrip(t *testing.T) {
+	testutil.TempHome(t)
+
+	// both are longer than the buffer used to read the state file
+	yanked := strings.Repeat("a long yanked line\n", 300)
+	macro := strings.Repeat("ciwfoo<esc>j", 500)
+
+	regs := registers.New()
+	regs.Select('a')
+	regs.Yank(yanked, true)
+	regs.Store('q', macro)
+
+	st := state.New()
+	st.ReplaceEntries(state.Register, regs.Serialize())
+	st.Append(state.NewEntry(state.Command, "set nu"))
+
+	if err := st.Write(); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	st = state.New()
+	if err := st.Read(); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	contents := []string{}
+	for _, entry := range st.Entries(state.Register) {
+		contents = append(contents, entry.Content())
+	}
+
+	restored := registers.New()
+	restored.Restore(contents)
+
+	if reg, _ := restored.Register('a'); reg.Content != yanked || !reg.Linewise {
+		t.Errorf("expected register a to be restored, got %d bytes (linewise %v)",
+			len(reg.Content), reg.Linewise)
+	}
+
+	if reg, _ := restored.Register('q'); reg.Content != macro {
+		t.Errorf("expected the macro in register q to be restored, got %d bytes",
+			len(reg.Content))
+	}
+
+	// the entries after the long ones are still read
+	if commands := st.Commands(); len(commands) != 1 || commands[0].Content() != "set nu" {
+		t.Errorf("expected the command history to be restored, got %v", commands)
+	}
+}
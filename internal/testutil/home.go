@@ -0,0 +1,27 @@
+// Package testutil contains helpers shared by the tests of several packages
+package testutil
+
+import (
+	"os"
+	"testing"
+)
+
+// TempHome points the home and the config directory to a new
+// temporary directory for the duration of the test. This keeps the
+// config and the meta infos of the notes away from the user's files
+// and the ones of tests running in other packages.
+// The meta infos may still be written after the test, so errors
+// removing the directory are ignored unlike with t.TempDir
+func TempHome(t *testing.T) string {
+	t.Helper()
+
+	home, err := os.MkdirTemp("", "bellbird-notes")
+	if err != nil {
+		t.Fatalf("MkdirTemp failed: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(home) })
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+
+	return home
+}
@@ -2,8 +2,10 @@ package application
 
 import (
 	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
 
 	"bellbird-notes/app/config"
 	"bellbird-notes/app/state"
@@ -13,10 +15,13 @@ import (
 	noteslist "bellbird-notes/tui/components/notes_list"
 	"bellbird-notes/tui/components/overlay"
 	"bellbird-notes/tui/components/statusbar"
+	suggestionlist "bellbird-notes/tui/components/suggestion_list"
+	tasklist "bellbird-notes/tui/components/task_list"
 	"bellbird-notes/tui/keyinput"
 	"bellbird-notes/tui/message"
 	"bellbird-notes/tui/mode"
 	"bellbird-notes/tui/shared"
+	"bellbird-notes/tui/theme"
 )
 
 type FocusController interface {
@@ -45,6 +50,12 @@ type App struct {
 	// BufferList holds and manages all open buffers.
 	BufferList *bufferlist.BufferList
 
+	// TaskList shows the open tasks of all notes
+	TaskList *tasklist.TaskList
+
+	// SuggestionList shows the spelling suggestions of `z=`
+	SuggestionList *suggestionlist.SuggestionList
+
 	// StatusBar displays current status information at the bottom of the screen.
 	StatusBar *statusbar.StatusBar
 
@@ -63,35 +74,102 @@ type App struct {
 	focus FocusController
 
 	CurrentOverlay *overlay.Overlay
+
+	// infoOverlay displays read-only information like the output of
+	// `:registers` and is closed with the next key press
+	infoOverlay *overlay.Overlay
 }
 
 func New(fc FocusController) *App {
 	conf := config.New()
-	state := state.New()
+	st := state.New()
 
-	state.Read()
+	st.Read()
 
 	app := App{
-		Conf:         conf,
-		State:        state,
-		Mode:         &mode.ModeInstance{Current: mode.Normal},
-		DirTree:      directorytree.New("Folders", conf),
-		NotesList:    noteslist.New("Notes", conf),
-		Editor:       editor.New("Editor", conf),
-		BufferList:   bufferlist.New("BufferList", conf),
-		StatusBar:    statusbar.New(),
-		Buffers:      make(editor.Buffers, 0),
-		CurrColFocus: 1,
-		focus:        fc,
+		Conf:           conf,
+		State:          st,
+		Mode:           &mode.ModeInstance{Current: mode.Normal},
+		DirTree:        directorytree.New("Folders", conf),
+		NotesList:      noteslist.New("Notes", conf),
+		Editor:         editor.New("Editor", conf),
+		BufferList:     bufferlist.New("BufferList", conf),
+		TaskList:       tasklist.New("Tasks", conf),
+		SuggestionList: suggestionlist.New("Suggestions", conf),
+		StatusBar:      statusbar.New(),
+		Buffers:        make(editor.Buffers, 0),
+		CurrColFocus:   1,
+		focus:          fc,
 	}
 
-	app.StatusBar.State = state
+	app.StatusBar.State = st
+	app.Editor.Registers.Restore(contents(st.Entries(state.Register)))
+	app.Editor.Marks.Restore(contents(st.Entries(state.Mark)))
 
 	conf.CleanMetaFile()
 
 	return &app
 }
 
+// contents returns the contents of the given state entries
+func contents(entries []state.StateEntry) []string {
+	c := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		c = append(c, entry.Content())
+	}
+	return c
+}
+
+// WriteState writes the session state, like the command history,
+// the named registers and the marks, to the state file
+func (app *App) WriteState() error {
+	app.State.ReplaceEntries(state.Register, app.Editor.Registers.Serialize())
+	app.State.ReplaceEntries(state.Mark, app.Editor.Marks.Serialize())
+	return app.State.Write()
+}
+
+// ShowInfoOverlay displays the given lines in an overlay that is closed
+// with the next key press
+func (app *App) ShowInfoOverlay(title string, lines []string) {
+	t := theme.New(app.Conf)
+	termW, _ := theme.TerminalSize()
+	width := termW * 2 / 3
+
+	body := lipgloss.NewStyle().
+		Border(t.BorderStyle()).
+		BorderTop(false).
+		BorderForeground(theme.ColourBorderFocused).
+		Padding(0, 1).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		t.Header(title, width, true),
+		body,
+	)
+
+	ov := &overlay.Overlay{}
+	ov.SetPosition(ov.CalculatePosition(width))
+	ov.SetContent(content)
+
+	app.infoOverlay = ov
+	app.CurrentOverlay = ov
+}
+
+// InfoOverlayVisible returns whether an info overlay is displayed
+func (app *App) InfoOverlayVisible() bool {
+	return app.infoOverlay != nil && app.CurrentOverlay == app.infoOverlay
+}
+
+// CloseInfoOverlay closes the info overlay
+func (app *App) CloseInfoOverlay() {
+	if app.InfoOverlayVisible() {
+		app.CurrentOverlay = nil
+	}
+	app.infoOverlay = nil
+}
+
 // restoreState restores the state of the TUI from the last session
 func (app *App) RestoreState() {
 	currComp, err := app.Conf.MetaValue("", config.CurrentComponent)
@@ -204,6 +282,14 @@ func (app *App) UpdateComponents(msg tea.Msg) []tea.Cmd {
 		cmds = append(cmds, cmd)
 	}
 
+	if _, cmd := app.TaskList.Update(msg); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
+	if _, cmd := app.SuggestionList.Update(msg); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
 	// collect dirty buffers
 	app.NotesList.DirtyBuffers = app.Editor.DirtyBuffers()
 
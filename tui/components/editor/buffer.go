@@ -1,6 +1,7 @@
 package editor
 
 import (
+	"bellbird-notes/app/config"
 	"bellbird-notes/app/utils"
 	"bellbird-notes/tui/components/textarea"
 	"net/url"
@@ -45,6 +46,10 @@ type Buffer struct {
 
 	// IsScratch indicates whether the buffer is a temporary scratch buffer
 	IsScratch bool
+
+	// Settings holds the values of buffer-local settings set with
+	// `:setlocal`, they take precedence over the config file
+	Settings map[config.Option]string
 }
 
 // Name returns the name of the buffer without its suffix.
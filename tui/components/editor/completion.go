@@ -0,0 +1,355 @@
+package editor
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"bellbird-notes/app"
+	"bellbird-notes/app/debug"
+	"bellbird-notes/app/notes"
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/mode"
+	"bellbird-notes/tui/theme"
+
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// completionMenuHeight is the maximum number of candidates
+	// the completion menu shows at once
+	completionMenuHeight = 8
+
+	// completionMenuWidth is the maximum width of the completion menu
+	completionMenuWidth = 40
+)
+
+// CompletionKeyMap holds the keys of insert mode that complete words
+type CompletionKeyMap struct {
+	Next, Prev, Accept, Cancel, CtrlX, Notes key.Binding
+}
+
+// CompletionKeys are the keys of insert mode that complete words.
+// Next and Prev also select the candidates of the completion menu just
+// like the keys of MenuKeys. Notes are completed with ctrl+x ctrl+f
+var CompletionKeys = CompletionKeyMap{
+	Next:   key.NewBinding(key.WithKeys("ctrl+n")),
+	Prev:   key.NewBinding(key.WithKeys("ctrl+p")),
+	Accept: key.NewBinding(key.WithKeys("enter", "ctrl+y")),
+	Cancel: key.NewBinding(key.WithKeys("ctrl+e")),
+	CtrlX:  key.NewBinding(key.WithKeys("ctrl+x")),
+	Notes:  key.NewBinding(key.WithKeys("ctrl+f")),
+}
+
+// MenuKeys select candidates while the completion menu is open
+var MenuKeys = struct {
+	Next, Prev key.Binding
+}{
+	Next: key.NewBinding(key.WithKeys("down", "tab")),
+	Prev: key.NewBinding(key.WithKeys("up", "shift+tab")),
+}
+
+// completionSource is a kind of completion
+type completionSource struct {
+	// isWordRune returns whether a rune is part of the completed word
+	isWordRune func(r rune) bool
+
+	// candidates returns the completions of the given word
+	candidates func(word string) []string
+}
+
+// completion holds the candidates of the word before the cursor
+// while cycling through them
+type completion struct {
+	source completionSource
+
+	// row and start are the position of the completed word
+	row, start int
+
+	// word is the word as it has been typed
+	word string
+
+	candidates []string
+
+	// index is the selected candidate, -1 is the word as it's been typed
+	index int
+
+	// end is the column of the cursor after the last completion.
+	// The completion is discarded once the cursor has moved otherwise
+	end int
+}
+
+// handleCompletionKey handles the keys that complete words in insert
+// mode. Returns false if the key has nothing to do with completion
+func (editor *Editor) handleCompletionKey(msg tea.KeyMsg) bool {
+	ctrlX := editor.ctrlX
+	editor.ctrlX = false
+
+	c := editor.activeCompletion()
+
+	switch {
+	case ctrlX && key.Matches(msg, CompletionKeys.Notes):
+		editor.complete(editor.noteSource(), 1)
+
+	case key.Matches(msg, CompletionKeys.CtrlX):
+		editor.ctrlX = true
+
+	case c != nil && key.Matches(msg, MenuKeys.Next, CompletionKeys.Next):
+		editor.complete(c.source, 1)
+
+	case c != nil && key.Matches(msg, MenuKeys.Prev, CompletionKeys.Prev):
+		editor.complete(c.source, -1)
+
+	case key.Matches(msg, CompletionKeys.Next):
+		editor.complete(editor.keywordSource(), 1)
+
+	case key.Matches(msg, CompletionKeys.Prev):
+		editor.complete(editor.keywordSource(), -1)
+
+	case c != nil && key.Matches(msg, CompletionKeys.Accept):
+		editor.completion = nil
+
+	case c != nil && key.Matches(msg, CompletionKeys.Cancel):
+		c.index = -1
+		editor.insertCandidate()
+		editor.completion = nil
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// complete completes the word before the cursor with the next or, if
+// step is negative, the previous candidate. The first completion
+// collects the candidates, following ones cycle through them and back
+// to the word as it has been typed
+func (editor *Editor) complete(source completionSource, step int) {
+	c := editor.activeCompletion()
+
+	if c == nil {
+		ta := &editor.Textarea
+		row, col := ta.Line(), ta.AbsCursorPos().ColumnOffset
+		line := ta.Val()[row]
+
+		start := col
+		for start > 0 && source.isWordRune(line[start-1]) {
+			start--
+		}
+
+		word := string(line[start:col])
+		candidates := source.candidates(word)
+
+		if len(candidates) == 0 {
+			return
+		}
+
+		c = &completion{
+			source:     source,
+			row:        row,
+			start:      start,
+			word:       word,
+			candidates: candidates,
+			index:      -1,
+			end:        col,
+		}
+		editor.completion = c
+	}
+
+	// cycle through the candidates and the typed word
+	n := len(c.candidates) + 1
+	c.index = (c.index+1+step+n)%n - 1
+
+	editor.insertCandidate()
+}
+
+// insertCandidate replaces the completed word with the selected candidate
+func (editor *Editor) insertCandidate() {
+	c := editor.completion
+	ta := &editor.Textarea
+
+	word := c.word
+	if c.index >= 0 {
+		word = c.candidates[c.index]
+	}
+
+	ta.DeleteRange(
+		textarea.CursorPos{Row: c.row, ColumnOffset: c.start},
+		textarea.CursorPos{Row: c.row, ColumnOffset: c.end},
+	)
+	ta.InsertString(word)
+
+	c.end = ta.AbsCursorPos().ColumnOffset
+}
+
+// activeCompletion returns the current completion unless the cursor
+// has been moved since the last completion
+func (editor *Editor) activeCompletion() *completion {
+	c := editor.completion
+	ta := &editor.Textarea
+
+	if c == nil ||
+		editor.Mode.Current != mode.Insert ||
+		ta.Line() != c.row ||
+		ta.AbsCursorPos().ColumnOffset != c.end {
+
+		editor.completion = nil
+	}
+
+	return editor.completion
+}
+
+// isKeywordRune returns whether a rune is part of a keyword
+func isKeywordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// keywordSource completes words with the words of all open buffers.
+// Words of the current buffer come first
+func (editor *Editor) keywordSource() completionSource {
+	return completionSource{
+		isWordRune: isKeywordRune,
+		candidates: func(word string) []string {
+			contents := []string{editor.Textarea.Value()}
+
+			for _, buf := range *editor.Buffers {
+				if buf.path != editor.CurrentBuffer.path {
+					contents = append(contents, buf.Content)
+				}
+			}
+
+			return keywords(contents, word)
+		},
+	}
+}
+
+// keywords returns the distinct words of the given contents that
+// start with the given prefix in the order they appear.
+// The prefix itself isn't part of the result
+func keywords(contents []string, prefix string) []string {
+	seen := map[string]bool{prefix: true}
+	words := []string{}
+
+	for _, content := range contents {
+		fields := strings.FieldsFunc(content, func(r rune) bool {
+			return !isKeywordRune(r)
+		})
+
+		for _, word := range fields {
+			if !seen[word] && strings.HasPrefix(word, prefix) {
+				seen[word] = true
+				words = append(words, word)
+			}
+		}
+	}
+
+	return words
+}
+
+// noteSource completes the paths of the notes within the notes
+// directory, e.g. to link to them. A path matches if either the
+// path or the name of the note starts with the typed word,
+// ignoring case
+func (editor *Editor) noteSource() completionSource {
+	return completionSource{
+		isWordRune: func(r rune) bool {
+			return !unicode.IsSpace(r) && !strings.ContainsRune("[](<>|\"'`", r)
+		},
+		candidates: func(word string) []string {
+			root, err := app.NotesRootDir()
+			if err != nil {
+				return nil
+			}
+
+			paths, err := notes.Paths(root)
+			if err != nil {
+				debug.LogErr(err)
+				return nil
+			}
+
+			prefix := strings.ToLower(word)
+			candidates := []string{}
+
+			for _, path := range paths {
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					continue
+				}
+
+				rel = filepath.ToSlash(rel)
+				name := strings.ToLower(filepath.Base(rel))
+
+				if strings.HasPrefix(strings.ToLower(rel), prefix) ||
+					strings.HasPrefix(name, prefix) {
+
+					candidates = append(candidates, rel)
+				}
+			}
+
+			return slices.DeleteFunc(candidates, func(c string) bool {
+				return c == word
+			})
+		},
+	}
+}
+
+// CompletionMenu renders the candidates of the current completion
+// below the completed word. Returns the menu and its position relative
+// to the top left corner of the editor or an empty string if there's
+// nothing to complete
+func (editor *Editor) CompletionMenu() (string, int, int) {
+	c := editor.activeCompletion()
+	if c == nil {
+		return "", 0, 0
+	}
+
+	height := min(len(c.candidates), completionMenuHeight)
+	first := max(0, min(c.index-height+1, len(c.candidates)-height))
+
+	width := 0
+	for _, candidate := range c.candidates {
+		width = max(width, ansi.StringWidth(candidate))
+	}
+	width = min(width+2, completionMenuWidth)
+
+	style := lipgloss.NewStyle().
+		Background(theme.ColourBgSelected).
+		Padding(0, 1).
+		Width(width)
+
+	selected := style.
+		Background(theme.ColourBorderFocused).
+		Foreground(theme.ColourSearchFg)
+
+	lines := make([]string, 0, height)
+
+	for i := first; i < first+height; i++ {
+		candidate := ansi.Truncate(c.candidates[i], width-2, "…")
+
+		if i == c.index {
+			lines = append(lines, selected.Render(candidate))
+		} else {
+			lines = append(lines, style.Render(candidate))
+		}
+	}
+
+	x, y := editor.Textarea.CursorOffset()
+	line := editor.Textarea.Val()[c.row]
+	x -= ansi.StringWidth(string(line[c.start:c.end]))
+
+	// the menu is shown above the cursor if there's no room below it.
+	// The header of the editor takes one line
+	y++
+	if y+1+height > editor.Size.Height {
+		y -= height
+	} else {
+		y++
+	}
+
+	return strings.Join(lines, "\n"), max(0, x), max(0, y)
+}
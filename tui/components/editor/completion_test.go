@@ -0,0 +1,97 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"bellbird-notes/app"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestKeywordCompletion(t *testing.T) {
+	editor := createTestEditor(t, "TEST1\nTest2\nTest3\ntest4\ntes5t")
+	ta := &editor.Textarea
+
+	ctrl := func(r rune) { editor.Update(tea.KeyPressMsg{Code: r, Mod: tea.ModCtrl}) }
+
+	editor.GoToBottom()
+	editor.InsertLine(false)
+	typeText(editor, "Te")
+
+	for _, want := range []string{"Test2", "Test3", "Te", "Test2"} {
+		ctrl('n')
+
+		if got := string(ta.Val()[5]); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+
+	if menu, _, _ := editor.CompletionMenu(); menu == "" {
+		t.Error("expected the completion menu to be shown")
+	}
+
+	ctrl('p')
+	ctrl('p')
+	if got := string(ta.Val()[5]); got != "Test3" {
+		t.Errorf("expected ctrl+p to select the previous candidate, got %q", got)
+	}
+
+	ctrl('e')
+	if got := string(ta.Val()[5]); got != "Te" {
+		t.Errorf("expected ctrl+e to restore the typed word, got %q", got)
+	}
+
+	// enter accepts the candidate without inserting a new line
+	ctrl('n')
+	typeKey(editor, tea.KeyEnter)
+	typeText(editor, "s")
+
+	if got := string(ta.Val()[5]); got != "Test2s" || len(ta.Val()) != 6 {
+		t.Errorf("expected the completion to be accepted, got %q", got)
+	}
+
+	if menu, _, _ := editor.CompletionMenu(); menu != "" {
+		t.Error("expected the completion menu to be closed")
+	}
+}
+
+func TestNoteCompletion(t *testing.T) {
+	editor := createTestEditor(t, "")
+
+	root, _ := app.NotesRootDir()
+	dir := filepath.Join(root, "projects")
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Mkdir failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "Roadmap.txt"), []byte(""), 0644); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	editor.InsertAfter()
+	typeText(editor, "[x](road")
+	editor.Update(tea.KeyPressMsg{Code: 'x', Mod: tea.ModCtrl})
+	editor.Update(tea.KeyPressMsg{Code: 'f', Mod: tea.ModCtrl})
+
+	if got := editor.Textarea.Value(); got != "[x](projects/Roadmap.txt" {
+		t.Errorf("expected the path of the note to be completed, got %q", got)
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	contents := []string{"foo_bar foo, fob\nfoo", "food foo_bar"}
+
+	got := keywords(contents, "fo")
+	if want := []string{"foo_bar", "foo", "fob", "food"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// the prefix itself is no candidate
+	if got := keywords(contents, "foo"); !slices.Equal(got, []string{"foo_bar", "food"}) {
+		t.Errorf("expected the typed word to be skipped, got %v", got)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"bellbird-notes/app/config"
 	"bellbird-notes/app/debug"
 	"bellbird-notes/app/notes"
+	"bellbird-notes/app/registers"
+	"bellbird-notes/app/spell"
 	"bellbird-notes/app/utils"
 	"bellbird-notes/app/utils/clipboard"
 	"bellbird-notes/tui/components/textarea"
@@ -80,6 +82,61 @@ type Editor struct {
 	// ShowLineNumbers indicates whether to show line numbers
 	ShowLineNumbers bool
 
+	// Registers holds yanked and deleted text
+	Registers *registers.Registers
+
+	// Marks holds the positions set with `m`
+	Marks *Marks
+
+	// Jumps holds the positions the cursor jumped away from
+	Jumps *JumpList
+
+	// blockInsert is set while inserting text into a block selection
+	blockInsert *blockInsert
+
+	// substitution is set while a substitution waits for confirmation
+	substitution *substitution
+
+	// searchOrigin is the cursor position the current search started at.
+	// It's set while the search prompt is open
+	searchOrigin *textarea.CursorPos
+
+	// lastPattern is the pattern of the last search, `:s` or `:g`.
+	// It's used by commands with an empty pattern like `:s//x/`
+	lastPattern string
+
+	// autoIndented is set if the current line has been indented by
+	// autoindent and nothing has been typed since
+	autoIndented bool
+
+	// completion holds the candidates while completing a word
+	// in insert mode
+	completion *completion
+
+	// ctrlX is set after ctrl+x has been typed in insert mode
+	// and the next key selects the kind of completion
+	ctrlX bool
+
+	// spell is the dictionary of the languages in spellLang.
+	// It's loaded once spell checking is enabled
+	spell     *spell.Dictionary
+	spellLang string
+
+	// spellTarget is the word that is replaced with the suggestion
+	// selected after `z=`
+	spellTarget spellTarget
+
+	// matches are the highlights added with `:match`,
+	// they last until the app is closed
+	matches []textarea.HighlightRule
+
+	// batch is set while the changes of several commands are
+	// recorded as a single undo step, see RunOnLines
+	batch bool
+
+	// changes counts the history entries that have been updated
+	changes int
+
 	// conf indicates whether to show column numbers
 	conf *config.Config
 
@@ -104,6 +161,9 @@ func New(title string, conf *config.Config) *Editor {
 		err:                nil,
 		conf:               conf,
 		LastOpenNoteLoaded: false,
+		Registers:          registers.New(),
+		Marks:              NewMarks(),
+		Jumps:              NewJumpList(),
 	}
 
 	editor.SetTitle(title)
@@ -111,6 +171,7 @@ func New(title string, conf *config.Config) *Editor {
 
 	editor.ShowLineNumbers = editor.LineNumbers()
 	editor.Textarea = editor.NewTextarea()
+	editor.RefreshHighlights()
 	editor.OnFocus = editor.onFocus
 	editor.OnBlur = editor.onBlur
 
@@ -187,20 +248,11 @@ func (editor *Editor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return editor, nil
 
 	case SearchMsg:
-		caseOverride := strings.HasPrefix(msg.SearchTerm, "\\c")
-
-		editor.Textarea.Search.IgnoreCase = editor.SearchIgnoreCase()
-
-		if caseOverride {
-			msg.SearchTerm = msg.SearchTerm[2:]
-			editor.Textarea.Search.IgnoreCase = true
-		}
-
-		editor.Textarea.Search.Query = msg.SearchTerm
+		editor.setSearchQuery(msg.SearchTerm)
+		editor.incSearch()
 
 	case SearchConfirmedMsg:
-		match := editor.Textarea.Search.FirstMatch()
-		editor.Textarea.MoveCursor(match.Row, match.RowOffset, match.ColumnOffset)
+		editor.ConfirmSearch()
 
 	case RefreshBufferMsg:
 		editor.BuildHeader(editor.Size.Width, true)
@@ -218,6 +270,11 @@ func (editor *Editor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	editor.RefreshSize()
 	cmds = append(cmds, cmd)
 
+	// the highlighted matches follow the changes of the text. They're
+	// only searched again if the text or the query has changed.
+	// Invalid queries are reported when the search is confirmed
+	_ = editor.Textarea.UpdateSearchMatches()
+
 	return editor, tea.Batch(cmds...)
 }
 
@@ -240,6 +297,15 @@ func (editor *Editor) Content() string {
 // and sets the cursor to the last known position
 func (editor *Editor) SetContent() {
 	buf := editor.CurrentBuffer
+	editor.Textarea.ShowLineNumbers = editor.LineNumbers()
+	editor.Textarea.TabStop = editor.TabStop()
+	editor.Textarea.Markdown = editor.Syntax()
+	editor.Textarea.NoWrap = !editor.Wrap()
+	editor.Textarea.BreakIndent = editor.BreakIndent()
+	editor.Textarea.ShowBreak = editor.ShowBreak()
+	if err := editor.RefreshSpell(); err != nil {
+		debug.LogErr(err)
+	}
 	editor.Textarea.SetValue(buf.Content)
 	editor.Textarea.MoveCursor(
 		buf.CursorPos.Row,
@@ -247,6 +313,7 @@ func (editor *Editor) SetContent() {
 		buf.CursorPos.ColumnOffset,
 	)
 	editor.Textarea.RepositionView()
+	editor.trackPositions()
 }
 
 // RefreshSize update the textarea height and width to match
@@ -357,6 +424,15 @@ func (editor *Editor) NewScratchBuffer(
 // OpenBuffer attempts to open the buffer with the given path.
 // If no buffer is found a new buffer is created
 func (editor *Editor) OpenBuffer(path string) message.StatusBarMsg {
+	if path != editor.CurrentBuffer.Path(false) {
+		editor.addJump()
+	}
+
+	return editor.openBuffer(path)
+}
+
+// openBuffer opens the buffer without adding a jump to the jump list
+func (editor *Editor) openBuffer(path string) message.StatusBarMsg {
 	statusMsg := editor.StatusBarFileInfo(path)
 
 	buf := editor.Buffers.Find(path)
@@ -383,6 +459,10 @@ func (editor *Editor) SwitchBuffer(buf *Buffer) message.StatusBarMsg {
 		return message.StatusBarMsg{}
 	}
 
+	if buf != editor.CurrentBuffer {
+		editor.addJump()
+	}
+
 	editor.CurrentBuffer = buf
 
 	editor.SetContent()
@@ -649,6 +729,7 @@ func (editor *Editor) EnterNormalMode(withHistory bool) message.StatusBarMsg {
 
 	if editor.Mode.IsAnyVisual() {
 		statusMsg.Column = sbc.KeyInfo
+		editor.setVisualMarks()
 	}
 
 	editor.Mode.Current = mode.Normal
@@ -695,6 +776,9 @@ func (editor *Editor) EnterInsertMode(withHistory bool) message.StatusBarMsg {
 	}
 
 	editor.Mode.Current = mode.Insert
+	editor.autoIndented = false
+	editor.completion = nil
+	editor.ctrlX = false
 	if withHistory {
 		editor.newHistoryEntry()
 	}
@@ -728,8 +812,11 @@ func (editor *Editor) EnterVisualMode(
 	editor.Textarea.StartSelection(selectionMode)
 
 	vimMode := mode.Visual
-	if selectionMode == textarea.SelectVisualLine {
+	switch selectionMode {
+	case textarea.SelectVisualLine:
 		vimMode = mode.VisualLine
+	case textarea.SelectVisualBlock:
+		vimMode = mode.VisualBlock
 	}
 
 	editor.Mode.Current = vimMode
@@ -740,24 +827,42 @@ func (editor *Editor) EnterVisualMode(
 // newHistoryEntry creates a new history entry for the current Buffers
 // saving the correct undo cursor position
 func (editor *Editor) newHistoryEntry() {
+	if editor.batch {
+		return
+	}
+
 	editor.CurrentBuffer.History.NewTmpEntry(editor.Textarea.CursorPos())
 }
 
 // updateHistoryEntry update the history entry saving the undo/redo
 // patch, the current cursor position and the hash of the buffer content
 func (editor *Editor) updateHistoryEntry() {
+	if editor.batch {
+		return
+	}
+
 	buf := editor.CurrentBuffer
 	editor.saveCursorPos()
 
 	redoPatch := buf.History.MakePatch(buf.Content, editor.Textarea.Value())
 	undoPatch := buf.History.MakePatch(editor.Textarea.Value(), buf.Content)
 
+	editor.updatePositions()
+
 	buf.History.UpdateEntry(
 		redoPatch,
 		undoPatch,
 		editor.Textarea.CursorPos(),
 		buf.hash(),
 	)
+
+	editor.changes++
+}
+
+// Changes returns the number of changes that have been made to
+// any buffer. Undo and redo don't count as changes
+func (editor *Editor) Changes() int {
+	return editor.changes
 }
 
 // checkDirty marks the current buffer as dirty if the current
@@ -792,7 +897,7 @@ func (editor *Editor) cursorInfo() string {
 	var info strings.Builder
 	info.WriteString(strconv.Itoa(editor.Textarea.Line() + 1))
 	info.WriteByte(',')
-	info.WriteString(strconv.Itoa(editor.Textarea.LineInfo().ColumnOffset))
+	info.WriteString(strconv.Itoa(editor.Textarea.CursorPos().ColumnOffset))
 	return info.String()
 }
 
@@ -858,16 +963,6 @@ func (editor Editor) StatusBarFileInfo(path string) message.StatusBarMsg {
 	}
 }
 
-func (editor *Editor) SetNumbers() {
-	editor.Textarea.ShowLineNumbers = true
-	editor.Content()
-}
-
-func (editor *Editor) SetNoNumbers() {
-	editor.Textarea.ShowLineNumbers = false
-	editor.Content()
-}
-
 // OpenConfig opens the config file as a buffer
 func (editor *Editor) OpenConfig() message.StatusBarMsg {
 	if configFile, err := app.ConfigFile(false); err == nil {
@@ -951,7 +1046,9 @@ func (editor *Editor) InsertLineEnd() message.StatusBarMsg {
 }
 
 // InsertLine creates and empty line below the current line
-// and enters insert mode.
+// and enters insert mode. Below a list item the new line continues
+// the list, otherwise with autoindent it gets the indentation
+// of the current line.
 // If above is true it inserts the line above the current line
 func (editor *Editor) InsertLine(above bool) message.StatusBarMsg {
 	if !editor.CurrentBuffer.Writeable {
@@ -960,13 +1057,22 @@ func (editor *Editor) InsertLine(above bool) message.StatusBarMsg {
 
 	editor.newHistoryEntry()
 
+	from := editor.Textarea.Line()
+
 	if above {
 		editor.Textarea.EmptyLineAbove()
+		// the current line has moved below the new one
+		from++
 	} else {
 		editor.Textarea.EmptyLineBelow()
 	}
 
 	editor.EnterInsertMode(false)
+
+	if above || !editor.continueList(from) {
+		editor.autoIndent(from)
+	}
+
 	return message.StatusBarMsg{}
 }
 
@@ -1061,6 +1167,11 @@ func (editor *Editor) GoToInputStart() message.StatusBarMsg {
 // GoToLineEnd moves the cursor to the end of the line, sets isAtLineEnd
 // and saves the cursor position
 func (editor *Editor) GoToLineEnd() message.StatusBarMsg {
+	// block selections extend to the end of every line
+	if editor.Mode.Current == mode.VisualBlock {
+		editor.Textarea.Selection.ToLineEnd = true
+	}
+
 	editor.Textarea.CursorLineVimEnd()
 	editor.isAtLineStart = editor.Textarea.IsAtLineStart()
 	editor.isAtLineEnd = editor.Textarea.IsAtLineEnd()
@@ -1070,6 +1181,7 @@ func (editor *Editor) GoToLineEnd() message.StatusBarMsg {
 
 // GoToTop moves the cursor to the beginning of the buffer
 func (editor *Editor) GoToTop() message.StatusBarMsg {
+	editor.addJump()
 	editor.Textarea.MoveToTop()
 	editor.Textarea.RepositionView()
 	editor.saveCursorPos()
@@ -1078,12 +1190,26 @@ func (editor *Editor) GoToTop() message.StatusBarMsg {
 
 // GoToBottom moves the cursor to the bottom of the buffer
 func (editor *Editor) GoToBottom() message.StatusBarMsg {
+	editor.addJump()
 	editor.Textarea.MoveToBottom()
 	editor.Textarea.RepositionView()
 	editor.saveCursorPos()
 	return editor.UpdateSelectedRowsCount()
 }
 
+// GoToLine moves the cursor to the first non-blank character
+// of the given line number. Line numbers start at 1
+func (editor *Editor) GoToLine(line int) message.StatusBarMsg {
+	row := min(max(line-1, 0), editor.Textarea.LineCount()-1)
+
+	editor.addJump()
+	editor.Textarea.MoveCursor(row, 0, 0)
+	editor.Textarea.CursorInputStart()
+	editor.Textarea.RepositionView()
+	editor.saveCursorPos()
+	return editor.UpdateSelectedRowsCount()
+}
+
 // WordRightStart moves the cursor to the beginning of the next word
 func (editor *Editor) WordForward(end bool) message.StatusBarMsg {
 	if end {
@@ -1107,6 +1233,25 @@ func (editor *Editor) WordBack(end bool) message.StatusBarMsg {
 	return message.StatusBarMsg{}
 }
 
+// MoveToMatch moves the cursor to the next search match
+// or to the previous one if prev is true.
+// The direction is reversed for searches started with `?`
+func (editor *Editor) MoveToMatch(prev bool) message.StatusBarMsg {
+	if err := editor.Textarea.UpdateSearchMatches(); err != nil {
+		return editor.invalidSearchMsg()
+	}
+
+	editor.addJump()
+
+	if prev != editor.Textarea.Search.Backward {
+		editor.Textarea.FindPrevMatch()
+	} else {
+		editor.Textarea.FindNextMatch()
+	}
+
+	return message.StatusBarMsg{}
+}
+
 // FindCharacter searches for the given character in the current line,
 // If back is true if searches back otherwise forward.
 // If found, it updates the cursor position
@@ -1121,6 +1266,87 @@ func (editor *Editor) FindCharacter(char string, back bool) message.StatusBarMsg
 	return message.StatusBarMsg{}
 }
 
+// TillCharacter works like FindCharacter but moves the cursor next to
+// the found character instead of onto it.
+// If skipAdjacent is true a match right next to the cursor is skipped so
+// that repeating the motion doesn't get stuck
+func (editor *Editor) TillCharacter(
+	char string,
+	back bool,
+	skipAdjacent bool,
+) message.StatusBarMsg {
+	dir := 1
+	if back {
+		dir = -1
+	}
+
+	col := editor.Textarea.AbsCursorPos().ColumnOffset
+
+	if skipAdjacent {
+		editor.Textarea.SetCursorColumn(col + dir)
+	}
+
+	charPos := editor.Textarea.FindCharacter(char, back)
+
+	if charPos == nil {
+		editor.Textarea.SetCursorColumn(col)
+		return message.StatusBarMsg{}
+	}
+
+	editor.Textarea.SetCursorColumn(charPos.ColumnOffset - dir)
+	editor.saveCursorPos()
+
+	return message.StatusBarMsg{}
+}
+
+// ParagraphForward moves the cursor to the next empty line after the
+// current paragraph. If there is none it moves to the end of the buffer
+func (editor *Editor) ParagraphForward() message.StatusBarMsg {
+	val := editor.Textarea.Val()
+	row := editor.Textarea.CursorPos().Row
+	last := len(val) - 1
+
+	for row < last && len(val[row]) == 0 {
+		row++
+	}
+
+	for row < last && len(val[row]) > 0 {
+		row++
+	}
+
+	col := 0
+	if len(val[row]) > 0 {
+		col = len(val[row]) - 1
+	}
+
+	editor.Textarea.MoveCursor(row, 0, col)
+	editor.isAtLineEnd = false
+	editor.saveCursorPos()
+
+	return editor.UpdateSelectedRowsCount()
+}
+
+// ParagraphBack moves the cursor to the previous empty line before the
+// current paragraph. If there is none it moves to the top of the buffer
+func (editor *Editor) ParagraphBack() message.StatusBarMsg {
+	val := editor.Textarea.Val()
+	row := editor.Textarea.CursorPos().Row
+
+	for row > 0 && len(val[row]) == 0 {
+		row--
+	}
+
+	for row > 0 && len(val[row]) > 0 {
+		row--
+	}
+
+	editor.Textarea.MoveCursor(row, 0, 0)
+	editor.isAtLineEnd = false
+	editor.saveCursorPos()
+
+	return editor.UpdateSelectedRowsCount()
+}
+
 func (editor *Editor) DeleteBeforeCharacter(char string, back bool) message.StatusBarMsg {
 	charPos := editor.Textarea.FindCharacter(char, back)
 
@@ -1144,6 +1370,22 @@ func (editor *Editor) UpHalfPage() message.StatusBarMsg {
 	return editor.UpdateSelectedRowsCount()
 }
 
+// ScrollLeft scrolls the text one column to the left if lines aren't
+// wrapped, which reveals the column before the first visible one
+func (editor *Editor) ScrollLeft() message.StatusBarMsg {
+	editor.Textarea.ScrollHorizontally(-1)
+	editor.saveCursorPos()
+	return editor.UpdateSelectedRowsCount()
+}
+
+// ScrollRight scrolls the text one column to the right if lines aren't
+// wrapped, which reveals the column after the last visible one
+func (editor *Editor) ScrollRight() message.StatusBarMsg {
+	editor.Textarea.ScrollHorizontally(1)
+	editor.saveCursorPos()
+	return editor.UpdateSelectedRowsCount()
+}
+
 // SelectWord selects the word the cursor is currently on.
 // If outer is true it includes the whitespace after.
 // Only effective if we're in visual mode
@@ -1156,18 +1398,52 @@ func (editor *Editor) SelectWord(outer bool) message.StatusBarMsg {
 	return editor.UpdateSelectedRowsCount()
 }
 
+// SelectTextObject selects a text object with the given function,
+// e.g. Textarea.SelectSentence, which returns false if there's
+// nothing to select.
+// In visual mode it switches to visual line mode for objects that
+// consist of whole lines and vice versa
+func (editor *Editor) SelectTextObject(selectFn func() bool) message.StatusBarMsg {
+	if !selectFn() {
+		return editor.UpdateSelectedRowsCount()
+	}
+
+	if editor.Mode.IsAnyVisual() {
+		editor.Mode.Current = mode.Visual
+		if editor.Textarea.Selection.Mode == textarea.SelectVisualLine {
+			editor.Mode.Current = mode.VisualLine
+		}
+	}
+
+	editor.isAtLineEnd = editor.Textarea.IsAtLineEnd()
+	editor.saveCursorPos()
+
+	return editor.UpdateSelectedRowsCount()
+}
+
 // DeleteLine deletes the current line and copies its content
 // to the clipboard
 func (editor *Editor) DeleteLine() message.StatusBarMsg {
+	return editor.DeleteLines(1)
+}
+
+// DeleteLines deletes `count` lines starting at the current line
+// and copies their content to the clipboard
+func (editor *Editor) DeleteLines(count int) message.StatusBarMsg {
 	if !editor.CurrentBuffer.Writeable {
 		return message.StatusBarMsg{}
 	}
 
 	editor.newHistoryEntry()
 
+	// don't delete more lines than there are left
+	cursorPos := editor.Textarea.CursorPos()
+	count = min(max(count, 1), editor.Textarea.LineCount()-cursorPos.Row)
+
 	editor.saveLineLength()
-	editor.YankLine()
-	editor.Textarea.DeleteLine()
+	editor.Cut(editor.Textarea.LinesStr(cursorPos.Row, cursorPos.Row+count-1), true)
+	editor.Textarea.DeleteLines(count, false)
+	editor.renumberList(cursorPos.Row)
 	editor.updateBufferContent(true)
 	editor.EnterNormalMode(true)
 
@@ -1225,19 +1501,24 @@ func (editor *Editor) DeleteNLines(lines int, up bool) message.StatusBarMsg {
 }
 
 // DeleteWordRight deletes the rest of word after the cursor
-func (editor *Editor) DeleteWordRight() message.StatusBarMsg {
+// and the following `count - 1` words
+func (editor *Editor) DeleteWordRight(count int) message.StatusBarMsg {
 	if !editor.CurrentBuffer.Writeable {
 		return message.StatusBarMsg{}
 	}
 
 	editor.newHistoryEntry()
-	editor.Textarea.DeleteWordRight()
+	for range max(count, 1) {
+		editor.Textarea.DeleteWordRight()
+	}
 	editor.updateHistoryEntry()
 	return message.StatusBarMsg{}
 }
 
-// MergeLineBelow merges the current line with the line below
-func (editor *Editor) MergeLineBelow() message.StatusBarMsg {
+// MergeLineBelow merges the current line with the line below.
+// Without a selection `count` lines are merged, a count lower
+// than 2 merges two lines
+func (editor *Editor) MergeLineBelow(count int) message.StatusBarMsg {
 	if !editor.CurrentBuffer.Writeable {
 		return message.StatusBarMsg{}
 	}
@@ -1254,7 +1535,9 @@ func (editor *Editor) MergeLineBelow() message.StatusBarMsg {
 	// no selection - everything should be the current row
 	if startRow == -1 {
 		startRow = cursorPos.Row
-		endRow = cursorPos.Row
+		endRow = startRow + max(count-1, 1) - 1
+		// the last line has nothing to merge with
+		endRow = min(endRow, editor.Textarea.LineCount()-2)
 	} else {
 		row := startRow
 		// get start and end row no matter the selection direction
@@ -1296,11 +1579,17 @@ func (editor *Editor) DeleteRune(
 
 	cursorPos := editor.Textarea.CursorPos()
 	minRange, maxRange := editor.Textarea.Selection.Range(cursorPos)
+	linewise := editor.Textarea.Selection.Mode == textarea.SelectVisualLine
+	blockwise := editor.Textarea.Selection.Mode == textarea.SelectVisualBlock
 
-	if minRange.Row > -1 {
+	if blockwise {
+		lines := editor.Textarea.DeleteBlock(editor.Textarea.BlockSelection())
+		char = strings.Join(lines, "\n")
+	} else if minRange.Row > -1 {
 		char = editor.Textarea.SelectionStr()
-		if editor.Textarea.Selection.Mode == textarea.SelectVisualLine {
+		if linewise {
 			editor.Textarea.DeleteSelectedLines()
+			editor.renumberList(minRange.Row)
 		} else {
 			editor.Textarea.DeleteRunesInRange(minRange, maxRange)
 		}
@@ -1309,7 +1598,11 @@ func (editor *Editor) DeleteRune(
 	}
 
 	if !noYank {
-		editor.Yank(char)
+		if blockwise {
+			editor.Registers.DeleteBlock(char)
+		} else {
+			editor.Cut(char, linewise)
+		}
 	}
 
 	if !keepMode {
@@ -1319,6 +1612,33 @@ func (editor *Editor) DeleteRune(
 	return editor.ResetSelectedRowsCount()
 }
 
+// DeleteCharacters deletes `count` characters starting at the cursor
+// and copies them to the clipboard.
+// In visual mode it deletes the selection instead
+func (editor *Editor) DeleteCharacters(count int) message.StatusBarMsg {
+	if count <= 1 || editor.Mode.IsAnyVisual() {
+		return editor.DeleteRune(false, true, false)
+	}
+
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	editor.newHistoryEntry()
+
+	c := editor.CurrentBuffer.CursorPos
+	var chars strings.Builder
+
+	for range count {
+		chars.WriteString(editor.Textarea.DeleteRune(c.Row, c.ColumnOffset))
+	}
+
+	editor.Cut(chars.String(), false)
+	editor.EnterNormalMode(true)
+	editor.Textarea.RepositionView()
+	return editor.ResetSelectedRowsCount()
+}
+
 // ResetSelectedRowsCount resets the selected rows count in the status bar
 func (editor *Editor) ResetSelectedRowsCount() message.StatusBarMsg {
 	return message.StatusBarMsg{
@@ -1368,6 +1688,7 @@ func (editor *Editor) Undo() message.StatusBarMsg {
 		)
 
 		editor.Textarea.RepositionView()
+		editor.trackPositions()
 		editor.CurrentBuffer.Content = editor.Textarea.Value()
 		editor.isAtLineEnd = editor.Textarea.IsAtLineEnd()
 		editor.isAtLineStart = editor.Textarea.IsAtLineStart()
@@ -1395,17 +1716,23 @@ func (editor *Editor) Redo() message.StatusBarMsg {
 		)
 
 		editor.Textarea.RepositionView()
+		editor.trackPositions()
 		editor.CurrentBuffer.Content = editor.Textarea.Value()
 	}
 
 	return message.StatusBarMsg{}
 }
 
-// Yank copies the given string to the clipboard
+// Yank stores the given string in the selected register.
+// Strings with a trailing new line are stored as whole lines
 func (editor *Editor) Yank(str string) message.StatusBarMsg {
-	if err := clipboard.Write(str); err != nil {
-		debug.LogDebug(err)
-	}
+	editor.Registers.Yank(str, strings.HasSuffix(str, "\n"))
+	return message.StatusBarMsg{}
+}
+
+// Cut stores deleted or changed text in the selected register
+func (editor *Editor) Cut(str string, linewise bool) message.StatusBarMsg {
+	editor.Registers.Delete(str, linewise)
 	return message.StatusBarMsg{}
 }
 
@@ -1413,20 +1740,26 @@ func (editor *Editor) Yank(str string) message.StatusBarMsg {
 // If keepCursorPos is true the cursor position remains the same
 // otherwise the cursor is moved to the beginning of the selection
 func (editor *Editor) YankSelection(keepCursor bool) message.StatusBarMsg {
-	sel := editor.Textarea.SelectionStr()
-	editor.Yank(sel)
-
 	var cursorDeferredCmd tea.Cmd
 
 	buf := editor.CurrentBuffer
 	startRow := editor.Textarea.Selection.StartRow
 	startCol := editor.Textarea.Selection.StartCol
 
-	if editor.Mode.Current == mode.VisualLine {
+	switch editor.Mode.Current {
+	case mode.VisualBlock:
+		// move the cursor to the top left corner of the block
+		block := editor.Textarea.BlockSelection()
+		editor.YankBlock(editor.Textarea.BlockStr(block))
+		startRow = block.StartRow
+		startCol = editor.Textarea.ColumnAt(block.StartRow, block.StartCol)
+
+	case mode.VisualLine:
+		editor.Yank(editor.Textarea.SelectionStr())
 		startCol = 0
-		// append a new line as a hacky indicator that we are supposed
-		// to paste visual line selections on a new line
-		sel += "\n"
+
+	default:
+		editor.Yank(editor.Textarea.SelectionStr())
 	}
 
 	cursor := textarea.CursorPos{
@@ -1467,8 +1800,21 @@ func (editor *Editor) YankAfterCursor() message.StatusBarMsg {
 
 // YankLine copies the current line to the clipboard
 func (editor *Editor) YankLine() message.StatusBarMsg {
+	return editor.YankLines(1)
+}
+
+// YankLines copies `count` lines starting at the current line
+// to the clipboard
+func (editor *Editor) YankLines(count int) message.StatusBarMsg {
 	editor.saveCursorPos()
 	editor.EnterVisualMode(textarea.SelectVisualLine)
+
+	if count > 1 {
+		cursorPos := editor.Textarea.CursorPos()
+		lastRow := min(cursorPos.Row+count-1, editor.Textarea.LineCount()-1)
+		editor.Textarea.MoveCursor(lastRow, 0, cursorPos.ColumnOffset)
+	}
+
 	return editor.YankSelection(true)
 }
 
@@ -1486,22 +1832,23 @@ func (editor *Editor) YankWord(outer bool) message.StatusBarMsg {
 	return editor.YankSelection(false)
 }
 
-// Paste pastes the clipboard content.
-// If the selection exceeds the length of the current line
-// it attempts to paste the clipboard content on a newline below
+// Paste pastes the content of the selected register `count` times.
+// Linewise register content is pasted on a new line below
 // the current line
-func (editor *Editor) Paste() message.StatusBarMsg {
+func (editor *Editor) Paste(count int) message.StatusBarMsg {
 	if !editor.CurrentBuffer.Writeable {
 		return message.StatusBarMsg{}
 	}
 
-	cnt, err := clipboard.Read()
+	reg, _ := editor.Registers.Get()
+	cnt := reg.Content
 
-	if err != nil {
-		debug.LogDebug(err)
-	}
-
-	if len(cnt) > 0 {
+	if len(cnt) > 0 && reg.Blockwise {
+		editor.newHistoryEntry()
+		editor.pasteBlock(cnt, count)
+		editor.Textarea.RepositionView()
+		editor.updateBufferContent(true)
+	} else if len(cnt) > 0 {
 		// save the curren cursor position to adjust the correct position
 		// after the clipboard content is pasted
 		var (
@@ -1513,23 +1860,21 @@ func (editor *Editor) Paste() message.StatusBarMsg {
 
 		editor.newHistoryEntry()
 
-		r := []rune(cnt)
-		pasteOnNewLine := r[len(r)-1] == '\n'
-
-		if pasteOnNewLine {
+		if reg.Linewise {
 			editor.Textarea.EmptyLineBelow()
 
 			// strip the last new line since we've already inserted
 			// an empty line so we don't need it
 			// otherwise it would produce an additional empty line
 			cnt = strings.TrimRight(cnt, "\n")
+			cnt = strings.TrimSuffix(strings.Repeat(cnt+"\n", max(count, 1)), "\n")
 
 			// set the cursor position at the beginning of the next row
 			// which is the newly pasted content
 			col = 0
 			row++
 		} else {
-			cnt = strings.TrimSpace(cnt)
+			cnt = strings.Repeat(strings.TrimSpace(cnt), max(count, 1))
 			// if the clipboard content is not a full line set the
 			// add the length of the selection to the current column offset
 			// to set the cursor to the end of the selection
@@ -1552,6 +1897,24 @@ func (editor *Editor) Paste() message.StatusBarMsg {
 func (editor *Editor) ChangeCaseOfSelection(toUpper bool) message.StatusBarMsg {
 	editor.newHistoryEntry()
 
+	if editor.Mode.Current == mode.VisualBlock {
+		block := editor.Textarea.BlockSelection()
+		op := OperatorLowerCase
+		if toUpper {
+			op = OperatorUpperCase
+		}
+
+		editor.Textarea.MapBlock(block, caseMapper(op))
+		editor.Textarea.MoveCursor(
+			block.StartRow,
+			0,
+			editor.Textarea.ColumnAt(block.StartRow, block.StartCol),
+		)
+		editor.EnterNormalMode(true)
+
+		return message.StatusBarMsg{}
+	}
+
 	cursorPos := editor.Textarea.CursorPos()
 	selection := editor.Textarea.SelectionStr()
 	start, end := editor.Textarea.Selection.Range(cursorPos)
@@ -1620,6 +1983,11 @@ func (editor *Editor) saveCursorPosToConf() {
 // updateBufferContent replaces the content of the current buffer with the
 // current textarea value
 func (editor *Editor) updateBufferContent(withHistory bool) {
+	// changes of a batch are recorded when the batch ends
+	if editor.batch {
+		return
+	}
+
 	if withHistory {
 		editor.updateHistoryEntry()
 	}
@@ -1644,9 +2012,10 @@ func (editor *Editor) UpdateMetaInfo() {
 	editor.conf.SetMetaValue("", config.LastOpenNote, editor.CurrentBuffer.Path(true))
 }
 
-// LineNumbers returns whether line numbers are enabled in the config file
+// LineNumbers returns whether line numbers are enabled for the
+// current buffer or in the config file
 func (editor *Editor) LineNumbers() bool {
-	numbers, err := editor.conf.Value(config.Editor, config.LineNumbers)
+	numbers, err := editor.value(config.Editor, config.LineNumbers)
 
 	if err != nil {
 		debug.LogErr(err)
@@ -1656,10 +2025,45 @@ func (editor *Editor) LineNumbers() bool {
 	return numbers.GetBool()
 }
 
+// Syntax returns whether the markdown syntax is highlighted
+func (editor *Editor) Syntax() bool {
+	syntax, err := editor.value(config.Editor, config.Syntax)
+
+	if err != nil {
+		debug.LogErr(err)
+		return false
+	}
+
+	return syntax.GetBool()
+}
+
+// Wrap returns whether long lines are soft wrapped
+func (editor *Editor) Wrap() bool {
+	wrap, err := editor.value(config.Editor, config.Wrap)
+	return err != nil || wrap.GetBool()
+}
+
+// BreakIndent returns whether soft wrapped lines continue
+// with the indentation of the line
+func (editor *Editor) BreakIndent() bool {
+	breakIndent, err := editor.value(config.Editor, config.BreakIndent)
+	return err == nil && breakIndent.GetBool()
+}
+
+// ShowBreak returns the text displayed at the beginning
+// of soft wrapped lines
+func (editor *Editor) ShowBreak() string {
+	showBreak, err := editor.value(config.Editor, config.ShowBreak)
+	if err != nil {
+		return ""
+	}
+	return showBreak.Value
+}
+
 // SearchIgnoreCase returns true if the editor config enables
 // case-insensitive search.
 func (editor *Editor) SearchIgnoreCase() bool {
-	ignoreCase, err := editor.conf.Value(config.Editor, config.SearchIgnoreCase)
+	ignoreCase, err := editor.value(config.Editor, config.SearchIgnoreCase)
 
 	if err != nil {
 		return false
@@ -1668,11 +2072,44 @@ func (editor *Editor) SearchIgnoreCase() bool {
 	return ignoreCase.GetBool()
 }
 
+// SearchSmartCase returns true if the editor config enables case-sensitive
+// search for queries containing upper case letters
+func (editor *Editor) SearchSmartCase() bool {
+	smartCase, err := editor.value(config.Editor, config.SearchSmartCase)
+
+	if err != nil {
+		return false
+	}
+
+	return smartCase.GetBool()
+}
+
+// SearchRegex returns true if the editor config enables searching
+// for regular expressions instead of literal text
+func (editor *Editor) SearchRegex() bool {
+	regex, err := editor.value(config.Editor, config.SearchRegex)
+
+	if err != nil {
+		return false
+	}
+
+	return regex.GetBool()
+}
+
 func (editor *Editor) RefreshTextAreaStyles() {
 	s := defaultStyles(editor.Theme())
 	editor.Textarea.Styles.Blurred.Base = s.blurred
 	editor.Textarea.Styles.Focused.Base = s.focused
 	editor.Textarea.ShowLineNumbers = editor.LineNumbers()
+	editor.Textarea.TabStop = editor.TabStop()
+	editor.Textarea.Markdown = editor.Syntax()
+	editor.Textarea.NoWrap = !editor.Wrap()
+	editor.Textarea.BreakIndent = editor.BreakIndent()
+	editor.Textarea.ShowBreak = editor.ShowBreak()
+	if err := editor.RefreshSpell(); err != nil {
+		debug.LogErr(err)
+	}
+	editor.RefreshHighlights()
 	editor.BuildHeader(editor.Size.Width, true)
 	editor.Content()
 }
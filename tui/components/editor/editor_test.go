@@ -0,0 +1,76 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bellbird-notes/app/config"
+	"bellbird-notes/internal/testutil"
+	"bellbird-notes/tui/components/textarea"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// createTestEditor creates a focused editor with a note of the
+// given content and the cursor at its beginning
+func createTestEditor(t *testing.T, content string) *Editor {
+	t.Helper()
+
+	testutil.TempHome(t)
+
+	path := filepath.Join(t.TempDir(), "test_note.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	editor := New("Editor", config.New())
+	buffers := make(Buffers, 0)
+	editor.SetBuffers(&buffers)
+	editor.SetFocus(true)
+	editor.Size.Width = 80
+	editor.Size.Height = 20
+
+	// the application only allows input after entering insert mode,
+	// the tests call the editor directly though
+	editor.CanInsert = true
+
+	editor.NewBuffer(path)
+	editor.Textarea.MoveCursor(0, 0, 0)
+	editor.CurrentBuffer.CursorPos = editor.Textarea.CursorPos()
+
+	return editor
+}
+
+// typeText passes the keys for the given text to the editor
+// just as if they have been typed
+func typeText(editor *Editor, text string) {
+	for _, r := range text {
+		editor.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+}
+
+// typeKey passes the given key to the editor
+func typeKey(editor *Editor, code rune) {
+	editor.Update(tea.KeyPressMsg{Code: code})
+}
+
+// setLocal sets the buffer-local value of the setting with the given name
+func setLocal(t *testing.T, editor *Editor, name string, value string) {
+	t.Helper()
+
+	s, ok := config.LookupSetting(name)
+	if !ok {
+		t.Fatalf("unknown setting %q", name)
+	}
+	editor.SetLocalSetting(s, value)
+}
+
+// linesRange returns the linewise operator range from row start to row end
+func linesRange(start int, end int) OperatorRange {
+	return OperatorRange{
+		Start:    textarea.CursorPos{Row: start},
+		End:      textarea.CursorPos{Row: end},
+		Linewise: true,
+	}
+}
@@ -0,0 +1,307 @@
+package editor
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/rivo/uniseg"
+
+	"bellbird-notes/app/config"
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+)
+
+// defaultTextWidth is the width `gq` formats to if textwidth is 0
+const defaultTextWidth = 79
+
+var (
+	// quotePrefixRegex matches the indentation and the markers
+	// of a blockquote, e.g. `> ` or `> > `
+	quotePrefixRegex = regexp.MustCompile(`^[ \t]*(?:>[ \t]?)+`)
+
+	fenceLineRegex = regexp.MustCompile("^[ \t]{0,3}(```|~~~)")
+
+	// lines that are kept as they are when formatting:
+	// headings, tables and thematic breaks
+	keepLineRegex = regexp.MustCompile(`^[ \t]*(?:#{1,6}(?:\s|$)|\||(?:[-*_][ \t]*){3,}$)`)
+)
+
+// TextWidth returns the width lines are wrapped at while typing.
+// 0 disables the wrapping
+func (editor *Editor) TextWidth() int {
+	return max(editor.intValue(config.TextWidth), 0)
+}
+
+// formatWidth returns the width `gq` formats lines to
+func (editor *Editor) formatWidth() int {
+	if tw := editor.TextWidth(); tw > 0 {
+		return tw
+	}
+	return defaultTextWidth
+}
+
+// linePrefix is the beginning of a line that is kept when
+// its text is wrapped: the indentation, blockquote markers
+// and list markers
+type linePrefix struct {
+	// quote is the indentation and the blockquote markers
+	quote string
+
+	// first is the whole prefix of the line, next is the prefix
+	// of the lines its text continues on
+	first, next string
+
+	isItem bool
+}
+
+// parseLinePrefix returns the prefix of the given line.
+// The text of list items continues aligned with the text after
+// the marker, e.g. `1. text` continues with `   text`
+func parseLinePrefix(line []rune) linePrefix {
+	str := string(line)
+	quote := quotePrefixRegex.FindString(str)
+	rest := []rune(str[len(quote):])
+
+	if item, ok := parseListItem(rest); ok {
+		marker := item.len - len([]rune(item.indent))
+
+		return linePrefix{
+			quote:  quote,
+			first:  quote + string(rest[:item.len]),
+			next:   quote + item.indent + strings.Repeat(" ", marker),
+			isItem: true,
+		}
+	}
+
+	text := strings.TrimLeftFunc(string(rest), unicode.IsSpace)
+	prefix := quote + string(rest)[:len(string(rest))-len(text)]
+
+	return linePrefix{quote: quote, first: prefix, next: prefix}
+}
+
+// displayWidth returns the number of columns the given text is
+// displayed with, tabs are expanded to the tab stop
+func (editor *Editor) displayWidth(s string) int {
+	return uniseg.StringWidth(string(textarea.ExpandTabs([]rune(s), editor.TabStop())))
+}
+
+// wrapWords wraps the words so that the lines including the prefix
+// don't exceed the given width. Words longer than the width
+// get their own line
+func (editor *Editor) wrapWords(words []string, prefix linePrefix, width int) []string {
+	prefixWidth := max(editor.displayWidth(prefix.first), editor.displayWidth(prefix.next))
+
+	ww := wordwrap.NewWriter(max(width-prefixWidth, 1))
+	ww.Breakpoints = nil
+	ww.KeepNewlines = false
+
+	_, _ = ww.Write([]byte(strings.Join(words, " ")))
+	_ = ww.Close()
+
+	lines := strings.Split(ww.String(), "\n")
+
+	for i, line := range lines {
+		p := prefix.next
+		if i == 0 {
+			p = prefix.first
+		}
+		lines[i] = p + strings.TrimSpace(line)
+	}
+
+	return lines
+}
+
+// inFence returns whether the given row is within a fenced code block
+func inFence(lines [][]rune, row int) bool {
+	fence := ""
+
+	for _, line := range lines[:min(row, len(lines))] {
+		m := fenceLineRegex.FindStringSubmatch(string(line))
+		if m == nil {
+			continue
+		}
+
+		if fence == "" {
+			fence = m[1]
+		} else if fence == m[1] {
+			fence = ""
+		}
+	}
+
+	return fence != ""
+}
+
+// formatLines wraps the paragraphs between the rows `start` and `end`
+// at the format width and returns the formatted lines.
+// Blank lines, headings, tables and fenced code blocks are kept
+// as they are. Every list item is a paragraph of its own
+func (editor *Editor) formatLines(lines [][]rune, start, end int) []string {
+	width := editor.formatWidth()
+	res := []string{}
+
+	var (
+		prefix linePrefix
+		words  []string
+
+		// paragraph is the number of lines of the current paragraph
+		paragraph int
+	)
+
+	flush := func() {
+		if paragraph > 0 {
+			res = append(res, editor.wrapWords(words, prefix, width)...)
+		}
+		paragraph = 0
+		words = nil
+	}
+
+	fenced := inFence(lines, start)
+
+	for row := start; row <= end; row++ {
+		line := lines[row]
+		str := string(line)
+		p := parseLinePrefix(line)
+		text := strings.TrimSpace(str[len(p.quote):])
+
+		if fenceLineRegex.MatchString(str[len(p.quote):]) {
+			fenced = !fenced
+			flush()
+			res = append(res, str)
+			continue
+		}
+
+		if fenced || text == "" || keepLineRegex.MatchString(str[len(p.quote):]) {
+			flush()
+			res = append(res, str)
+			continue
+		}
+
+		if p.isItem || paragraph == 0 || p.quote != prefix.quote {
+			flush()
+			prefix = p
+			text = strings.TrimSpace(str[len(p.first):])
+
+			// an empty list item is kept as it is
+			if text == "" {
+				res = append(res, str)
+				continue
+			}
+		} else if paragraph == 1 && !prefix.isItem {
+			// the rest of a paragraph continues with
+			// the indentation of its second line
+			prefix.next = p.first
+		}
+
+		paragraph++
+		words = append(words, strings.Fields(text)...)
+
+		// a backslash at the end of a line is a hard line break
+		if strings.HasSuffix(text, `\`) {
+			flush()
+		}
+	}
+
+	flush()
+
+	return res
+}
+
+// Format wraps the lines between the rows `start` and `end` at
+// textwidth as a single undo step. If keepCursor is false the
+// cursor moves to the first character of the last formatted line,
+// otherwise it stays where it is
+func (editor *Editor) Format(start, end int, keepCursor bool, cursor textarea.CursorPos) message.StatusBarMsg {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	ta := &editor.Textarea
+	lines := editor.formatLines(ta.Val(), start, end)
+
+	editor.newHistoryEntry()
+	ta.ReplaceLines(start, end, lines)
+
+	if keepCursor {
+		row := min(cursor.Row, ta.LineCount()-1)
+		ta.MoveCursor(row, 0, min(cursor.ColumnOffset, max(ta.LineLength(row)-1, 0)))
+	} else {
+		ta.MoveCursor(start+len(lines)-1, 0, 0)
+		ta.CursorInputStart()
+	}
+
+	editor.updateBufferContent(true)
+	editor.EnterNormalMode(true)
+	ta.RepositionView()
+
+	return message.StatusBarMsg{}
+}
+
+// FormatSelection wraps the selected lines at textwidth
+func (editor *Editor) FormatSelection(keepCursor bool) message.StatusBarMsg {
+	start, end := editor.selectedRows()
+	return editor.Format(start, end, keepCursor, editor.Textarea.AbsCursorPos())
+}
+
+// autoWrap breaks the current line at the last blank before textwidth
+// if the text before the cursor exceeds textwidth. The text after the
+// break continues with the prefix of the line
+func (editor *Editor) autoWrap() {
+	tw := editor.TextWidth()
+	if tw == 0 {
+		return
+	}
+
+	ta := &editor.Textarea
+	row := ta.Line()
+	line := ta.Val()[row]
+	col := min(ta.AbsCursorPos().ColumnOffset, len(line))
+
+	if editor.displayWidth(string(line[:col])) <= tw ||
+		keepLineRegex.MatchString(string(line)) ||
+		inFence(ta.Val(), row) {
+
+		return
+	}
+
+	prefix := parseLinePrefix(line)
+	prefixLen := len([]rune(prefix.first))
+
+	// the blank to break at, the last one before textwidth or
+	// if a word is longer than textwidth the one before the word
+	brk := -1
+	for i := col - 1; i > prefixLen; i-- {
+		if !unicode.IsSpace(line[i]) {
+			continue
+		}
+
+		if brk == -1 {
+			brk = i
+		}
+
+		if editor.displayWidth(string(line[:i])) <= tw {
+			brk = i
+			break
+		}
+	}
+
+	if brk == -1 {
+		return
+	}
+
+	// the white space around the break is removed
+	before, after := brk, brk
+	for before > prefixLen && unicode.IsSpace(line[before-1]) {
+		before--
+	}
+	for after < col && unicode.IsSpace(line[after]) {
+		after++
+	}
+
+	ta.ReplaceLines(row, row, []string{
+		string(line[:before]),
+		prefix.next + string(line[after:]),
+	})
+	ta.MoveCursor(row+1, 0, len([]rune(prefix.next))+col-after)
+}
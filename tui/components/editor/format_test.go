@@ -0,0 +1,102 @@
+package editor
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"bellbird-notes/tui/components/textarea"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestFormat(t *testing.T) {
+	content := strings.Join([]string{
+		"- a list item with quite a few",
+		"  words in it",
+		"> quoted text that goes on and on",
+		"",
+		"```",
+		"code that is longer than twenty columns",
+		"```",
+		"  indented text that is wrapped",
+	}, "\n")
+
+	editor := createTestEditor(t, content)
+	ta := &editor.Textarea
+
+	setLocal(t, editor, "textwidth", "20")
+	editor.ApplyOperator(OperatorFormat, linesRange(0, ta.LineCount()-1))
+
+	expected := []string{
+		"- a list item with",
+		"  quite a few words",
+		"  in it",
+		"> quoted text that",
+		"> goes on and on",
+		"",
+		"```",
+		"code that is longer than twenty columns",
+		"```",
+		"  indented text that",
+		"  is wrapped",
+	}
+
+	if got := strings.Split(ta.Value(), "\n"); !slices.Equal(got, expected) {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+
+	if row := ta.Line(); row != len(expected)-1 {
+		t.Errorf("expected the cursor on the last formatted line, got %d", row)
+	}
+
+	// the whole reflow is undone at once
+	editor.Undo()
+	if got := ta.Value(); got != content {
+		t.Errorf("expected the reflow to be undone, got\n%s", got)
+	}
+
+	// the cursor stays where it was before the motion
+	r := linesRange(0, 3)
+	r.Cursor = textarea.CursorPos{Row: 1, ColumnOffset: 4}
+	editor.ApplyOperator(OperatorFormatKeepCursor, r)
+
+	if row, col := ta.Line(), ta.AbsCursorPos().ColumnOffset; row != 1 || col != 4 {
+		t.Errorf("expected the cursor at 1:4, got %d:%d", row, col)
+	}
+
+	// lines are wrapped while typing
+	ta.SetValue("> ")
+	editor.InsertLineEnd()
+	typeText(editor, "one two three four five")
+	typeKey(editor, tea.KeyEscape)
+
+	expected = []string{"> one two three four", "> five"}
+	if got := strings.Split(ta.Value(), "\n"); !slices.Equal(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestParseLinePrefix(t *testing.T) {
+	tests := []struct {
+		line        string
+		first, next string
+		isItem      bool
+	}{
+		{"plain text", "", "", false},
+		{"  indented", "  ", "  ", false},
+		{"- item", "- ", "  ", true},
+		{"12. item", "12. ", "    ", true},
+		{"- [ ] task", "- [ ] ", "      ", true},
+		{"> quote", "> ", "> ", false},
+		{"> - quoted item", "> - ", ">   ", true},
+	}
+
+	for _, tt := range tests {
+		p := parseLinePrefix([]rune(tt.line))
+		if p.first != tt.first || p.next != tt.next || p.isItem != tt.isItem {
+			t.Errorf("%q: expected (%q, %q, %v), got (%q, %q, %v)",
+				tt.line, tt.first, tt.next, tt.isItem, p.first, p.next, p.isItem)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package editor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"bellbird-notes/app/config"
+	"bellbird-notes/app/debug"
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+	"bellbird-notes/tui/theme"
+)
+
+// parseHighlightRule parses a style followed by a regular expression,
+// e.g. `#ffcb78,bold /\bTODO\b/`. The slashes around the pattern
+// are optional
+func parseHighlightRule(name, value string) (textarea.HighlightRule, error) {
+	spec, pattern, _ := strings.Cut(strings.TrimSpace(value), " ")
+	pattern = strings.TrimSpace(pattern)
+
+	if len(pattern) > 1 && pattern[0] == '/' && pattern[len(pattern)-1] == '/' {
+		pattern = pattern[1 : len(pattern)-1]
+	}
+
+	if pattern == "" {
+		return textarea.HighlightRule{}, fmt.Errorf("missing pattern: %s", value)
+	}
+
+	style, err := theme.ParseStyle(spec)
+	if err != nil {
+		return textarea.HighlightRule{}, err
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return textarea.HighlightRule{}, err
+	}
+
+	return textarea.HighlightRule{
+		Name:  name,
+		Regex: re,
+		Style: style,
+	}, nil
+}
+
+// RefreshHighlights passes the highlights of the config followed
+// by those added with `:match` to the textarea.
+// Invalid highlights of the config are skipped
+func (editor *Editor) RefreshHighlights() {
+	rules := []textarea.HighlightRule{}
+
+	for _, entry := range editor.conf.Entries(config.Highlights) {
+		if strings.TrimSpace(entry.Value) == "" {
+			continue
+		}
+
+		rule, err := parseHighlightRule(entry.Name, entry.Value)
+		if err != nil {
+			debug.LogErr(fmt.Errorf("highlight %s: %w", entry.Name, err))
+			continue
+		}
+
+		rules = append(rules, rule)
+	}
+
+	rules = append(rules, editor.matches...)
+	editor.Textarea.SetHighlightRules(rules)
+}
+
+// AddMatch highlights the matches of a pattern for the rest of the
+// session. The argument is a style followed by the pattern,
+// e.g. `red,bold /ABC-\d+/`
+func (editor *Editor) AddMatch(arg string) message.StatusBarMsg {
+	rule, err := parseHighlightRule(":match", arg)
+	if err != nil {
+		return message.StatusBarMsg{
+			Content: fmt.Sprintf(message.StatusBar.InvalidArgument, err.Error()),
+			Type:    message.Error,
+		}
+	}
+
+	editor.matches = append(editor.matches, rule)
+	editor.RefreshHighlights()
+
+	return message.StatusBarMsg{}
+}
+
+// ClearMatches removes the highlights added with `:match`
+func (editor *Editor) ClearMatches() message.StatusBarMsg {
+	editor.matches = nil
+	editor.RefreshHighlights()
+
+	return message.StatusBarMsg{}
+}
@@ -0,0 +1,63 @@
+package editor
+
+import (
+	"testing"
+)
+
+func TestParseHighlightRule(t *testing.T) {
+	tests := []struct {
+		value, match string
+		ok           bool
+	}{
+		{`#ffcb78,bold /\bTODO\b/`, "a TODO", true},
+		{`red ABC-\d+`, "ABC-12", true},
+		{`red / /`, "a b", true},
+		{"red", "", false},
+		{"red //", "", false},
+		{"nocolour /x/", "", false},
+		{"red /(/", "", false},
+	}
+
+	for _, tt := range tests {
+		rule, err := parseHighlightRule("name", tt.value)
+
+		if (err == nil) != tt.ok {
+			t.Errorf("%q: expected ok to be %v, got %v", tt.value, tt.ok, err)
+			continue
+		}
+
+		if tt.ok && !rule.Regex.MatchString(tt.match) {
+			t.Errorf("%q: expected %s to match %q", tt.value, rule.Regex, tt.match)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	editor := createTestEditor(t, "")
+	ta := &editor.Textarea
+
+	editor.RefreshHighlights()
+	configured := len(ta.HighlightRules())
+
+	editor.AddMatch(`red,bold /ABC-\d+/`)
+
+	if msg := editor.AddMatch("red"); msg.Content == "" {
+		t.Error("expected an error for a match without a pattern")
+	}
+
+	rules := ta.HighlightRules()
+	if len(rules) != configured+1 || rules[len(rules)-1].Name != ":match" {
+		t.Fatalf("expected the match after the %d configured rules, got %v", configured, rules)
+	}
+
+	// the matches are kept when the highlights are refreshed
+	editor.RefreshHighlights()
+	if n := len(ta.HighlightRules()); n != configured+1 {
+		t.Errorf("expected %d rules after refreshing, got %d", configured+1, n)
+	}
+
+	editor.ClearMatches()
+	if n := len(ta.HighlightRules()); n != configured {
+		t.Errorf("expected the matches to be removed, got %d rules", n)
+	}
+}
@@ -0,0 +1,158 @@
+package editor
+
+import (
+	"strings"
+
+	"bellbird-notes/app/config"
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+)
+
+// defaultTabStop is used if the tab stop of the config is invalid
+const defaultTabStop = 4
+
+// TabStop returns the number of columns a tab is displayed with
+func (editor *Editor) TabStop() int {
+	if tabStop := editor.intValue(config.TabStop); tabStop > 0 {
+		return tabStop
+	}
+	return defaultTabStop
+}
+
+// ShiftWidth returns the number of columns `>>` and `<<` shift
+// lines by. A shift width of 0 uses the tab stop
+func (editor *Editor) ShiftWidth() int {
+	if shiftWidth := editor.intValue(config.ShiftWidth); shiftWidth > 0 {
+		return shiftWidth
+	}
+	return editor.TabStop()
+}
+
+// ExpandTab returns whether to indent with spaces instead of tabs
+func (editor *Editor) ExpandTab() bool {
+	expandTab, err := editor.value(config.Editor, config.ExpandTab)
+	return err != nil || expandTab.GetBool()
+}
+
+// AutoIndent returns whether new lines start with the
+// indentation of the line they're inserted from
+func (editor *Editor) AutoIndent() bool {
+	autoIndent, err := editor.value(config.Editor, config.AutoIndent)
+	return err == nil && autoIndent.GetBool()
+}
+
+// intValue returns the value of a number option of the editor section.
+// Returns 0 if it isn't a valid number
+func (editor *Editor) intValue(option config.Option) int {
+	value, err := editor.value(config.Editor, option)
+	if err != nil {
+		return 0
+	}
+
+	n, err := value.GetInt()
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// indentString returns white space that is `width` columns wide.
+// Unless expandtab is set it's made of as many tabs as possible
+func (editor *Editor) indentString(width int) string {
+	if editor.ExpandTab() {
+		return strings.Repeat(" ", width)
+	}
+
+	tabStop := editor.TabStop()
+	return strings.Repeat("\t", width/tabStop) + strings.Repeat(" ", width%tabStop)
+}
+
+// shiftLines shifts the lines from row `start` to row `end` by `count`
+// shift widths, to the left if count is negative. The indentation is
+// rebuilt according to expandtab. Empty lines aren't indented
+func (editor *Editor) shiftLines(start int, end int, count int) {
+	ta := &editor.Textarea
+	tabStop := editor.TabStop()
+
+	for row := start; row <= end; row++ {
+		line := ta.Val()[row]
+
+		if len(line) == 0 {
+			continue
+		}
+
+		width := textarea.IndentWidth(line, tabStop)
+		width = max(0, width+count*editor.ShiftWidth())
+
+		ta.SetIndentation(row, editor.indentString(width))
+	}
+}
+
+// ShiftSelection shifts the selected lines `count` times by the shift
+// width, to the left if left is true, like `>` and `<` in visual mode
+func (editor *Editor) ShiftSelection(count int, left bool) message.StatusBarMsg {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	ta := &editor.Textarea
+	start, end := editor.selectedRows()
+
+	if left {
+		count = -count
+	}
+
+	editor.newHistoryEntry()
+	editor.shiftLines(start, end, count)
+
+	ta.MoveCursor(start, 0, 0)
+	ta.CursorInputStart()
+	editor.EnterNormalMode(true)
+	ta.RepositionView()
+
+	return message.StatusBarMsg{}
+}
+
+// tabString returns the text the tab key inserts at the cursor.
+// With expandtab it's the spaces up to the next tab stop
+func (editor *Editor) tabString() string {
+	if !editor.ExpandTab() {
+		return "\t"
+	}
+
+	ta := &editor.Textarea
+	tabStop := editor.TabStop()
+	line := ta.Val()[ta.Line()]
+	col := len(textarea.ExpandTabs(line[:min(ta.AbsCursorPos().ColumnOffset, len(line))], tabStop))
+
+	return strings.Repeat(" ", tabStop-col%tabStop)
+}
+
+// autoIndent gives the current line the indentation of the line at
+// row `from` if autoindent is set
+func (editor *Editor) autoIndent(from int) {
+	if !editor.AutoIndent() {
+		return
+	}
+
+	ta := &editor.Textarea
+
+	if indent := ta.Indentation(from); indent != "" {
+		ta.SetIndentation(ta.Line(), indent)
+		editor.autoIndented = true
+	}
+}
+
+// removeAutoIndent removes the indentation that has been inserted
+// automatically if nothing has been typed after it
+func (editor *Editor) removeAutoIndent() {
+	ta := &editor.Textarea
+	line := string(ta.Val()[ta.Line()])
+
+	if editor.autoIndented && line != "" && ta.Indentation(ta.Line()) == line {
+		ta.SetIndentation(ta.Line(), "")
+	}
+
+	editor.autoIndented = false
+}
@@ -0,0 +1,79 @@
+package editor
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/components/textarea"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestIndentation(t *testing.T) {
+	editor := createTestEditor(t, "TEST1\nTest2\nTest3\ntest4\ntes5t")
+	ta := &editor.Textarea
+
+	setLocal(t, editor, "autoindent", "true")
+	setLocal(t, editor, "expandtab", "true")
+	setLocal(t, editor, "shiftwidth", "4")
+	setLocal(t, editor, "tabstop", "4")
+
+	editor.ApplyOperator(OperatorIndent, linesRange(0, 1))
+	if got := ta.Value(); got != "    TEST1\n    Test2\nTest3\ntest4\ntes5t" {
+		t.Errorf("expected two indented lines, got %q", got)
+	}
+
+	// the new line gets the indentation of the current line
+	ta.MoveCursor(0, 0, 0)
+	editor.InsertLine(false)
+	typeText(editor, "foo")
+	typeKey(editor, tea.KeyEnter)
+	typeText(editor, "bar")
+	typeKey(editor, tea.KeyEscape)
+
+	if got := ta.LinesStr(1, 2); got != "    foo\n    bar\n" {
+		t.Errorf("expected auto-indented lines, got %q", got)
+	}
+
+	// unused indentation is removed
+	editor.InsertLine(false)
+	typeKey(editor, tea.KeyEscape)
+
+	if got := string(ta.Val()[3]); got != "" {
+		t.Errorf("expected an empty line, got %q", got)
+	}
+
+	editor.DeleteLine()
+	editor.ApplyOperator(OperatorOutdent, linesRange(0, 0))
+
+	if got := string(ta.Val()[0]); got != "TEST1" {
+		t.Errorf("expected the first line to be outdented, got %q", got)
+	}
+
+	// the selection is shifted `count` times
+	ta.MoveCursor(0, 0, 0)
+	editor.EnterVisualMode(textarea.SelectVisualLine)
+	editor.LineDown(false)
+	editor.ShiftSelection(2, false)
+
+	if got := ta.LinesStr(0, 1); got != "        TEST1\n            foo\n" {
+		t.Errorf("expected the selection to be shifted twice, got %q", got)
+	}
+
+	editor.Undo()
+
+	// indentation is rebuilt with tabs
+	setLocal(t, editor, "expandtab", "false")
+	editor.ApplyOperator(OperatorIndent, linesRange(1, 1))
+
+	if got := string(ta.Val()[1]); got != "\t\tfoo" {
+		t.Errorf("expected the line to be indented with tabs, got %q", got)
+	}
+
+	editor.InsertLineStart()
+	typeKey(editor, tea.KeyTab)
+	typeKey(editor, tea.KeyEscape)
+
+	if got := string(ta.Val()[1]); got != "\t\t\tfoo" {
+		t.Errorf("expected a tab to be inserted, got %q", got)
+	}
+}
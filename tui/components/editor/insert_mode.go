@@ -3,12 +3,19 @@ package editor
 import (
 	"strings"
 
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
-	"github.com/charmbracelet/x/input"
 )
 
 func (editor *Editor) handleInsertMode(msg tea.KeyMsg) tea.Cmd {
 	if msg.String() == "esc" {
+		if editor.blockInsert != nil {
+			editor.finishBlockInsert()
+		}
+
+		editor.completion = nil
+		editor.ctrlX = false
+		editor.removeAutoIndent()
 		editor.EnterNormalMode(true)
 		return nil
 	}
@@ -17,23 +24,57 @@ func (editor *Editor) handleInsertMode(msg tea.KeyMsg) tea.Cmd {
 
 	// only allow input when this flag is true.
 	// See tui.updateComponents() for further explanation
-	if editor.CanInsert {
-		if msg.Key().Code == 9 {
-			k := msg.Key()
-			// Just for now, will be setting when tab support is finished
-			tabWidth := 4
-			// simulate soft tabs
-			tabStr := strings.Repeat(string(input.KeySpace), tabWidth)
-			msg = tea.KeyPressMsg{
-				Text:        tabStr,
-				Mod:         k.Mod,
-				Code:        k.Code,
-				ShiftedCode: k.ShiftedCode,
-				BaseCode:    k.BaseCode,
-				IsRepeat:    k.IsRepeat,
+	if !editor.CanInsert {
+		return nil
+	}
+
+	if editor.handleCompletionKey(msg) {
+		editor.autoIndented = false
+		return nil
+	}
+
+	switch {
+	case msg.Key().Code == tea.KeyTab && msg.Key().Mod == 0:
+		editor.autoIndented = false
+		editor.Textarea.InsertString(editor.tabString())
+
+	case key.Matches(msg, editor.Textarea.KeyMap.InsertNewline):
+		ta := &editor.Textarea
+		row := ta.Line()
+		item, isItem := parseListItem(ta.Val()[row])
+		afterMarker := isItem && ta.AbsCursorPos().ColumnOffset >= item.len
+
+		// enter on an empty list item ends the list
+		if afterMarker && item.empty {
+			editor.endList(row)
+			editor.autoIndented = false
+			return nil
+		}
+
+		// a line that only holds the automatic indentation is
+		// left empty and the indentation moves to the new line
+		indent := ta.Indentation(row)
+		editor.removeAutoIndent()
+
+		editor.Textarea, cmd = editor.Textarea.Update(msg)
+
+		if ta.Line() > row {
+			if afterMarker {
+				editor.continueList(row)
+			} else if editor.AutoIndent() && indent != "" {
+				ta.SetIndentation(ta.Line(), indent)
+				editor.autoIndented = true
 			}
 		}
+
+	default:
+		editor.autoIndented = false
 		editor.Textarea, cmd = editor.Textarea.Update(msg)
+
+		// lines are only broken when a word is typed beyond textwidth
+		if text := msg.Key().Text; text != "" && strings.TrimSpace(text) == text {
+			editor.autoWrap()
+		}
 	}
 
 	return cmd
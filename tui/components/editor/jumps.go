@@ -0,0 +1,198 @@
+package editor
+
+import (
+	"fmt"
+	"slices"
+
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+)
+
+// maxJumps is the maximum number of jumps the jump list remembers
+const maxJumps = 100
+
+// Jump is a position in a note the cursor jumped away from.
+// The column offset of Pos is relative to the beginning of the whole line
+type Jump struct {
+	Path string
+	Pos  textarea.CursorPos
+
+	// Line is the id of the jump's line in the textarea while its note
+	// is open. It's 0 if the jump hasn't been tracked yet
+	Line int
+}
+
+// JumpList holds the positions the cursor jumped away from, e.g. with
+// `gg`, a search or by opening another note, so that we can go back
+// with `ctrl+o` and forward again with `ctrl+i`
+type JumpList struct {
+	jumps []Jump
+
+	// index is the jump we're currently at when walking through the list.
+	// It's len(jumps) if we're not walking through the list
+	index int
+}
+
+func NewJumpList() *JumpList {
+	return &JumpList{jumps: []Jump{}}
+}
+
+// Push adds a jump to the end of the list.
+// Older jumps to the same line are removed
+func (list *JumpList) Push(jump Jump) {
+	list.jumps = slices.DeleteFunc(list.jumps, func(j Jump) bool {
+		return j.Path == jump.Path && j.Pos.Row == jump.Pos.Row
+	})
+
+	list.jumps = append(list.jumps, jump)
+
+	if len(list.jumps) > maxJumps {
+		list.jumps = list.jumps[len(list.jumps)-maxJumps:]
+	}
+
+	list.index = len(list.jumps)
+}
+
+// Back returns the jump `count` jumps before the current one.
+// If we're not walking through the list yet, the current position
+// is added first so that we can go forward to it again
+func (list *JumpList) Back(current Jump, count int) (Jump, bool) {
+	if list.index >= len(list.jumps) {
+		list.Push(current)
+		list.index = len(list.jumps) - 1
+	}
+
+	if list.index-count < 0 {
+		return Jump{}, false
+	}
+
+	list.index -= count
+	return list.jumps[list.index], true
+}
+
+// Forward returns the jump `count` jumps after the current one
+func (list *JumpList) Forward(count int) (Jump, bool) {
+	if list.index+count >= len(list.jumps) {
+		return Jump{}, false
+	}
+
+	list.index += count
+	return list.jumps[list.index], true
+}
+
+// All returns all jumps from the oldest to the newest one
+func (list *JumpList) All() []Jump {
+	return list.jumps
+}
+
+// Index returns the index of the jump we're currently at
+func (list *JumpList) Index() int {
+	return list.index
+}
+
+// track gives the jumps of the note with the given path the ids of
+// the lines they are on in the textarea
+func (list *JumpList) track(path string, ta *textarea.Model) {
+	for i, jump := range list.jumps {
+		if jump.Path == path {
+			list.jumps[i].Line = ta.LineID(jump.Pos.Row)
+		}
+	}
+}
+
+// update moves the jumps of the note with the given path to the rows
+// their lines are at in the textarea.
+// Jumps on removed lines stay on their row and follow the line
+// that is there now
+func (list *JumpList) update(path string, ta *textarea.Model) {
+	for i, jump := range list.jumps {
+		if jump.Path != path {
+			continue
+		}
+
+		if row, ok := ta.LineRow(jump.Line); ok {
+			list.jumps[i].Pos.Row = row
+			continue
+		}
+
+		row := min(jump.Pos.Row, ta.LineCount()-1)
+		list.jumps[i].Line = ta.LineID(row)
+	}
+}
+
+// addJump adds the cursor position to the jump list.
+// It's called before the cursor jumps somewhere else
+func (editor *Editor) addJump() {
+	path := editor.CurrentBuffer.Path(false)
+
+	// scratch buffers can't be opened again
+	if path == "" {
+		return
+	}
+
+	editor.Jumps.Push(editor.currentJump())
+}
+
+// currentJump returns a jump to the cursor position
+func (editor *Editor) currentJump() Jump {
+	pos := editor.Textarea.AbsCursorPos()
+
+	return Jump{
+		Path: editor.CurrentBuffer.Path(false),
+		Pos:  pos,
+		Line: editor.Textarea.LineID(pos.Row),
+	}
+}
+
+// JumpBack moves the cursor to an older position in the jump list
+func (editor *Editor) JumpBack(count int) message.StatusBarMsg {
+	if jump, ok := editor.Jumps.Back(editor.currentJump(), count); ok {
+		msg, _ := editor.jumpTo(jump)
+		return msg
+	}
+
+	return message.StatusBarMsg{}
+}
+
+// JumpForward moves the cursor to a newer position in the jump list
+func (editor *Editor) JumpForward(count int) message.StatusBarMsg {
+	if jump, ok := editor.Jumps.Forward(count); ok {
+		msg, _ := editor.jumpTo(jump)
+		return msg
+	}
+
+	return message.StatusBarMsg{}
+}
+
+// jumpTo moves the cursor to the given jump, opening its note if necessary.
+// Returns false if the note can't be opened
+func (editor *Editor) jumpTo(jump Jump) (message.StatusBarMsg, bool) {
+	statusMsg := message.StatusBarMsg{}
+
+	if jump.Path != editor.CurrentBuffer.Path(false) {
+		statusMsg = editor.openBuffer(jump.Path)
+
+		// the note may have been moved or deleted
+		if jump.Path != editor.CurrentBuffer.Path(false) {
+			return message.StatusBarMsg{
+				Content: fmt.Sprintf(message.StatusBar.NoteNotFound, jump.Path),
+				Type:    message.Error,
+			}, false
+		}
+	}
+
+	ta := &editor.Textarea
+	row := min(jump.Pos.Row, ta.LineCount()-1)
+
+	// the jump's line may have moved since the jump has been stored
+	if r, ok := ta.LineRow(jump.Line); ok {
+		row = r
+	}
+
+	ta.MoveCursor(row, 0, min(jump.Pos.ColumnOffset, ta.LineLength(row)))
+	ta.RepositionView()
+	editor.isAtLineEnd = ta.IsAtLineEnd()
+	editor.saveCursorPos()
+
+	return statusMsg, true
+}
@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/components/textarea"
+)
+
+func jumpAt(path string, row int) Jump {
+	return Jump{Path: path, Pos: textarea.CursorPos{Row: row}}
+}
+
+func TestJumpListBackForward(t *testing.T) {
+	list := NewJumpList()
+	list.Push(jumpAt("a.md", 1))
+	list.Push(jumpAt("b.md", 3))
+
+	// older jumps to the same line are replaced
+	list.Push(jumpAt("a.md", 1))
+
+	if got := list.All(); len(got) != 2 || got[0].Path != "b.md" {
+		t.Fatalf("expected the older jump to be removed, got %+v", got)
+	}
+
+	// going back remembers the current position
+	jump, ok := list.Back(jumpAt("a.md", 4), 1)
+	if !ok || jump.Path != "a.md" || jump.Pos.Row != 1 {
+		t.Fatalf("expected to go back to a.md:1, got %+v", jump)
+	}
+
+	if jump, ok = list.Back(jumpAt("a.md", 1), 1); !ok || jump.Path != "b.md" {
+		t.Fatalf("expected to go back to b.md, got %+v", jump)
+	}
+
+	if _, ok = list.Back(jumpAt("b.md", 3), 1); ok {
+		t.Fatal("expected no older jump")
+	}
+
+	if jump, ok = list.Forward(2); !ok || jump.Path != "a.md" || jump.Pos.Row != 4 {
+		t.Fatalf("expected to go forward to the remembered position, got %+v", jump)
+	}
+
+	if _, ok = list.Forward(1); ok {
+		t.Fatal("expected no newer jump")
+	}
+
+	// a new jump ends walking through the list
+	list.Push(jumpAt("c.md", 0))
+
+	if list.Index() != len(list.All()) {
+		t.Errorf("expected index %d, got %d", len(list.All()), list.Index())
+	}
+}
+
+func TestJumpListFollowLines(t *testing.T) {
+	editor := createTestEditor(t, "a\nb\nc\nd\ne")
+	path := editor.CurrentBuffer.Path(false)
+	ta := &editor.Textarea
+
+	for _, row := range []int{1, 4} {
+		ta.MoveCursor(row, 0, 0)
+		editor.addJump()
+	}
+	editor.Jumps.Push(jumpAt("b.md", 4))
+
+	ta.ReplaceLines(1, 1, nil)
+	editor.updatePositions()
+
+	// jumps on removed lines stay on their row
+	want := []int{1, 3, 4}
+	for i, jump := range editor.Jumps.All() {
+		if jump.Pos.Row != want[i] {
+			t.Errorf("jump %d: expected row %d, got %d", i, want[i], jump.Pos.Row)
+		}
+	}
+
+	// and follow the line that is there now
+	ta.CopyLines(0, 0, -1)
+	editor.updatePositions()
+
+	want = []int{2, 4, 4}
+	for i, jump := range editor.Jumps.All() {
+		if jump.Pos.Row != want[i] {
+			t.Errorf("jump %d: expected row %d, got %d", i, want[i], jump.Pos.Row)
+		}
+	}
+
+	if jump := editor.Jumps.All()[0]; jump.Path != path {
+		t.Errorf("expected the jump to be in the current note, got %s", jump.Path)
+	}
+}
@@ -0,0 +1,167 @@
+package editor
+
+import (
+	"bellbird-notes/tui/message"
+)
+
+// RunOnLines moves the cursor to the beginning of each of the given
+// rows and calls fn. All changes are recorded as a single undo step.
+// The lines are tracked while fn changes the text, lines that have
+// been removed are skipped.
+// Returns the status message of the last call that didn't fail or,
+// if every call failed, the message of the last call
+func (editor *Editor) RunOnLines(rows []int, fn func() message.StatusBarMsg) message.StatusBarMsg {
+	// nested calls are part of the outer batch
+	if !editor.batch {
+		editor.newHistoryEntry()
+		editor.batch = true
+
+		defer func() {
+			editor.batch = false
+			if editor.Textarea.Value() != editor.CurrentBuffer.Content {
+				editor.updateBufferContent(true)
+			}
+		}()
+	}
+
+	ta := &editor.Textarea
+
+	ids := make([]int, 0, len(rows))
+	for _, row := range rows {
+		if row >= 0 && row < ta.LineCount() {
+			ids = append(ids, ta.LineID(row))
+		}
+	}
+
+	var statusMsg, errMsg message.StatusBarMsg
+	failed := true
+
+	for _, id := range ids {
+		row, ok := ta.LineRow(id)
+		if !ok {
+			continue
+		}
+
+		ta.MoveCursor(row, 0, 0)
+
+		if msg := fn(); msg.Type == message.Error {
+			errMsg = msg
+		} else {
+			statusMsg = msg
+			failed = false
+		}
+	}
+
+	editor.isAtLineEnd = ta.IsAtLineEnd()
+	editor.saveCursorPos()
+
+	if failed {
+		return errMsg
+	}
+
+	return statusMsg
+}
+
+// DeleteRows deletes the lines from row `start` to row `end` and
+// copies them to the selected register
+func (editor *Editor) DeleteRows(start int, end int) message.StatusBarMsg {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	ta := &editor.Textarea
+	editor.newHistoryEntry()
+	editor.Cut(ta.LinesStr(start, end), true)
+
+	// there's always at least one line
+	var lines []string
+	if start == 0 && end >= ta.LineCount()-1 {
+		lines = []string{""}
+	}
+
+	ta.ReplaceLines(start, end, lines)
+	editor.renumberList(start)
+	ta.MoveCursor(min(start, ta.LineCount()-1), 0, 0)
+	ta.CursorInputStart()
+	ta.RepositionView()
+
+	editor.isAtLineEnd = ta.IsAtLineEnd()
+	editor.updateBufferContent(true)
+
+	return message.StatusBarMsg{}
+}
+
+// MoveRows moves the lines from row `start` to row `end` below the line
+// at row `dest`. A dest of -1 moves them above the first line.
+// The cursor ends up on the last moved line
+func (editor *Editor) MoveRows(start int, end int, dest int) message.StatusBarMsg {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	if dest >= start && dest < end {
+		return message.StatusBarMsg{
+			Content: message.StatusBar.MoveIntoItself,
+			Type:    message.Error,
+		}
+	}
+
+	ta := &editor.Textarea
+	editor.newHistoryEntry()
+	ta.MoveLines(start, end, dest)
+	ta.CursorInputStart()
+	ta.RepositionView()
+
+	editor.isAtLineEnd = ta.IsAtLineEnd()
+	editor.updateBufferContent(true)
+
+	return message.StatusBarMsg{}
+}
+
+// CopyRows inserts a copy of the lines from row `start` to row `end`
+// below the line at row `dest`. A dest of -1 inserts the copy above the
+// first line. The cursor ends up on the last copied line
+func (editor *Editor) CopyRows(start int, end int, dest int) message.StatusBarMsg {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	ta := &editor.Textarea
+	editor.newHistoryEntry()
+	ta.CopyLines(start, end, dest)
+	ta.CursorInputStart()
+	ta.RepositionView()
+
+	editor.isAtLineEnd = ta.IsAtLineEnd()
+	editor.updateBufferContent(true)
+
+	return message.StatusBarMsg{}
+}
+
+// JoinRows joins the lines from row `start` to row `end`. A range of one
+// line is joined with the line below. If spaces is true the lines are
+// separated by a single space like with `J`
+func (editor *Editor) JoinRows(start int, end int, spaces bool) message.StatusBarMsg {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	ta := &editor.Textarea
+
+	if start == end {
+		end++
+	}
+
+	if end >= ta.LineCount() {
+		return message.StatusBarMsg{}
+	}
+
+	editor.newHistoryEntry()
+	ta.JoinLines(start, end, spaces)
+	ta.RepositionView()
+
+	editor.isAtLineEnd = ta.IsAtLineEnd()
+	editor.updateBufferContent(true)
+
+	return message.StatusBarMsg{}
+}
@@ -0,0 +1,303 @@
+package editor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+)
+
+// ListType is the kind of markdown list lines are turned into
+type ListType string
+
+const (
+	BulletList   ListType = "bullet"
+	NumberedList ListType = "numbered"
+	CheckboxList ListType = "checkbox"
+)
+
+// listItemRegex matches the indentation and the marker of a markdown
+// list item, e.g. `- `, `* `, `1. `, `2) ` or `- [ ] `
+var listItemRegex = regexp.MustCompile(
+	`^([ \t]*)(?:([-*+])|(\d{1,9})([.)]))[ \t]+(?:(\[[ xX]\])(?:[ \t]+|$))?`,
+)
+
+// listItem is the beginning of a line that is a markdown list item
+type listItem struct {
+	indent string
+
+	// bullet is `-`, `*` or `+`, it's empty for ordered lists
+	bullet string
+
+	// number and delimiter, `.` or `)`, of ordered list items
+	number    int
+	delimiter string
+
+	// checked is true if the checkbox is checked
+	checkbox, checked bool
+
+	// len is the number of runes of the indentation and the marker
+	len int
+
+	// empty is true if there's no text after the marker
+	empty bool
+}
+
+// parseListItem returns the list item the given line starts with
+func parseListItem(line []rune) (listItem, bool) {
+	str := string(line)
+	m := listItemRegex.FindStringSubmatch(str)
+
+	if m == nil {
+		return listItem{}, false
+	}
+
+	item := listItem{
+		indent:    m[1],
+		bullet:    m[2],
+		delimiter: m[4],
+		checkbox:  m[5] != "",
+		checked:   m[5] != "" && m[5] != "[ ]",
+		len:       len([]rune(m[0])),
+		empty:     strings.TrimSpace(str[len(m[0]):]) == "",
+	}
+
+	if m[3] != "" {
+		item.number, _ = strconv.Atoi(m[3])
+	}
+
+	return item, true
+}
+
+// ordered returns whether the item belongs to a numbered list
+func (item listItem) ordered() bool {
+	return item.bullet == ""
+}
+
+// numberLen returns the number of runes of the indentation,
+// the number and the delimiter of an ordered list item
+func (item listItem) numberLen() int {
+	return len([]rune(item.indent)) + len(strconv.Itoa(item.number)) + len(item.delimiter)
+}
+
+// marker returns the bullet or the number and delimiter of the item
+func (item listItem) marker() string {
+	if item.ordered() {
+		return strconv.Itoa(item.number) + item.delimiter
+	}
+	return item.bullet
+}
+
+// next returns the marker of the item that follows the item
+func (item listItem) next() string {
+	next := item
+	next.number++
+
+	marker := item.indent + next.marker() + " "
+
+	if item.checkbox {
+		marker += "[ ] "
+	}
+
+	return marker
+}
+
+// continueList starts the current line with the marker of the item
+// that follows the list item at row `from`. The white space the line
+// begins with is replaced. Returns false if there's no list item
+func (editor *Editor) continueList(from int) bool {
+	ta := &editor.Textarea
+
+	item, ok := parseListItem(ta.Val()[from])
+	if !ok {
+		return false
+	}
+
+	row := ta.Line()
+	ta.ReplacePrefix(row, len([]rune(ta.Indentation(row))), item.next())
+
+	if item.ordered() {
+		editor.renumberList(row)
+	}
+
+	return true
+}
+
+// endList removes the marker of the empty list item at `row`
+// which ends the list
+func (editor *Editor) endList(row int) {
+	ta := &editor.Textarea
+	ta.ReplacePrefix(row, len(ta.Val()[row]), "")
+}
+
+// renumberList renumbers the items of the numbered list the item at
+// `row` belongs to, starting with the number of the first item.
+// Nested lists and lines with a deeper indentation don't interrupt the
+// list, blank lines and lines with less indentation end it
+func (editor *Editor) renumberList(row int) {
+	ta := &editor.Textarea
+	lines := ta.Val()
+
+	if row < 0 || row >= len(lines) {
+		return
+	}
+
+	item, ok := parseListItem(lines[row])
+	if !ok || !item.ordered() {
+		return
+	}
+
+	tabStop := editor.TabStop()
+	width := textarea.IndentWidth(lines[row], tabStop)
+
+	// sibling returns whether the line at `r` is an item of the list.
+	// Returns false and whether the list goes on otherwise
+	sibling := func(r int) (listItem, bool, bool) {
+		line := lines[r]
+
+		if strings.TrimSpace(string(line)) == "" {
+			return listItem{}, false, false
+		}
+
+		w := textarea.IndentWidth(line, tabStop)
+		if w > width {
+			return listItem{}, false, true
+		}
+
+		it, ok := parseListItem(line)
+		if w == width && ok && it.ordered() && it.delimiter == item.delimiter {
+			return it, true, true
+		}
+
+		return listItem{}, false, false
+	}
+
+	first := row
+	for r := row - 1; r >= 0; r-- {
+		_, isItem, goesOn := sibling(r)
+		if !goesOn {
+			break
+		}
+
+		if isItem {
+			first = r
+		}
+	}
+
+	number := -1
+
+	for r := first; r < len(lines); r++ {
+		it, isItem, goesOn := sibling(r)
+		if !goesOn {
+			break
+		}
+
+		if !isItem {
+			continue
+		}
+
+		if number < 0 {
+			number = it.number
+		}
+
+		if it.number != number {
+			prefix := it.indent + strconv.Itoa(number) + it.delimiter
+			ta.ReplacePrefix(r, it.numberLen(), prefix)
+		}
+
+		number++
+	}
+}
+
+// MakeList turns the selected lines into a list of the given type.
+// Existing list markers are replaced, if every line is already an item
+// of that type the markers are removed instead.
+// Blank lines are skipped and nested lines are numbered separately
+func (editor *Editor) MakeList(listType ListType) message.StatusBarMsg {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	ta := &editor.Textarea
+	start, end := editor.selectedRows()
+	lines := ta.Val()
+	remove := true
+
+	for row := start; row <= end; row++ {
+		if strings.TrimSpace(string(lines[row])) == "" {
+			continue
+		}
+
+		if item, ok := parseListItem(lines[row]); !ok || item.listType() != listType {
+			remove = false
+			break
+		}
+	}
+
+	editor.newHistoryEntry()
+
+	tabStop := editor.TabStop()
+	// the next number of every indentation width
+	numbers := map[int]int{}
+
+	for row := start; row <= end; row++ {
+		line := lines[row]
+
+		if strings.TrimSpace(string(line)) == "" {
+			continue
+		}
+
+		indent := ta.Indentation(row)
+		n := len([]rune(indent))
+
+		if item, ok := parseListItem(line); ok {
+			n = item.len
+		}
+
+		if remove {
+			ta.ReplacePrefix(row, n, indent)
+			continue
+		}
+
+		width := textarea.IndentWidth(line, tabStop)
+		for w := range numbers {
+			if w > width {
+				delete(numbers, w)
+			}
+		}
+
+		numbers[width]++
+		ta.ReplacePrefix(row, n, indent+listType.marker(numbers[width]))
+	}
+
+	ta.MoveCursor(start, 0, 0)
+	ta.CursorInputStart()
+	editor.EnterNormalMode(true)
+	ta.RepositionView()
+
+	return message.StatusBarMsg{}
+}
+
+// listType returns the type of the list the item belongs to
+func (item listItem) listType() ListType {
+	switch {
+	case item.checkbox:
+		return CheckboxList
+	case item.ordered():
+		return NumberedList
+	}
+	return BulletList
+}
+
+// marker returns the marker of the list item with the given number
+func (listType ListType) marker(number int) string {
+	switch listType {
+	case NumberedList:
+		return strconv.Itoa(number) + ". "
+	case CheckboxList:
+		return "- [ ] "
+	}
+	return "- "
+}
@@ -0,0 +1,81 @@
+package editor
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/components/textarea"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestLists(t *testing.T) {
+	editor := createTestEditor(t, "TEST1\nTest2\nTest3\ntest4\ntes5t")
+	ta := &editor.Textarea
+
+	editor.EnterVisualMode(textarea.SelectVisualLine)
+	editor.LineDown(false)
+	editor.MakeList(NumberedList)
+
+	if got := ta.Value(); got != "1. TEST1\n2. Test2\nTest3\ntest4\ntes5t" {
+		t.Errorf("expected a numbered list, got %q", got)
+	}
+
+	// new items continue the list and following items are renumbered
+	editor.GoToLine(2)
+	editor.InsertLineEnd()
+	typeKey(editor, tea.KeyEnter)
+	typeText(editor, "foo")
+	editor.EnterNormalMode(true)
+
+	editor.GoToTop()
+	editor.InsertLine(false)
+	typeText(editor, "bar")
+	editor.EnterNormalMode(true)
+
+	if got := ta.LinesStr(0, 3); got != "1. TEST1\n2. bar\n3. Test2\n4. foo\n" {
+		t.Errorf("expected the list to be continued, got %q", got)
+	}
+
+	editor.DeleteLine()
+
+	if got := ta.LinesStr(0, 2); got != "1. TEST1\n2. Test2\n3. foo\n" {
+		t.Errorf("expected the list to be renumbered, got %q", got)
+	}
+
+	// enter on an empty item ends the list
+	editor.GoToLine(3)
+	editor.InsertLineEnd()
+	typeKey(editor, tea.KeyEnter)
+	typeKey(editor, tea.KeyEnter)
+	editor.EnterNormalMode(true)
+
+	if got := ta.LinesStr(2, 4); got != "3. foo\n\nTest3\n" {
+		t.Errorf("expected the list to end, got %q", got)
+	}
+
+	editor.GoToTop()
+	editor.EnterVisualMode(textarea.SelectVisualLine)
+	editor.LineDown(false)
+	editor.MakeList(CheckboxList)
+
+	editor.GoToLine(2)
+	editor.InsertLineEnd()
+	typeKey(editor, tea.KeyEnter)
+	typeText(editor, "baz")
+	editor.EnterNormalMode(true)
+
+	if got := ta.LinesStr(0, 3); got != "- [ ] TEST1\n- [ ] Test2\n- [ ] baz\n3. foo\n" {
+		t.Errorf("expected a checkbox list, got %q", got)
+	}
+
+	// converting a list into the same type removes the markers
+	editor.GoToTop()
+	editor.EnterVisualMode(textarea.SelectVisualLine)
+	editor.LineDown(false)
+	editor.LineDown(false)
+	editor.MakeList(CheckboxList)
+
+	if got := ta.LinesStr(0, 2); got != "TEST1\nTest2\nbaz\n" {
+		t.Errorf("expected the markers to be removed, got %q", got)
+	}
+}
@@ -0,0 +1,297 @@
+package editor
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"bellbird-notes/app/debug"
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+)
+
+// Mark is a position in a note that can be jumped to with `'` or "`".
+// The column offset of Pos is relative to the beginning of the whole line
+type Mark struct {
+	Name rune
+	Path string
+	Pos  textarea.CursorPos
+
+	// Line is the id of the mark's line in the textarea while its note
+	// is open, so that the mark follows its line when lines above it
+	// change. It's 0 if the mark hasn't been tracked yet
+	Line int
+}
+
+// IsGlobal returns whether the mark is one of the marks `A`-`Z`
+// which can be jumped to from any note
+func (mark Mark) IsGlobal() bool {
+	return mark.Name >= 'A' && mark.Name <= 'Z'
+}
+
+// Marks holds the marks `a`-`z` of every note and the global marks `A`-`Z`
+type Marks struct {
+	// local holds the marks `a`-`z` per note path
+	local map[string]map[rune]Mark
+
+	global map[rune]Mark
+}
+
+func NewMarks() *Marks {
+	return &Marks{
+		local:  make(map[string]map[rune]Mark),
+		global: make(map[rune]Mark),
+	}
+}
+
+// ValidMark returns whether the given name is a valid mark name.
+// `<` and `>` are the beginning and the end of the last selection
+func ValidMark(name rune) bool {
+	return (name >= 'a' && name <= 'z') ||
+		(name >= 'A' && name <= 'Z') ||
+		name == '<' || name == '>'
+}
+
+// Set sets the mark with the given name to the given position.
+// Returns false if the name is not a valid mark name
+func (marks *Marks) Set(name rune, path string, pos textarea.CursorPos) bool {
+	return marks.set(Mark{Name: name, Path: path, Pos: pos})
+}
+
+// set stores the given mark, replacing the one with the same name
+func (marks *Marks) set(mark Mark) bool {
+	if !ValidMark(mark.Name) {
+		return false
+	}
+
+	name, path := mark.Name, mark.Path
+
+	if mark.IsGlobal() {
+		marks.global[name] = mark
+		return true
+	}
+
+	if marks.local[path] == nil {
+		marks.local[path] = make(map[rune]Mark)
+	}
+
+	marks.local[path][name] = mark
+	return true
+}
+
+// Get returns the mark with the given name.
+// Local marks are looked up in the note with the given path
+func (marks *Marks) Get(name rune, path string) (Mark, bool) {
+	if mark, ok := marks.global[name]; ok {
+		return mark, true
+	}
+
+	mark, ok := marks.local[path][name]
+	return mark, ok
+}
+
+// All returns the local marks of the note with the given path
+// followed by all global marks, sorted by name
+func (marks *Marks) All(path string) []Mark {
+	local := slices.Collect(maps.Values(marks.local[path]))
+	global := slices.Collect(maps.Values(marks.global))
+
+	byName := func(a, b Mark) int { return int(a.Name - b.Name) }
+	slices.SortFunc(local, byName)
+	slices.SortFunc(global, byName)
+
+	return append(local, global...)
+}
+
+// track gives the marks of the note with the given path the ids of
+// the lines they are on in the textarea
+func (marks *Marks) track(path string, ta *textarea.Model) {
+	for name, mark := range marks.local[path] {
+		mark.Line = ta.LineID(mark.Pos.Row)
+		marks.local[path][name] = mark
+	}
+
+	for name, mark := range marks.global {
+		if mark.Path == path {
+			mark.Line = ta.LineID(mark.Pos.Row)
+			marks.global[name] = mark
+		}
+	}
+}
+
+// update moves the marks of the note with the given path to the rows
+// their lines are at in the textarea.
+// Marks on removed lines are deleted, marks that aren't tracked yet,
+// e.g. because they have just been restored, start to be tracked
+func (marks *Marks) update(path string, ta *textarea.Model) {
+	updated := func(mark Mark) (Mark, bool) {
+		if mark.Line == 0 {
+			mark.Line = ta.LineID(mark.Pos.Row)
+			return mark, true
+		}
+
+		row, ok := ta.LineRow(mark.Line)
+		mark.Pos.Row = row
+		return mark, ok
+	}
+
+	for name, mark := range marks.local[path] {
+		if mark, ok := updated(mark); ok {
+			marks.local[path][name] = mark
+		} else {
+			delete(marks.local[path], name)
+		}
+	}
+
+	for name, mark := range marks.global {
+		if mark.Path != path {
+			continue
+		}
+
+		if mark, ok := updated(mark); ok {
+			marks.global[name] = mark
+		} else {
+			delete(marks.global, name)
+		}
+	}
+}
+
+// Serialize returns all marks encoded as strings so that they
+// can be stored in the state file.
+// Format: <name> <row> <column> <path>
+func (marks *Marks) Serialize() []string {
+	entries := []string{}
+
+	serialize := func(mark Mark) {
+		entries = append(entries, fmt.Sprintf(
+			"%c %d %d %s",
+			mark.Name,
+			mark.Pos.Row,
+			mark.Pos.ColumnOffset,
+			mark.Path,
+		))
+	}
+
+	for _, local := range marks.local {
+		for _, mark := range local {
+			serialize(mark)
+		}
+	}
+
+	for _, mark := range marks.global {
+		serialize(mark)
+	}
+
+	slices.Sort(entries)
+	return entries
+}
+
+// Restore restores marks from entries created by Serialize.
+// Invalid entries are skipped
+func (marks *Marks) Restore(entries []string) {
+	for _, entry := range entries {
+		fields := strings.SplitN(entry, " ", 4)
+		if len(fields) < 4 {
+			continue
+		}
+
+		row, errRow := strconv.Atoi(fields[1])
+		col, errCol := strconv.Atoi(fields[2])
+
+		if errRow != nil || errCol != nil {
+			debug.LogDebug("invalid mark", entry)
+			continue
+		}
+
+		name := []rune(fields[0])
+		if len(name) != 1 {
+			continue
+		}
+
+		marks.Set(name[0], fields[3], textarea.CursorPos{
+			Row:          row,
+			ColumnOffset: col,
+		})
+	}
+}
+
+// SetMark sets the mark with the given name to the cursor position
+func (editor *Editor) SetMark(name rune) message.StatusBarMsg {
+	editor.setMark(name, editor.Textarea.AbsCursorPos())
+	return message.StatusBarMsg{}
+}
+
+// setMark sets the mark with the given name to the given position
+// in the current note
+func (editor *Editor) setMark(name rune, pos textarea.CursorPos) {
+	editor.Marks.set(Mark{
+		Name: name,
+		Path: editor.CurrentBuffer.Path(false),
+		Pos:  pos,
+		Line: editor.Textarea.LineID(pos.Row),
+	})
+}
+
+// setVisualMarks sets the marks `<` and `>` to the beginning and the end
+// of the current selection
+func (editor *Editor) setVisualMarks() {
+	sel := editor.Textarea.Selection
+	if editor.CurrentBuffer == nil || sel.Mode == textarea.SelectNone {
+		return
+	}
+
+	start := textarea.CursorPos{Row: sel.StartRow, ColumnOffset: sel.StartCol}
+	end := editor.Textarea.AbsCursorPos()
+
+	if start.GreaterThan(end) {
+		start, end = end, start
+	}
+
+	editor.setMark('<', start)
+	editor.setMark('>', end)
+}
+
+// JumpToMark moves the cursor to the mark with the given name.
+// If exact is false it moves to the first non-blank character of the
+// mark's line. Global marks open the note they have been set in
+func (editor *Editor) JumpToMark(name rune, exact bool) message.StatusBarMsg {
+	mark, ok := editor.Marks.Get(name, editor.CurrentBuffer.Path(false))
+	if !ok {
+		return message.StatusBarMsg{
+			Content: message.StatusBar.MarkNotSet,
+			Type:    message.Error,
+		}
+	}
+
+	editor.addJump()
+
+	statusMsg, ok := editor.jumpTo(Jump{Path: mark.Path, Pos: mark.Pos, Line: mark.Line})
+	if ok && !exact {
+		editor.Textarea.CursorInputStart()
+		editor.isAtLineEnd = editor.Textarea.IsAtLineEnd()
+		editor.saveCursorPos()
+	}
+
+	return statusMsg
+}
+
+// trackPositions gives the marks and jumps of the current note the ids
+// of their lines. It's called whenever the content of the textarea has
+// been replaced, since the lines get new ids then
+func (editor *Editor) trackPositions() {
+	path := editor.CurrentBuffer.Path(false)
+
+	editor.Marks.track(path, &editor.Textarea)
+	editor.Jumps.track(path, &editor.Textarea)
+}
+
+// updatePositions moves the marks and jumps of the current note
+// to the rows their lines are at after the note has changed
+func (editor *Editor) updatePositions() {
+	path := editor.CurrentBuffer.Path(false)
+
+	editor.Marks.update(path, &editor.Textarea)
+	editor.Jumps.update(path, &editor.Textarea)
+}
@@ -0,0 +1,121 @@
+package editor
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/components/textarea"
+)
+
+func TestMarksSerialize(t *testing.T) {
+	marks := NewMarks()
+	marks.Set('a', "/notes/a.md", textarea.CursorPos{Row: 2, ColumnOffset: 3})
+	marks.Set('A', "/notes/with space.md", textarea.CursorPos{Row: 5, ColumnOffset: 1})
+
+	restored := NewMarks()
+	restored.Restore(marks.Serialize())
+
+	tests := []struct {
+		name rune
+		path string
+		want Mark
+	}{
+		{'a', "/notes/a.md", Mark{Name: 'a', Path: "/notes/a.md", Pos: textarea.CursorPos{Row: 2, ColumnOffset: 3}}},
+		{'A', "/notes/a.md", Mark{Name: 'A', Path: "/notes/with space.md", Pos: textarea.CursorPos{Row: 5, ColumnOffset: 1}}},
+	}
+
+	for _, tt := range tests {
+		got, ok := restored.Get(tt.name, tt.path)
+		if !ok {
+			t.Fatalf("mark %c: expected mark to be restored", tt.name)
+		}
+
+		if got.Name != tt.want.Name || got.Path != tt.want.Path ||
+			got.Pos.Row != tt.want.Pos.Row ||
+			got.Pos.ColumnOffset != tt.want.Pos.ColumnOffset {
+
+			t.Errorf("mark %c: expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+
+	// local marks stay local to their note
+	if _, ok := restored.Get('a', "/notes/b.md"); ok {
+		t.Error("expected local mark not to be found in another note")
+	}
+}
+
+func TestMarksFollowLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(ta *textarea.Model)
+		// the rows of the marks on the rows 0, 1, 3 and 4 after the change,
+		// -1 if the mark has been removed
+		want [4]int
+	}{
+		{"delete a line", func(ta *textarea.Model) { ta.ReplaceLines(1, 1, nil) }, [4]int{0, -1, 2, 3}},
+		{"insert a line", func(ta *textarea.Model) { ta.CopyLines(0, 0, 1) }, [4]int{0, 1, 4, 5}},
+		{"move a line down", func(ta *textarea.Model) { ta.MoveLines(0, 0, 3) }, [4]int{3, 0, 2, 4}},
+		{"move a line up", func(ta *textarea.Model) { ta.MoveLines(3, 3, 0) }, [4]int{0, 2, 1, 4}},
+	}
+
+	names := [4]rune{'a', 'b', 'd', 'E'}
+	rows := [4]int{0, 1, 3, 4}
+
+	for _, tt := range tests {
+		editor := createTestEditor(t, "a\nb\nc\nd\ne")
+		path := editor.CurrentBuffer.Path(false)
+
+		for i, name := range names {
+			editor.setMark(name, textarea.CursorPos{Row: rows[i], ColumnOffset: 1})
+		}
+
+		// global marks of other notes aren't moved
+		editor.Marks.Set('Z', "/notes/b.md", textarea.CursorPos{Row: 1})
+
+		tt.change(&editor.Textarea)
+		editor.updatePositions()
+
+		for i, name := range names {
+			mark, ok := editor.Marks.Get(name, path)
+
+			if tt.want[i] < 0 {
+				if ok {
+					t.Errorf("%s: expected mark %c to be removed", tt.name, name)
+				}
+				continue
+			}
+
+			if !ok || mark.Pos.Row != tt.want[i] || mark.Pos.ColumnOffset != 1 {
+				t.Errorf("%s: expected mark %c on row %d, got %+v (%v)",
+					tt.name, name, tt.want[i], mark.Pos, ok)
+			}
+		}
+
+		if mark, _ := editor.Marks.Get('Z', path); mark.Pos.Row != 1 {
+			t.Errorf("%s: expected mark of another note to stay, got row %d", tt.name, mark.Pos.Row)
+		}
+	}
+}
+
+func TestMarksTrackRestored(t *testing.T) {
+	editor := createTestEditor(t, "a\nb\nc")
+	path := editor.CurrentBuffer.Path(false)
+
+	// restored marks don't know the ids of their lines yet
+	editor.Marks.Set('a', path, textarea.CursorPos{Row: 1})
+	editor.updatePositions()
+
+	editor.Textarea.CopyLines(0, 0, -1)
+	editor.updatePositions()
+
+	if mark, ok := editor.Marks.Get('a', path); !ok || mark.Pos.Row != 2 {
+		t.Errorf("expected the restored mark to follow its line to row 2, got %+v (%v)", mark.Pos, ok)
+	}
+
+	// replacing the content gives the lines new ids
+	editor.SetContent()
+	editor.updatePositions()
+
+	if mark, ok := editor.Marks.Get('a', path); !ok || mark.Pos.Row != 2 {
+		t.Errorf("expected the mark to stay on row 2, got %+v (%v)", mark.Pos, ok)
+	}
+}
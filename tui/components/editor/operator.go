@@ -0,0 +1,154 @@
+package editor
+
+import (
+	"unicode"
+
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+)
+
+// Operator is applied to the text a motion moves over or a text object
+// selects, e.g. `d` in `dw` or `gU` in `gUiw`
+type Operator int
+
+const (
+	OperatorDelete Operator = iota
+	OperatorChange
+	OperatorYank
+	OperatorLowerCase
+	OperatorUpperCase
+	OperatorToggleCase
+	OperatorIndent
+	OperatorOutdent
+	OperatorFormat
+	OperatorFormatKeepCursor
+)
+
+// OperatorRange is the range of text an operator is applied to.
+// Column offsets are relative to the beginning of the whole line
+// and the character at End is not part of the range.
+// For linewise ranges only the rows matter and both the start and
+// the end row are included.
+type OperatorRange struct {
+	Start    textarea.CursorPos
+	End      textarea.CursorPos
+	Linewise bool
+
+	// Cursor is the cursor position before the motion
+	Cursor textarea.CursorPos
+}
+
+// ApplyOperator applies the given operator to the given range.
+// Deleted and changed text is stored in the registers just like yanked text.
+func (editor *Editor) ApplyOperator(op Operator, r OperatorRange) message.StatusBarMsg {
+	if op != OperatorYank && !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	ta := &editor.Textarea
+
+	if op == OperatorFormat || op == OperatorFormatKeepCursor {
+		return editor.Format(r.Start.Row, r.End.Row, op == OperatorFormatKeepCursor, r.Cursor)
+	}
+
+	// The motion has already moved the cursor, move it back to the
+	// start of the range so that undo restores the correct position
+	ta.MoveCursor(r.Start.Row, 0, r.Start.ColumnOffset)
+
+	if op == OperatorYank {
+		editor.Registers.Yank(editor.operatorRangeStr(r), r.Linewise)
+		editor.saveCursorPos()
+		return editor.ResetSelectedRowsCount()
+	}
+
+	editor.newHistoryEntry()
+
+	switch op {
+	case OperatorDelete:
+		editor.Cut(editor.operatorRangeStr(r), r.Linewise)
+
+		if r.Linewise {
+			ta.DeleteLines(r.End.Row-r.Start.Row+1, false)
+			editor.renumberList(r.Start.Row)
+			ta.CursorInputStart()
+		} else {
+			ta.DeleteRange(r.Start, r.End)
+		}
+
+	case OperatorChange:
+		editor.Cut(editor.operatorRangeStr(r), r.Linewise)
+
+		if r.Linewise {
+			// keep the first line so that we can start typing on it
+			ta.MoveCursor(r.Start.Row+1, 0, 0)
+			ta.DeleteLines(r.End.Row-r.Start.Row, false)
+			ta.DeleteRange(
+				textarea.CursorPos{Row: r.Start.Row},
+				textarea.CursorPos{
+					Row:          r.Start.Row,
+					ColumnOffset: ta.LineLength(r.Start.Row),
+				},
+			)
+		} else {
+			ta.DeleteRange(r.Start, r.End)
+		}
+
+		// the history entry is updated when leaving insert mode
+		// so that the change and the inserted text are undone at once
+		editor.EnterInsertMode(false)
+		return editor.ResetSelectedRowsCount()
+
+	case OperatorLowerCase, OperatorUpperCase, OperatorToggleCase:
+		start, end := r.Start, r.End
+
+		if r.Linewise {
+			start.ColumnOffset = 0
+			end.ColumnOffset = ta.LineLength(end.Row)
+		}
+
+		ta.MapRunesInRange(start, end, caseMapper(op))
+		ta.MoveCursor(start.Row, 0, start.ColumnOffset)
+
+	case OperatorIndent:
+		editor.shiftLines(r.Start.Row, r.End.Row, 1)
+
+	case OperatorOutdent:
+		editor.shiftLines(r.Start.Row, r.End.Row, -1)
+
+		ta.MoveCursor(r.Start.Row, 0, 0)
+		ta.CursorInputStart()
+	}
+
+	editor.updateBufferContent(true)
+	editor.EnterNormalMode(true)
+	ta.RepositionView()
+
+	return editor.ResetSelectedRowsCount()
+}
+
+// operatorRangeStr returns the text within the given range.
+// Linewise text has a trailing new line so that it's pasted
+// on a new line
+func (editor *Editor) operatorRangeStr(r OperatorRange) string {
+	if r.Linewise {
+		return editor.Textarea.LinesStr(r.Start.Row, r.End.Row)
+	}
+	return editor.Textarea.RangeStr(r.Start, r.End)
+}
+
+// caseMapper returns the function that changes the case of a rune
+// according to the given operator
+func caseMapper(op Operator) func(rune) rune {
+	switch op {
+	case OperatorUpperCase:
+		return unicode.ToUpper
+	case OperatorToggleCase:
+		return func(r rune) rune {
+			if unicode.IsUpper(r) {
+				return unicode.ToLower(r)
+			}
+			return unicode.ToUpper(r)
+		}
+	}
+	return unicode.ToLower
+}
@@ -1,6 +1,11 @@
 package editor
 
 import (
+	"fmt"
+
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
@@ -40,7 +45,127 @@ func (editor *Editor) handleSearchMode(msg tea.KeyMsg) tea.Cmd {
 	return cmd
 }
 
+// setSearchQuery sets the query of the search using the search options
+// of the config. The options can be overridden for a single search by
+// prefixing the query with `\c` to ignore the case, `\C` to respect it,
+// `\v` to search for a regular expression or `\V` to search literally
+func (editor *Editor) setSearchQuery(query string) {
+	search := &editor.Textarea.Search
+
+	search.IgnoreCase = editor.SearchIgnoreCase()
+	search.SmartCase = editor.SearchSmartCase()
+	search.Regex = editor.SearchRegex()
+
+	for len(query) >= 2 && query[0] == '\\' {
+		switch query[1] {
+		case 'c':
+			search.IgnoreCase = true
+			search.SmartCase = false
+		case 'C':
+			search.IgnoreCase = false
+		case 'v':
+			search.Regex = true
+		case 'V':
+			search.Regex = false
+		default:
+			search.Query = query
+			return
+		}
+
+		query = query[2:]
+	}
+
+	search.Query = query
+}
+
+// StartSearch prepares a new search. While typing, the cursor moves
+// to the nearest match and back to where it was if the search is
+// cancelled. If backward is true, the search goes backward like `?`
+func (editor *Editor) StartSearch(backward bool) {
+	origin := editor.Textarea.AbsCursorPos()
+	editor.searchOrigin = &origin
+	editor.Textarea.Search = textarea.Search{Backward: backward}
+}
+
+// incSearch moves the cursor to the match nearest to the position the
+// search started at, or back to that position if there's no match
+func (editor *Editor) incSearch() {
+	origin := editor.searchOrigin
+	if origin == nil {
+		return
+	}
+
+	ta := &editor.Textarea
+	ta.UpdateSearchMatches()
+
+	pos := *origin
+	if match, ok := ta.Search.FindMatch(*origin, ta.Search.Backward); ok {
+		pos = match
+	}
+
+	ta.MoveCursor(pos.Row, 0, pos.ColumnOffset)
+	ta.RepositionView()
+}
+
+// ConfirmSearch moves the cursor to the match nearest to the position
+// the search started at and adds that position to the jump list.
+// Returns an error message if the query is not a valid regular expression
+func (editor *Editor) ConfirmSearch() message.StatusBarMsg {
+	ta := &editor.Textarea
+
+	if origin := editor.searchOrigin; origin != nil {
+		ta.MoveCursor(origin.Row, 0, origin.ColumnOffset)
+		editor.searchOrigin = nil
+	}
+
+	if err := ta.UpdateSearchMatches(); err != nil {
+		ta.RepositionView()
+		return editor.invalidSearchMsg()
+	}
+
+	editor.addJump()
+	editor.lastPattern = ta.Search.Pattern()
+
+	if match, ok := ta.Search.FindMatch(ta.AbsCursorPos(), ta.Search.Backward); ok {
+		ta.MoveCursor(match.Row, 0, match.ColumnOffset)
+	}
+
+	ta.RepositionView()
+	editor.isAtLineEnd = ta.IsAtLineEnd()
+	editor.saveCursorPos()
+
+	return message.StatusBarMsg{}
+}
+
+// invalidSearchMsg returns the error message for a search
+// query that is not a valid regular expression
+func (editor *Editor) invalidSearchMsg() message.StatusBarMsg {
+	return message.StatusBarMsg{
+		Content: fmt.Sprintf(message.StatusBar.InvalidPattern, editor.Textarea.Search.Query),
+		Type:    message.Error,
+	}
+}
+
+// LastPattern returns the pattern of the last search, `:s` or `:g`
+func (editor *Editor) LastPattern() string {
+	return editor.lastPattern
+}
+
+// SetLastPattern sets the pattern that is used by commands
+// with an empty pattern like `:s//x/`
+func (editor *Editor) SetLastPattern(pattern string) {
+	editor.lastPattern = pattern
+}
+
+// CancelSearch removes the search highlights and moves the cursor
+// back to where it was when the search has been started
 func (editor *Editor) CancelSearch() {
+	if origin := editor.searchOrigin; origin != nil {
+		editor.Textarea.MoveCursor(origin.Row, 0, origin.ColumnOffset)
+		editor.Textarea.RepositionView()
+		editor.searchOrigin = nil
+	}
+
 	editor.Textarea.ResetMultiSelection()
 }
 
@@ -0,0 +1,151 @@
+package editor
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/message"
+)
+
+func TestRegexSearch(t *testing.T) {
+	editor := createTestEditor(t, "TEST1\nTest2\nTest3\ntest4\ntes5t")
+	ta := &editor.Textarea
+
+	search := func(query string) {
+		editor.Update(SearchMsg{SearchTerm: query})
+		editor.Update(SearchConfirmedMsg{})
+	}
+
+	expectCursor := func(row, col int) {
+		t.Helper()
+		if pos := ta.AbsCursorPos(); pos.Row != row || pos.ColumnOffset != col {
+			t.Fatalf("expected cursor at (%d, %d), got (%d, %d)", row, col, pos.Row, pos.ColumnOffset)
+		}
+	}
+
+	// searches are literal by default
+	search(`t\d`)
+
+	if m := ta.Search.Matches; len(m) != 0 {
+		t.Fatalf("expected no literal match, got %v", m)
+	}
+
+	// `\v` searches for a regular expression
+	search(`\vt\d`)
+	expectCursor(0, 3)
+
+	if n := len(ta.Search.Matches); n != 4 {
+		t.Fatalf("expected 4 matches, got %d", n)
+	}
+
+	editor.MoveToMatch(false)
+	expectCursor(1, 3)
+	editor.MoveToMatch(true)
+	editor.MoveToMatch(true)
+	expectCursor(3, 3)
+
+	// `\C` respects the case, `\V` searches literally
+	search(`\C\VTe`)
+	expectCursor(1, 0)
+
+	if n := len(ta.Search.Matches); n != 2 {
+		t.Fatalf("expected 2 matches, got %d", n)
+	}
+
+	// matches may span lines
+	search(`\C\v2\ntest`)
+
+	if m := ta.Search.Matches; len(m) != 0 {
+		t.Fatalf("expected no match, got %v", m)
+	}
+
+	search(`\v3\ntest`)
+
+	if m := ta.Search.Matches; len(m) != 1 || m[0].End.Row != 3 || m[0].End.ColumnOffset != 4 {
+		t.Fatalf("expected a match ending in the next line, got %v", m)
+	}
+
+	// moving to the next match goes backward after `?`
+	ta.MoveCursor(2, 0, 0)
+	ta.Search.Backward = true
+	search(`es`)
+	expectCursor(1, 1)
+	editor.MoveToMatch(false)
+	expectCursor(0, 1)
+	editor.MoveToMatch(true)
+	expectCursor(1, 1)
+}
+
+func TestIncSearch(t *testing.T) {
+	editor := createTestEditor(t, "TEST1\nTest2\nTest3\ntest4\ntes5t")
+	ta := &editor.Textarea
+
+	expectCursor := func(row, col int) {
+		t.Helper()
+		if pos := ta.AbsCursorPos(); pos.Row != row || pos.ColumnOffset != col {
+			t.Fatalf("expected cursor at (%d, %d), got (%d, %d)", row, col, pos.Row, pos.ColumnOffset)
+		}
+	}
+
+	ta.MoveCursor(1, 0, 2)
+	editor.StartSearch(false)
+
+	// the cursor follows the query while typing
+	editor.Update(SearchMsg{SearchTerm: "t"})
+	expectCursor(1, 3)
+	editor.Update(SearchMsg{SearchTerm: "te"})
+	expectCursor(2, 0)
+	editor.Update(SearchMsg{SearchTerm: "tex"})
+	expectCursor(1, 2)
+
+	editor.CancelSearch()
+	expectCursor(1, 2)
+
+	if ta.Search.Query != "" {
+		t.Fatalf("expected search to be cancelled")
+	}
+
+	editor.StartSearch(true)
+	editor.Update(SearchMsg{SearchTerm: "te"})
+	expectCursor(1, 0)
+	editor.ConfirmSearch()
+	expectCursor(1, 0)
+
+	// the position the search started at is added to the jump list
+	if jumps := editor.Jumps.All(); len(jumps) != 1 || jumps[0].Pos.ColumnOffset != 2 {
+		t.Fatalf("expected search start in jump list, got %v", jumps)
+	}
+}
+
+func TestInvalidSearch(t *testing.T) {
+	editor := createTestEditor(t, "a(b\nc")
+	ta := &editor.Textarea
+
+	ta.MoveCursor(1, 0, 0)
+	editor.StartSearch(false)
+	editor.Update(SearchMsg{SearchTerm: `\va(`})
+
+	msg := editor.ConfirmSearch()
+	if msg.Type != message.Error || msg.Content == "" {
+		t.Fatalf("expected an error for the invalid pattern, got %+v", msg)
+	}
+
+	if pos := ta.AbsCursorPos(); pos.Row != 1 || pos.ColumnOffset != 0 {
+		t.Errorf("expected the cursor to stay at (1, 0), got (%d, %d)", pos.Row, pos.ColumnOffset)
+	}
+
+	if msg := editor.MoveToMatch(false); msg.Type != message.Error {
+		t.Errorf("expected moving to the next match to fail, got %+v", msg)
+	}
+
+	// without `\v` the pattern is searched literally
+	editor.StartSearch(false)
+	editor.Update(SearchMsg{SearchTerm: "a("})
+
+	if msg := editor.ConfirmSearch(); msg.Content != "" {
+		t.Fatalf("expected the literal search to succeed, got %+v", msg)
+	}
+
+	if pos := ta.AbsCursorPos(); pos.Row != 0 || pos.ColumnOffset != 0 {
+		t.Errorf("expected the cursor at (0, 0), got (%d, %d)", pos.Row, pos.ColumnOffset)
+	}
+}
@@ -0,0 +1,40 @@
+package editor
+
+import (
+	"bellbird-notes/app/config"
+)
+
+// Setting returns the value of a setting for the current buffer
+func (editor *Editor) Setting(s config.Setting) string {
+	value, _ := editor.value(s.Section, s.Option)
+	return value.Value
+}
+
+// SetLocalSetting sets the value of a buffer-local setting
+// for the current buffer only
+func (editor *Editor) SetLocalSetting(s config.Setting, value string) {
+	buf := editor.CurrentBuffer
+
+	if buf.Settings == nil {
+		buf.Settings = map[config.Option]string{}
+	}
+
+	buf.Settings[s.Option] = value
+}
+
+// HasLocalSetting returns whether the current buffer has
+// its own value of the setting
+func (editor *Editor) HasLocalSetting(s config.Setting) bool {
+	_, ok := editor.CurrentBuffer.Settings[s.Option]
+	return ok
+}
+
+// value returns the value of a config option. A buffer-local value
+// of the current buffer takes precedence over the config
+func (editor *Editor) value(section config.Section, option config.Option) (config.Value, error) {
+	if value, ok := editor.CurrentBuffer.Settings[option]; ok {
+		return config.Value{Value: value}, nil
+	}
+
+	return editor.conf.Value(section, option)
+}
@@ -0,0 +1,294 @@
+package editor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"bellbird-notes/app"
+	"bellbird-notes/app/config"
+	"bellbird-notes/app/debug"
+	"bellbird-notes/app/spell"
+	"bellbird-notes/tui/message"
+)
+
+// maxSuggestions is the number of suggestions `z=` shows
+const maxSuggestions = 20
+
+// spellTarget is the word suggestions have been made for
+type spellTarget struct {
+	row, start, end int
+	word            string
+}
+
+// SpellDir returns the directory dictionaries are loaded from
+func SpellDir() (string, error) {
+	dir, err := app.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "spell"), nil
+}
+
+// Spell returns whether misspelled words are underlined
+func (editor *Editor) Spell() bool {
+	spell, err := editor.value(config.Editor, config.Spell)
+	return err == nil && spell.GetBool()
+}
+
+// SpellLang returns the languages whose dictionaries are loaded
+func (editor *Editor) SpellLang() []string {
+	langs := []string{}
+
+	if value, err := editor.value(config.Editor, config.SpellLang); err == nil {
+		for lang := range strings.SplitSeq(value.Value, ",") {
+			if lang = strings.TrimSpace(lang); lang != "" {
+				langs = append(langs, lang)
+			}
+		}
+	}
+
+	if len(langs) == 0 {
+		langs = append(langs, "en")
+	}
+
+	return langs
+}
+
+// dictionary returns the dictionary of the spell languages.
+// It's loaded again if the languages have changed
+func (editor *Editor) dictionary() (*spell.Dictionary, error) {
+	langs := editor.SpellLang()
+	key := strings.Join(langs, ",")
+
+	if editor.spell != nil && editor.spellLang == key {
+		return editor.spell, nil
+	}
+
+	dir, err := SpellDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dict, err := spell.Load(dir, langs)
+	if err != nil {
+		return nil, err
+	}
+
+	editor.spell = dict
+	editor.spellLang = key
+
+	return dict, nil
+}
+
+// RefreshSpell underlines the misspelled words of the current buffer
+// if spell checking is enabled for it
+func (editor *Editor) RefreshSpell() error {
+	editor.Textarea.SpellCheck = nil
+
+	if !editor.Spell() {
+		return nil
+	}
+
+	dict, err := editor.dictionary()
+	if err != nil {
+		return err
+	}
+
+	editor.Textarea.SpellCheck = dict.Misspelled
+
+	return nil
+}
+
+// spellError returns the status message of an error
+// that occurred while loading the dictionary
+func spellError(err error) message.StatusBarMsg {
+	debug.LogErr(err)
+	return message.StatusBarMsg{Content: err.Error(), Type: message.Error}
+}
+
+// NextMisspelled moves the cursor to the next misspelled word or,
+// if prev is true, to the previous one. The search wraps around
+// at the end and the beginning of the buffer
+func (editor *Editor) NextMisspelled(prev bool, count int) message.StatusBarMsg {
+	if !editor.Spell() {
+		return message.StatusBarMsg{
+			Content: message.StatusBar.SpellOff,
+			Type:    message.Error,
+		}
+	}
+
+	dict, err := editor.dictionary()
+	if err != nil {
+		return spellError(err)
+	}
+
+	ta := &editor.Textarea
+	lines := ta.Val()
+	row, col := ta.Line(), ta.AbsCursorPos().ColumnOffset
+
+	for range max(count, 1) {
+		var ok bool
+		row, col, ok = nextMisspelled(dict, lines, row, col, prev)
+
+		if !ok {
+			return message.StatusBarMsg{
+				Content: message.StatusBar.NoMisspelled,
+				Type:    message.Error,
+			}
+		}
+	}
+
+	ta.MoveCursor(row, 0, col)
+	ta.RepositionView()
+	editor.saveCursorPos()
+
+	return message.StatusBarMsg{}
+}
+
+// nextMisspelled returns the position of the misspelled word
+// after or, if prev is true, before the given position
+func nextMisspelled(
+	dict *spell.Dictionary,
+	lines [][]rune,
+	row, col int,
+	prev bool,
+) (int, int, bool) {
+	n := len(lines)
+
+	// the current line is checked twice, first the words after the
+	// cursor and after wrapping around the words before the cursor
+	for i := 0; i <= n; i++ {
+		r := (row + i) % n
+		if prev {
+			r = (row - i%n + n) % n
+		}
+
+		ranges := dict.Misspelled(lines[r])
+
+		if prev {
+			for j := len(ranges) - 1; j >= 0; j-- {
+				if i > 0 || ranges[j][0] < col {
+					return r, ranges[j][0], true
+				}
+			}
+			continue
+		}
+
+		for _, word := range ranges {
+			if i > 0 || word[0] > col {
+				return r, word[0], true
+			}
+		}
+	}
+
+	return row, col, false
+}
+
+// wordAtCursor returns the range of the word at the cursor
+func (editor *Editor) wordAtCursor() (spellTarget, bool) {
+	ta := &editor.Textarea
+	row := ta.Line()
+	line := ta.Val()[row]
+
+	r, ok := spell.WordAt(line, ta.AbsCursorPos().ColumnOffset)
+	if !ok {
+		return spellTarget{}, false
+	}
+
+	return spellTarget{
+		row:   row,
+		start: r[0],
+		end:   r[1],
+		word:  string(line[r[0]:r[1]]),
+	}, true
+}
+
+// SpellSuggestions returns the word at the cursor and its suggestions.
+// The word is replaced with ReplaceMisspelled
+func (editor *Editor) SpellSuggestions() (string, []string, message.StatusBarMsg) {
+	dict, err := editor.dictionary()
+	if err != nil {
+		return "", nil, spellError(err)
+	}
+
+	target, ok := editor.wordAtCursor()
+	if !ok {
+		return "", nil, message.StatusBarMsg{
+			Content: message.StatusBar.NoWordUnderCursor,
+			Type:    message.Error,
+		}
+	}
+
+	suggestions := dict.Suggest(target.word, maxSuggestions)
+	if len(suggestions) == 0 {
+		return target.word, nil, message.StatusBarMsg{
+			Content: message.StatusBar.NoSuggestions,
+			Type:    message.Error,
+		}
+	}
+
+	editor.spellTarget = target
+
+	return target.word, suggestions, message.StatusBarMsg{}
+}
+
+// ReplaceMisspelled replaces the word the suggestions have been made
+// for with the given suggestion. Nothing is replaced if the word has
+// been changed in the meantime
+func (editor *Editor) ReplaceMisspelled(suggestion string) message.StatusBarMsg {
+	t := editor.spellTarget
+	ta := &editor.Textarea
+	lines := ta.Val()
+
+	if !editor.CurrentBuffer.Writeable ||
+		t.row >= len(lines) ||
+		t.end > len(lines[t.row]) ||
+		string(lines[t.row][t.start:t.end]) != t.word {
+
+		return message.StatusBarMsg{}
+	}
+
+	editor.newHistoryEntry()
+	ta.ReplacePrefix(t.row, t.end, string(lines[t.row][:t.start])+suggestion)
+	ta.MoveCursor(t.row, 0, t.start)
+	editor.EnterNormalMode(true)
+
+	return message.StatusBarMsg{}
+}
+
+// AddSpellWord adds the word at the cursor to the good words file.
+// If bad is true, the word is removed from the file or marked as
+// wrong if it's part of the dictionary
+func (editor *Editor) AddSpellWord(bad bool) message.StatusBarMsg {
+	dict, err := editor.dictionary()
+	if err != nil {
+		return spellError(err)
+	}
+
+	target, ok := editor.wordAtCursor()
+	if !ok {
+		return message.StatusBarMsg{
+			Content: message.StatusBar.NoWordUnderCursor,
+			Type:    message.Error,
+		}
+	}
+
+	msg := message.StatusBar.WordAdded
+
+	if bad {
+		err = dict.AddBad(target.word)
+		msg = message.StatusBar.WordMarkedWrong
+	} else {
+		err = dict.AddGood(target.word)
+	}
+
+	if err != nil {
+		return spellError(err)
+	}
+
+	return message.StatusBarMsg{
+		Content: fmt.Sprintf(msg, target.word, dict.GoodWordsPath()),
+	}
+}
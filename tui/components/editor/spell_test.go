@@ -0,0 +1,87 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bellbird-notes/tui/message"
+)
+
+// writeDictionary writes the English dictionary with the given words
+func writeDictionary(t *testing.T, words string) string {
+	t.Helper()
+
+	dir, err := SpellDir()
+	if err != nil {
+		t.Fatalf("SpellDir failed: %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Mkdir failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "en.txt"), []byte(words), 0644); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	return dir
+}
+
+func TestSpell(t *testing.T) {
+	editor := createTestEditor(t, "hello wrld\nnote helo")
+	dir := writeDictionary(t, "hello\nworld\nnote\n")
+	ta := &editor.Textarea
+
+	if msg := editor.NextMisspelled(false, 1); msg.Type != message.Error {
+		t.Error("expected an error while spell checking is disabled")
+	}
+
+	setLocal(t, editor, "spell", "true")
+	if err := editor.RefreshSpell(); err != nil || ta.SpellCheck == nil {
+		t.Fatalf("expected spell checking to be enabled, got %v", err)
+	}
+
+	positions := []struct {
+		prev     bool
+		count    int
+		row, col int
+	}{
+		{false, 1, 0, 6},
+		{false, 1, 1, 5},
+		{false, 1, 0, 6},
+		{true, 1, 1, 5},
+		{true, 2, 1, 5},
+	}
+
+	for _, p := range positions {
+		editor.NextMisspelled(p.prev, p.count)
+
+		if row, col := ta.Line(), ta.AbsCursorPos().ColumnOffset; row != p.row || col != p.col {
+			t.Errorf("%+v: expected %d:%d, got %d:%d", p, p.row, p.col, row, col)
+		}
+	}
+
+	word, suggestions, _ := editor.SpellSuggestions()
+	if word != "helo" || len(suggestions) == 0 || suggestions[0] != "hello" {
+		t.Fatalf("expected hello to be suggested for %q, got %v", word, suggestions)
+	}
+
+	editor.ReplaceMisspelled(suggestions[0])
+
+	if got := string(ta.Val()[1]); got != "note hello" {
+		t.Errorf("expected the word to be replaced, got %q", got)
+	}
+
+	ta.MoveCursor(0, 0, 6)
+	editor.AddSpellWord(false)
+
+	if ranges := ta.SpellCheck(ta.Val()[0]); len(ranges) != 0 {
+		t.Errorf("expected wrld to be a good word, got %v", ranges)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "good.words"))
+	if err != nil || string(content) != "wrld\n" {
+		t.Errorf("expected wrld in the good words file, got %q", content)
+	}
+}
@@ -0,0 +1,273 @@
+package editor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+)
+
+// Substitution replaces the matches of a pattern within a range of lines
+type Substitution struct {
+	Pattern *regexp.Regexp
+
+	// Replacement is a template as used by regexp.Expand,
+	// e.g. `${1}` is replaced with the first capture group
+	Replacement string
+
+	// StartRow and EndRow are the first and the last line
+	StartRow, EndRow int
+
+	// Global replaces every match of a line instead of only the first one
+	Global bool
+
+	// Confirm asks for confirmation before every replacement
+	Confirm bool
+}
+
+// substitution holds the state of a running substitution
+type substitution struct {
+	Substitution
+
+	row int
+
+	// col is the byte offset within the line after which
+	// the next match is searched
+	col int
+
+	// match holds the submatch indices of the current match
+	match []int
+
+	// count is the number of replacements, lines the number
+	// of lines with at least one replacement
+	count, lines int
+	lastRow      int
+}
+
+// Substitute starts replacing the matches of the given substitution
+// as a single undo step.
+// If the substitution needs confirmation, the status bar asks for it and
+// pending is true until ConfirmSubstitution has been called for every match
+func (editor *Editor) Substitute(sub Substitution) (message.StatusBarMsg, bool) {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}, false
+	}
+
+	editor.newHistoryEntry()
+	editor.substitution = &substitution{
+		Substitution: sub,
+		row:          sub.StartRow,
+		lastRow:      -1,
+	}
+
+	// a batch can't wait for confirmations
+	if !sub.Confirm || editor.batch {
+		return editor.substituteAll(), false
+	}
+
+	return editor.nextSubstitution()
+}
+
+// ConfirmSubstitution answers the confirmation prompt of the current match:
+// `y` replaces it, `n` skips it, `a` replaces it and all remaining matches,
+// `l` replaces it and stops, `q` and `esc` stop.
+// Other keys are ignored
+func (editor *Editor) ConfirmSubstitution(answer string) (message.StatusBarMsg, bool) {
+	sub := editor.substitution
+	if sub == nil {
+		return message.StatusBarMsg{}, false
+	}
+
+	switch answer {
+	case "y":
+		editor.replaceMatch()
+	case "n":
+		editor.skipMatch()
+	case "a":
+		editor.replaceMatch()
+		return editor.substituteAll(), false
+	case "l":
+		editor.replaceMatch()
+		return editor.finishSubstitution(), false
+	case "q", "esc":
+		return editor.finishSubstitution(), false
+	default:
+		return editor.substitutionPrompt(), true
+	}
+
+	return editor.nextSubstitution()
+}
+
+// substituteAll replaces all remaining matches without confirmation
+func (editor *Editor) substituteAll() message.StatusBarMsg {
+	for editor.findMatch() {
+		editor.replaceMatch()
+	}
+
+	return editor.finishSubstitution()
+}
+
+// nextSubstitution moves to the next match and asks for confirmation
+func (editor *Editor) nextSubstitution() (message.StatusBarMsg, bool) {
+	if !editor.findMatch() {
+		return editor.finishSubstitution(), false
+	}
+
+	sub := editor.substitution
+	line := string(editor.Textarea.Val()[sub.row])
+	start := utf8.RuneCountInString(line[:sub.match[0]])
+	end := utf8.RuneCountInString(line[:sub.match[1]])
+
+	ta := &editor.Textarea
+	ta.ResetSelection()
+
+	if end > start {
+		ta.SelectRange(
+			textarea.SelectVisual,
+			textarea.CursorPos{Row: sub.row, ColumnOffset: start},
+			textarea.CursorPos{Row: sub.row, ColumnOffset: end - 1},
+		)
+	}
+
+	ta.MoveCursor(sub.row, 0, start)
+	ta.RepositionView()
+
+	return editor.substitutionPrompt(), true
+}
+
+// substitutionPrompt returns the confirmation prompt of the current match
+func (editor *Editor) substitutionPrompt() message.StatusBarMsg {
+	sub := editor.substitution
+	line := string(editor.Textarea.Val()[sub.row])
+	replacement := sub.Pattern.ExpandString(nil, sub.Replacement, line, sub.match)
+
+	return message.StatusBarMsg{
+		Content: fmt.Sprintf(message.StatusBar.SubstituteConfirm, replacement),
+		Type:    message.Prompt,
+	}
+}
+
+// findMatch finds the next match starting at the current position
+// of the substitution. Returns false if there are no more matches
+func (editor *Editor) findMatch() bool {
+	sub := editor.substitution
+	lines := editor.Textarea.Val()
+
+	for ; sub.row <= sub.EndRow && sub.row < len(lines); sub.row++ {
+		line := string(lines[sub.row])
+
+		if sub.col <= len(line) {
+			for _, match := range sub.Pattern.FindAllStringSubmatchIndex(line, -1) {
+				if match[0] >= sub.col {
+					sub.match = match
+					return true
+				}
+			}
+		}
+
+		sub.col = 0
+	}
+
+	return false
+}
+
+// skipMatch continues the search after the current match
+func (editor *Editor) skipMatch() {
+	sub := editor.substitution
+	line := string(editor.Textarea.Val()[sub.row])
+
+	sub.col = sub.match[1]
+	if sub.match[0] == sub.match[1] {
+		sub.col += runeLenAt(line, sub.col)
+	}
+
+	if !sub.Global {
+		sub.nextRow()
+	}
+}
+
+// replaceMatch replaces the current match and continues the search
+// after the inserted text.
+// Replacements containing line breaks split the line
+func (editor *Editor) replaceMatch() {
+	sub := editor.substitution
+	line := string(editor.Textarea.Val()[sub.row])
+	replacement := sub.Pattern.ExpandString(nil, sub.Replacement, line, sub.match)
+
+	before := line[:sub.match[0]] + string(replacement)
+	after := line[sub.match[1]:]
+	newLines := strings.Split(before+after, "\n")
+
+	editor.Textarea.ReplaceLines(sub.row, sub.row, newLines)
+
+	if sub.lastRow != sub.row {
+		sub.lines++
+	}
+
+	sub.count++
+	sub.EndRow += len(newLines) - 1
+	sub.row += strings.Count(before, "\n")
+	sub.lastRow = sub.row
+
+	// continue after the replacement in the last line of it
+	sub.col = len(before) - strings.LastIndex(before, "\n") - 1
+	if sub.match[0] == sub.match[1] {
+		sub.col += runeLenAt(after, 0)
+	}
+
+	if !sub.Global {
+		sub.nextRow()
+	}
+}
+
+// nextRow continues the search at the beginning of the next line
+func (sub *substitution) nextRow() {
+	sub.row++
+	sub.col = 0
+}
+
+// finishSubstitution adds the replacements to the history, moves the
+// cursor to the last changed line and reports the number of replacements
+func (editor *Editor) finishSubstitution() message.StatusBarMsg {
+	sub := editor.substitution
+	editor.substitution = nil
+
+	ta := &editor.Textarea
+	ta.ResetSelection()
+
+	if sub.count == 0 {
+		return message.StatusBarMsg{
+			Content: fmt.Sprintf(message.StatusBar.PatternNotFound, sub.Pattern),
+			Type:    message.Error,
+		}
+	}
+
+	ta.MoveCursor(sub.lastRow, 0, 0)
+	ta.CursorInputStart()
+	ta.RepositionView()
+	editor.isAtLineEnd = ta.IsAtLineEnd()
+
+	editor.updateBufferContent(true)
+
+	return message.StatusBarMsg{
+		Content: fmt.Sprintf(
+			message.StatusBar.Substitutions,
+			sub.count, message.PluralSuffix(sub.count),
+			sub.lines, message.PluralSuffix(sub.lines),
+		),
+	}
+}
+
+// runeLenAt returns the number of bytes of the rune starting at byte
+// offset `i` of the string. Offsets out of range count as one byte
+func runeLenAt(str string, i int) int {
+	if i < 0 || i >= len(str) {
+		return 1
+	}
+
+	_, size := utf8.DecodeRuneInString(str[i:])
+	return size
+}
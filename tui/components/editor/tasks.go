@@ -0,0 +1,60 @@
+package editor
+
+import (
+	"regexp"
+	"time"
+
+	"bellbird-notes/app/config"
+	"bellbird-notes/tui/message"
+)
+
+// taskDateRegex matches the date that is appended to done tasks
+var taskDateRegex = regexp.MustCompile(`\s*@done\(\d{4}-\d{2}-\d{2}\)\s*$`)
+
+// TaskDate returns whether checking a task appends the current date
+func (editor *Editor) TaskDate() bool {
+	taskDate, err := editor.value(config.Editor, config.TaskDate)
+	return err == nil && taskDate.GetBool()
+}
+
+// ToggleTask checks or unchecks the checkbox of the list item on the
+// current line. List items without a checkbox get one, other lines are
+// turned into a task. With taskdate the current date is appended to
+// checked tasks and removed again when they're unchecked
+func (editor *Editor) ToggleTask() message.StatusBarMsg {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	ta := &editor.Textarea
+	row := ta.Line()
+	line := ta.Val()[row]
+
+	item, ok := parseListItem(line)
+	if !ok {
+		item = listItem{
+			indent: ta.Indentation(row),
+			bullet: "-",
+			len:    len([]rune(ta.Indentation(row))),
+		}
+	}
+
+	text := string(line[item.len:])
+	checkbox := "[ ]"
+
+	if item.checkbox && !item.checked {
+		checkbox = "[x]"
+
+		if editor.TaskDate() {
+			text += " @done(" + time.Now().Format(time.DateOnly) + ")"
+		}
+	} else {
+		text = taskDateRegex.ReplaceAllString(text, "")
+	}
+
+	editor.newHistoryEntry()
+	ta.ReplacePrefix(row, len(line), item.indent+item.marker()+" "+checkbox+" "+text)
+	editor.EnterNormalMode(true)
+
+	return message.StatusBarMsg{}
+}
@@ -0,0 +1,43 @@
+package editor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToggleTask(t *testing.T) {
+	editor := createTestEditor(t, "TEST1\n  * item\n1. [x] done @done(2024-01-02)")
+	ta := &editor.Textarea
+
+	for _, want := range []string{"- [ ] TEST1", "- [x] TEST1", "- [ ] TEST1"} {
+		editor.ToggleTask()
+
+		if got := string(ta.Val()[0]); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+
+	// list items keep their marker and indentation
+	editor.LineDown(false)
+	editor.ToggleTask()
+
+	if got := string(ta.Val()[1]); got != "  * [ ] item" {
+		t.Errorf("expected the list item to become a task, got %q", got)
+	}
+
+	// unchecking a task removes its date
+	editor.LineDown(false)
+	editor.ToggleTask()
+
+	if got := string(ta.Val()[2]); got != "1. [ ] done" {
+		t.Errorf("expected the date to be removed, got %q", got)
+	}
+
+	setLocal(t, editor, "taskdate", "true")
+	editor.ToggleTask()
+
+	date := time.Now().Format(time.DateOnly)
+	if got := string(ta.Val()[2]); got != "1. [x] done @done("+date+")" {
+		t.Errorf("expected the task to be dated, got %q", got)
+	}
+}
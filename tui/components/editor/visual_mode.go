@@ -1,15 +1,218 @@
 package editor
 
 import (
+	"slices"
+	"strings"
+
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+	"bellbird-notes/tui/mode"
+
 	tea "github.com/charmbracelet/bubbletea/v2"
+	rw "github.com/mattn/go-runewidth"
 )
 
 func (editor *Editor) handleVisualMode(msg tea.KeyMsg) tea.Cmd {
 	if msg.String() == "esc" {
-		editor.Textarea.ResetSelection()
 		editor.EnterNormalMode(true)
 		return nil
 	}
 
 	return nil
 }
+
+// blockInsert holds the block we're inserting text into with
+// `I`, `A` or `c` in visual block mode so that the text typed in the
+// first line can be repeated in the other lines when leaving insert mode
+type blockInsert struct {
+	block textarea.Block
+
+	// col is the display column the text is inserted at
+	col int
+
+	// pad indicates whether lines that are too short are padded
+	// with spaces, otherwise nothing is inserted into them
+	pad bool
+
+	// line is the content of the first line before inserting
+	line []rune
+
+	lineCount int
+}
+
+// InsertBlock enters insert mode in the first line of the block selection.
+// If after is false the text is inserted before the block, otherwise
+// it's appended to it. The typed text is inserted into every line of the
+// block when leaving insert mode
+func (editor *Editor) InsertBlock(after bool) message.StatusBarMsg {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	ta := &editor.Textarea
+	block := ta.BlockSelection()
+
+	editor.newHistoryEntry()
+
+	insert := &blockInsert{block: block, col: block.StartCol}
+
+	if after {
+		insert.col = block.EndCol
+		insert.pad = true
+
+		// pad the first line so that we can start typing at the block's end
+		if !block.ToLineEnd {
+			ta.InsertAtDisplayCol(block.StartRow, insert.col, "", true)
+		}
+	}
+
+	ta.MoveCursor(block.StartRow, 0, 0)
+
+	if after && block.ToLineEnd {
+		ta.CursorEnd()
+	} else {
+		ta.SetCursorColumn(ta.ColumnAt(block.StartRow, insert.col))
+	}
+
+	editor.startBlockInsert(insert)
+	return editor.ResetSelectedRowsCount()
+}
+
+// ChangeBlock deletes the block selection and enters insert mode.
+// The typed text is inserted into every line of the block
+// when leaving insert mode
+func (editor *Editor) ChangeBlock() message.StatusBarMsg {
+	if !editor.CurrentBuffer.Writeable {
+		return message.StatusBarMsg{}
+	}
+
+	ta := &editor.Textarea
+	block := ta.BlockSelection()
+
+	editor.newHistoryEntry()
+	editor.CutBlock(ta.DeleteBlock(block))
+
+	editor.startBlockInsert(&blockInsert{block: block, col: block.StartCol})
+	return editor.ResetSelectedRowsCount()
+}
+
+// startBlockInsert remembers the first line of the block
+// and enters insert mode
+func (editor *Editor) startBlockInsert(insert *blockInsert) {
+	ta := &editor.Textarea
+
+	insert.line = slices.Clone(ta.Val()[insert.block.StartRow])
+	insert.lineCount = ta.LineCount()
+
+	// the history entry is updated when leaving insert mode so that
+	// the text inserted into all lines is undone at once
+	editor.EnterInsertMode(false)
+	editor.blockInsert = insert
+}
+
+// finishBlockInsert inserts the text that has been typed into the
+// first line of the block into the other lines of the block.
+// Nothing is repeated if a line break has been typed
+func (editor *Editor) finishBlockInsert() {
+	insert := editor.blockInsert
+	editor.blockInsert = nil
+
+	ta := &editor.Textarea
+
+	if insert == nil || ta.LineCount() != insert.lineCount {
+		return
+	}
+
+	block := insert.block
+
+	text, ok := insertedText(insert.line, ta.Val()[block.StartRow])
+	if !ok || text == "" {
+		return
+	}
+
+	cursor := ta.AbsCursorPos()
+
+	for row := block.StartRow + 1; row <= block.EndRow; row++ {
+		if block.ToLineEnd && insert.pad {
+			ta.MoveCursor(row, 0, 0)
+			ta.CursorEnd()
+			ta.InsertString(text)
+			continue
+		}
+
+		ta.InsertAtDisplayCol(row, insert.col, text, insert.pad)
+	}
+
+	ta.MoveCursor(cursor.Row, 0, cursor.ColumnOffset)
+}
+
+// insertedText returns the text that has been inserted into `before`
+// resulting in `after`.
+// Returns false if anything else has been changed
+func insertedText(before []rune, after []rune) (string, bool) {
+	if len(after) < len(before) {
+		return "", false
+	}
+
+	prefix := 0
+	for prefix < len(before) && before[prefix] == after[prefix] {
+		prefix++
+	}
+
+	inserted := len(after) - len(before)
+
+	if !slices.Equal(before[prefix:], after[prefix+inserted:]) {
+		return "", false
+	}
+
+	return string(after[prefix : prefix+inserted]), true
+}
+
+// CutBlock stores the lines of a deleted block in the selected register
+func (editor *Editor) CutBlock(lines []string) message.StatusBarMsg {
+	editor.Registers.DeleteBlock(strings.Join(lines, "\n"))
+	return message.StatusBarMsg{}
+}
+
+// YankBlock stores the lines of a block in the selected register
+func (editor *Editor) YankBlock(lines []string) message.StatusBarMsg {
+	editor.Registers.YankBlock(strings.Join(lines, "\n"))
+	return message.StatusBarMsg{}
+}
+
+// pasteBlock pastes the lines of a block register `count` times
+// next to each other after the cursor
+func (editor *Editor) pasteBlock(content string, count int) {
+	ta := &editor.Textarea
+	lines := strings.Split(content, "\n")
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, rw.StringWidth(line))
+	}
+
+	for i, line := range lines {
+		padding := strings.Repeat(" ", width-rw.StringWidth(line))
+		lines[i] = strings.Repeat(line+padding, max(count, 1)-1) + line
+	}
+
+	cursor := ta.AbsCursorPos()
+	line := ta.Val()[cursor.Row]
+	col := textarea.DisplayCol(line, min(cursor.ColumnOffset+1, len(line)))
+
+	ta.PasteBlock(lines, col)
+	ta.MoveCursor(cursor.Row, 0, ta.ColumnAt(cursor.Row, col))
+}
+
+// selectedRows returns the first and the last row of the selection
+func (editor *Editor) selectedRows() (int, int) {
+	ta := &editor.Textarea
+
+	if editor.Mode.Current == mode.VisualBlock {
+		block := ta.BlockSelection()
+		return block.StartRow, block.EndRow
+	}
+
+	start, end := ta.Selection.Range(ta.CursorPos())
+	return start.Row, end.Row
+}
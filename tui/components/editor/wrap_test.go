@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"bellbird-notes/app/config"
+	"bellbird-notes/tui/components/textarea"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSoftWrap(t *testing.T) {
+	editor := createTestEditor(t, "- a list item with quite a few words that wrap around\nshort")
+	editor.Size.Width = 30
+	editor.RefreshSize()
+
+	ta := &editor.Textarea
+
+	setLocal(t, editor, "breakindent", "true")
+	editor.conf.Override(config.Editor, config.ShowBreak, ">>")
+	editor.RefreshTextAreaStyles()
+
+	// the textarea is 26 columns wide, continuation rows are indented by
+	// the list marker and the showbreak marker: `- a list item with quite a `
+	// continues with `  >>few words that wrap `
+
+	// moving by screen lines keeps the column on the screen
+	ta.MoveCursor(0, 0, 5)
+	editor.LineDown(true)
+	if pos := ta.CursorPos(); pos.Row != 0 || pos.RowOffset != 1 || pos.ColumnOffset != 28 {
+		t.Errorf("expected the cursor on the `e` of `few`, got %+v", pos)
+	}
+
+	editor.LineDown(true)
+	if pos := ta.CursorPos(); pos.RowOffset != 2 || pos.ColumnOffset != 48 {
+		t.Errorf("expected the cursor on the `r` of `around`, got %+v", pos)
+	}
+
+	// the selection covers the wrapped rows in between
+	editor.EnterVisualMode(textarea.SelectVisual)
+	editor.LineUp(true)
+	if got := ta.SelectionStr(); got != "ew words that wrap ar" {
+		t.Errorf("expected the selection %q, got %q", "ew words that wrap ar", got)
+	}
+	editor.EnterNormalMode(true)
+
+	// lines are scrolled horizontally without wrapping
+	setLocal(t, editor, "wrap", "false")
+	editor.RefreshTextAreaStyles()
+	ta.MoveCursor(0, 0, 0)
+
+	for range 10 {
+		editor.ScrollRight()
+	}
+	if col := ta.CursorPos().ColumnOffset; col != 10 {
+		t.Errorf("expected scrolling to move the cursor into view, got column %d", col)
+	}
+
+	view := ansi.Strip(ta.View())
+	if !strings.Contains(view, "tem with quite a few words") {
+		t.Errorf("expected the line to be scrolled by 10 columns, got\n%s", view)
+	}
+}
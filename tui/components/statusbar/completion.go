@@ -0,0 +1,161 @@
+package statusbar
+
+import (
+	"strings"
+
+	"bellbird-notes/tui/message"
+	"bellbird-notes/tui/mode"
+	"bellbird-notes/tui/theme"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Completer returns the completions of the word that ends at the end of
+// the given command line and the byte offset the word starts at
+type Completer func(cmdline string) (int, []string)
+
+// wildmenu holds the completions of the command prompt while cycling
+// through them
+type wildmenu struct {
+	// prefix is the part of the command line before the completed word
+	// and word the word as it has been typed
+	prefix, word string
+
+	candidates []string
+
+	// index is the selected candidate, -1 is the word as it's been typed
+	index int
+
+	// cmdline is the command line after the last completion. The menu
+	// is discarded once the command line has been changed otherwise
+	cmdline string
+}
+
+// CompleteNext completes the word before the cursor of the command
+// prompt with the next candidate. The first completion collects the
+// candidates, following ones cycle through them and back to the word
+// as it has been typed
+func (sb *StatusBar) CompleteNext() message.StatusBarMsg {
+	return sb.complete(1)
+}
+
+// CompletePrev is like CompleteNext but cycles backwards
+func (sb *StatusBar) CompletePrev() message.StatusBarMsg {
+	return sb.complete(-1)
+}
+
+func (sb *StatusBar) complete(step int) message.StatusBarMsg {
+	if sb.Mode != mode.Command || sb.Completer == nil {
+		return message.StatusBarMsg{}
+	}
+
+	menu := sb.activeWildmenu()
+
+	if menu == nil {
+		cmdline := string([]rune(sb.Prompt.Value())[:sb.Prompt.Position()])
+		start, candidates := sb.Completer(cmdline)
+
+		if len(candidates) == 0 {
+			return message.StatusBarMsg{}
+		}
+
+		menu = &wildmenu{
+			prefix:     cmdline[:start],
+			word:       cmdline[start:],
+			candidates: candidates,
+			index:      -1,
+		}
+		sb.wildmenu = menu
+	}
+
+	// cycle through the candidates and the typed word
+	n := len(menu.candidates) + 1
+	menu.index = (menu.index+1+step+n)%n - 1
+
+	word := menu.word
+	if menu.index >= 0 {
+		word = menu.candidates[menu.index]
+	}
+
+	menu.cmdline = menu.prefix + word
+	sb.Prompt.SetValue(menu.cmdline)
+	sb.Prompt.CursorEnd()
+
+	return message.StatusBarMsg{}
+}
+
+// activeWildmenu returns the wildmenu unless the command line has been
+// changed since the last completion
+func (sb *StatusBar) activeWildmenu() *wildmenu {
+	if sb.wildmenu == nil ||
+		!sb.Prompt.Focused() ||
+		sb.Prompt.Value() != sb.wildmenu.cmdline {
+
+		sb.wildmenu = nil
+	}
+
+	return sb.wildmenu
+}
+
+// WildmenuView renders the candidates of the current completion in
+// a single line with the selected candidate highlighted.
+// Returns an empty string if there's nothing to complete
+func (sb *StatusBar) WildmenuView() string {
+	menu := sb.activeWildmenu()
+	if menu == nil {
+		return ""
+	}
+
+	width, _ := theme.TerminalSize()
+
+	style := lipgloss.NewStyle().
+		Background(theme.ColourBgSelected).
+		Width(width)
+
+	selected := lipgloss.NewStyle().
+		Background(theme.ColourBorderFocused).
+		Foreground(theme.ColourSearchFg)
+
+	// scroll so that the selected candidate is visible
+	first := 0
+	visibleWidth := func(from, to int) int {
+		w := 0
+		for _, c := range menu.candidates[from : to+1] {
+			w += ansi.StringWidth(c) + 2
+		}
+		return w
+	}
+
+	for menu.index > first && visibleWidth(first, menu.index) > width-4 {
+		first++
+	}
+
+	var line strings.Builder
+
+	if first > 0 {
+		line.WriteString("< ")
+	}
+
+	used := line.Len()
+
+	for i := first; i < len(menu.candidates); i++ {
+		c := menu.candidates[i]
+
+		if used+ansi.StringWidth(c)+2 > width-2 {
+			line.WriteString(">")
+			break
+		}
+
+		if i == menu.index {
+			line.WriteString(selected.Render(c))
+		} else {
+			line.WriteString(c)
+		}
+
+		line.WriteString("  ")
+		used += ansi.StringWidth(c) + 2
+	}
+
+	return style.Render(line.String())
+}
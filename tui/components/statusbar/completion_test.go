@@ -0,0 +1,61 @@
+package statusbar
+
+import (
+	"strings"
+	"testing"
+
+	"bellbird-notes/tui/mode"
+)
+
+func TestWildmenu(t *testing.T) {
+	sb := New()
+	sb.Completer = func(cmdline string) (int, []string) {
+		start := strings.LastIndexByte(cmdline, ' ') + 1
+		return start, []string{"number", "nowrap"}
+	}
+	sb.Mode = mode.Command
+	sb.Prompt.SetValue("set n")
+	sb.Prompt.CursorEnd()
+	sb.Prompt.Focus()
+
+	for _, want := range []string{"set number", "set nowrap", "set n", "set number"} {
+		sb.CompleteNext()
+		if got := sb.Prompt.Value(); got != want {
+			t.Errorf("expected %q after tab, got %q", want, got)
+		}
+	}
+
+	if sb.WildmenuView() == "" {
+		t.Error("expected the wildmenu to be shown")
+	}
+
+	// cycles back to the typed word
+	sb.CompletePrev()
+	if got := sb.Prompt.Value(); got != "set n" {
+		t.Errorf("expected %q after shift+tab, got %q", "set n", got)
+	}
+
+	// changing the command line discards the candidates
+	sb.Prompt.SetValue("set no")
+	if sb.WildmenuView() != "" {
+		t.Error("expected the wildmenu to be closed after typing")
+	}
+
+	sb.CompleteNext()
+	sb.BlurPrompt(true)
+	if sb.WildmenuView() != "" {
+		t.Error("expected the wildmenu to be closed")
+	}
+}
+
+func TestWildmenuCommandModeOnly(t *testing.T) {
+	sb := New()
+	sb.Completer = func(string) (int, []string) { return 0, []string{"x"} }
+	sb.Prompt.SetValue("a")
+	sb.Prompt.Focus()
+
+	sb.CompleteNext()
+	if got := sb.Prompt.Value(); got != "a" {
+		t.Errorf("expected no completion outside of command mode, got %q", got)
+	}
+}
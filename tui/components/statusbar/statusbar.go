@@ -30,6 +30,13 @@ type Focusable = interfaces.Focusable
 
 type Commands map[string]func(opts ...string) message.StatusBarMsg
 
+// rangeCmd matches a command name preceded by an optional line range
+// like `%`, `5,10`, `.,+3` or `'a,'b`. The name is empty if there's
+// only a range
+var rangeCmd = regexp.MustCompile(
+	`^((?:[0-9.$%,;+\-]|'[a-zA-Z<>])*)\s*([a-zA-Z]*)(.*)$`,
+)
+
 // StatusBar represents the bottom bar UI component that displays messages,
 // input prompts, and application mode information.
 type StatusBar struct {
@@ -47,6 +54,9 @@ type StatusBar struct {
 	// The Current editing/view mode
 	Mode mode.Mode
 
+	// SearchBackward shows `?` instead of `/` in front of the search prompt
+	SearchBackward bool
+
 	//Sender message.Sender
 	//SenderMsg message.StatusBarMsg
 
@@ -61,6 +71,16 @@ type StatusBar struct {
 	// Registered prompt commands
 	Commands Commands
 
+	// Registered answers to a prompt, e.g. `y` to confirm a deletion.
+	// They take precedence over the commands while a prompt is shown
+	PromptCommands Commands
+
+	// Completer completes the words of the command prompt
+	Completer Completer
+
+	// wildmenu is set while cycling through completions
+	wildmenu *wildmenu
+
 	TeaCmd tea.Cmd
 }
 
@@ -192,6 +212,9 @@ func (sb *StatusBar) View() string {
 			sb.Prompt.Prompt = ":"
 		case mode.SearchPrompt, mode.Search:
 			sb.Prompt.Prompt = "/"
+			if sb.SearchBackward {
+				sb.Prompt.Prompt = "?"
+			}
 		}
 		sb.Prompt.Focus()
 		promptView := strings.TrimSpace(sb.Prompt.View())
@@ -265,6 +288,7 @@ func (sb *StatusBar) ConfirmAction(
 	)
 
 	statusMsg.Content = fnMsg.Content
+	statusMsg.Type = fnMsg.Type
 
 	return statusMsg
 }
@@ -285,7 +309,11 @@ func (sb *StatusBar) execPromptFn() message.StatusBarMsg {
 	promptCmd := sb.Prompt.Value()
 	args := ""
 
-	re := regexp.MustCompile(`^(open|set|reload)\s+(\S+)\s*(.*)`)
+	if fn, ok := sb.PromptCommands[promptCmd]; ok && sb.isPrompt() {
+		return fn(args)
+	}
+
+	re := regexp.MustCompile(`^(open|set|setl|setlocal|reload)\s+(.+?)\s*$`)
 	matches := re.FindStringSubmatch(promptCmd)
 
 	if len(matches) > 0 {
@@ -293,16 +321,38 @@ func (sb *StatusBar) execPromptFn() message.StatusBarMsg {
 		args = matches[2]
 	}
 
-	for cmd, fn := range sb.Commands {
-		if cmd == promptCmd {
-			fnMsg = fn(args)
-			break
+	if fn, ok := sb.Commands[promptCmd]; ok {
+		return fn(args)
+	}
+
+	// commands that take a line range, e.g. `%s/foo/bar/g`.
+	// The function gets the range and everything after the command name
+	if rangeSpec, name, rest, ok := ParseRangeCmd(promptCmd); ok {
+		// a range without a command moves the cursor, e.g. `:10`
+		if name == "" && rangeSpec != "" {
+			name = message.CmdPrompt.GoToLine
+		}
+
+		if fn, ok := sb.Commands[name]; ok {
+			fnMsg = fn(rangeSpec, rest)
 		}
 	}
 
 	return fnMsg
 }
 
+// ParseRangeCmd splits a command like `%s/foo/bar/g` into the line range,
+// the command name and everything after the name.
+// Returns false if the command neither has a name nor a range
+func ParseRangeCmd(cmd string) (string, string, string, bool) {
+	matches := rangeCmd.FindStringSubmatch(cmd)
+	if len(matches) == 0 || matches[1]+matches[2] == "" {
+		return "", "", "", false
+	}
+
+	return matches[1], matches[2], matches[3], true
+}
+
 // CancelAction cancels the current prompt input and resets the status bar state.
 func (sb *StatusBar) CancelAction(cb func()) message.StatusBarMsg {
 	sb.Type = message.Success
@@ -353,6 +403,7 @@ func (sb *StatusBar) BlurPrompt(resetValue bool) {
 		sb.Prompt.SetValue("")
 	}
 
+	sb.wildmenu = nil
 	sb.Prompt.Blur()
 	sb.Mode = mode.Normal
 }
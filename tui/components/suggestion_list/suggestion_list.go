@@ -0,0 +1,225 @@
+package suggestionlist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"bellbird-notes/app/config"
+	"bellbird-notes/tui/components/overlay"
+	"bellbird-notes/tui/message"
+	"bellbird-notes/tui/mode"
+	"bellbird-notes/tui/shared"
+	"bellbird-notes/tui/theme"
+)
+
+const (
+	// minWidth and maxHeight limit the size of the list
+	minWidth  = 30
+	maxHeight = 10
+)
+
+// SuggestionListItem is a suggested spelling of a misspelled word
+type SuggestionListItem struct {
+	shared.Item
+}
+
+// String is the string representation of a SuggestionListItem
+func (item SuggestionListItem) String() string {
+	style := lipgloss.NewStyle().
+		Foreground(lipgloss.NoColor{}).
+		Width(item.Width())
+
+	if item.IsSelected {
+		style = style.Background(theme.ColourBgSelected)
+	}
+
+	number := lipgloss.NewStyle().
+		Foreground(theme.ColourBorder).
+		Render(fmt.Sprintf(" %2d ", item.Index()+1))
+
+	name := ansi.Truncate(item.Name(), item.Width()-lipgloss.Width(number)-1, "…")
+
+	return style.Render(number + name)
+}
+
+// SuggestionList shows the suggestions for a misspelled word
+type SuggestionList struct {
+	shared.List[*SuggestionListItem]
+
+	width  int
+	height int
+
+	// word is the misspelled word
+	word string
+
+	Overlay *overlay.Overlay
+}
+
+func New(title string, conf *config.Config) *SuggestionList {
+	var list shared.List[*SuggestionListItem]
+	list.MakeEmpty()
+	list.Conf = conf
+
+	panel := &SuggestionList{
+		List:    list,
+		Overlay: &overlay.Overlay{},
+	}
+
+	panel.SetTitle(title)
+	panel.SetTheme(theme.New(conf))
+	panel.UpdateSize()
+	panel.Blur()
+	panel.Mode = mode.Normal
+
+	return panel
+}
+
+// UpdateSize fits the list to its suggestions
+func (list *SuggestionList) UpdateSize() {
+	w, h := theme.TerminalSize()
+
+	width := max(minWidth, lipgloss.Width(list.header())+4)
+	for _, item := range list.Items {
+		width = max(width, ansi.StringWidth(item.Name())+6)
+	}
+
+	list.width = min(width, max(w-4, minWidth))
+	list.height = max(1, min(len(list.Items), maxHeight, h/2))
+
+	list.Viewport.SetWidth(list.width)
+	list.Viewport.SetHeight(list.height)
+
+	for _, item := range list.Items {
+		item.SetWidth(list.width - 2)
+	}
+}
+
+// Load replaces the items of the list with the given suggestions
+// for the given word
+func (list *SuggestionList) Load(word string, suggestions []string) {
+	list.word = word
+	list.Items = make([]*SuggestionListItem, 0, len(suggestions))
+
+	for i, suggestion := range suggestions {
+		item := &SuggestionListItem{}
+		item.SetIndex(i)
+		item.SetName(suggestion)
+
+		list.Items = append(list.Items, item)
+	}
+
+	list.UpdateSize()
+
+	list.Length = len(list.Items)
+	list.LastIndex = max(0, list.Length-1)
+	list.SelectedIndex = 0
+	list.FirstVisibleLine = 0
+	list.LastVisibleLine = list.height - 1
+	list.VisibleLines = list.height - 1
+	list.Viewport.GotoTop()
+}
+
+// Init initialises the Model on program load.
+// It partly implements the tea.Model interface.
+func (list *SuggestionList) Init() tea.Cmd {
+	return nil
+}
+
+func (list *SuggestionList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		list.UpdateSize()
+	}
+
+	if list.Focused() {
+		if !list.IsReady {
+			list.Viewport = viewport.New()
+			list.Viewport.KeyMap = viewport.KeyMap{}
+			list.UpdateSize()
+			list.IsReady = true
+		}
+
+		list.updateOverlay()
+
+		var cmd tea.Cmd
+		list.Viewport, cmd = list.Viewport.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	return list, tea.Batch(cmds...)
+}
+
+func (list *SuggestionList) View() tea.View {
+	var view tea.View
+	view.SetContent(list.Content())
+	return view
+}
+
+func (list *SuggestionList) Content() string {
+	list.Viewport.SetContent(list.render())
+
+	t := list.Theme()
+	body := lipgloss.NewStyle().
+		Border(t.BorderStyle()).
+		BorderTop(false).
+		BorderForeground(theme.ColourBorderFocused).
+		Width(list.width).
+		Render(list.Viewport.View())
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		t.Header(list.header(), list.width, true),
+		body,
+	)
+}
+
+// header returns the title of the list, it names the misspelled word
+func (list *SuggestionList) header() string {
+	return "Change " + strconv.Quote(list.word) + " to"
+}
+
+func (list *SuggestionList) render() string {
+	lines := make([]string, 0, len(list.Items))
+
+	for i, item := range list.Items {
+		item.IsSelected = list.SelectedIndex == i
+		lines = append(lines, item.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SelectedSuggestion returns the selected suggestion
+func (list *SuggestionList) SelectedSuggestion() (string, bool) {
+	if list.Length == 0 {
+		return "", false
+	}
+
+	return list.SelectedItem(nil).Name(), true
+}
+
+func (list *SuggestionList) updateOverlay() {
+	x, y := list.Overlay.CalculatePosition(list.width)
+
+	list.Overlay.SetPosition(x, y)
+	list.Overlay.SetContent(list.Content())
+}
+
+func (list *SuggestionList) ConfirmAction() message.StatusBarMsg {
+	return message.StatusBarMsg{}
+}
+
+func (list *SuggestionList) PasteSelectedItems() message.StatusBarMsg {
+	return message.StatusBarMsg{}
+}
+
+func (list *SuggestionList) TogglePinnedItems() message.StatusBarMsg {
+	return message.StatusBarMsg{}
+}
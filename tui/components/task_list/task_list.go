@@ -0,0 +1,259 @@
+package tasklist
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"bellbird-notes/app/config"
+	"bellbird-notes/app/notes"
+	"bellbird-notes/app/utils"
+	"bellbird-notes/tui/components/overlay"
+	"bellbird-notes/tui/message"
+	"bellbird-notes/tui/mode"
+	"bellbird-notes/tui/shared"
+	"bellbird-notes/tui/theme"
+)
+
+// TaskListItem is either an open task or the note the following
+// tasks belong to
+type TaskListItem struct {
+	shared.Item
+
+	// Line is the line number of the task within the note.
+	// It's 0 for the items of notes
+	Line int
+}
+
+// IsNote returns whether the item is the heading of a note
+func (item TaskListItem) IsNote() bool {
+	return item.Line == 0
+}
+
+func (item TaskListItem) render(content string, faded bool, w int) string {
+	style := lipgloss.NewStyle().
+		Foreground(lipgloss.NoColor{}).
+		Width(w)
+
+	if faded {
+		style = style.Foreground(theme.ColourBorder)
+	}
+
+	if item.IsNote() {
+		style = style.Foreground(theme.ColourBorderFocused).Bold(true)
+	}
+
+	if item.IsSelected {
+		style = style.Background(theme.ColourBgSelected)
+	}
+
+	return style.Render(content)
+}
+
+// String is the string representation of a TaskListItem
+func (item TaskListItem) String() string {
+	if item.IsNote() {
+		icon := theme.Icon(theme.IconNote, item.NerdFonts)
+		name := ansi.Truncate(icon+" "+item.Name(), item.Width()-1, "…")
+		return item.render(" "+name, false, item.Width())
+	}
+
+	line := item.render(strconv.Itoa(item.Line)+" ", true, 0)
+	textWidth := max(0, item.Width()-lipgloss.Width(line)-5)
+	text := item.render(
+		"   [ ] "+ansi.Truncate(item.Name(), textWidth, "…"),
+		false,
+		item.Width()-lipgloss.Width(line),
+	)
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, text, line)
+}
+
+// TaskList lists the open tasks of all notes grouped by note
+type TaskList struct {
+	shared.List[*TaskListItem]
+
+	width  int
+	height int
+
+	Overlay *overlay.Overlay
+}
+
+func New(title string, conf *config.Config) *TaskList {
+	var list shared.List[*TaskListItem]
+	list.MakeEmpty()
+	list.Conf = conf
+
+	panel := &TaskList{
+		List:    list,
+		Overlay: &overlay.Overlay{},
+	}
+
+	panel.SetTitle(title)
+	panel.SetTheme(theme.New(conf))
+	panel.UpdateSize()
+	panel.Blur()
+	panel.Mode = mode.Normal
+
+	return panel
+}
+
+// ListSize returns the size of the list without its header
+func (list TaskList) ListSize() (int, int) {
+	w, h := theme.TerminalSize()
+	return w * 2 / 3, max(h/2, 5)
+}
+
+func (list *TaskList) UpdateSize() {
+	w, h := list.ListSize()
+	list.Viewport.SetWidth(w)
+	list.Viewport.SetHeight(h)
+	list.width = w
+	list.height = h
+}
+
+// Load collects the open tasks of all notes within the given directory
+func (list *TaskList) Load(root string) error {
+	tasks, err := notes.OpenTasks(root)
+	if err != nil {
+		return err
+	}
+
+	list.Items = make([]*TaskListItem, 0, len(tasks))
+	nerdFonts := list.Conf.NerdFonts()
+	path := ""
+
+	add := func(name string, path string, line int) {
+		item := &TaskListItem{Line: line}
+		item.SetIndex(len(list.Items))
+		item.SetName(name)
+		item.SetPath(path)
+		item.SetWidth(list.width - 2)
+		item.NerdFonts = nerdFonts
+
+		list.Items = append(list.Items, item)
+	}
+
+	for _, task := range tasks {
+		if task.Path != path {
+			path = task.Path
+			add(utils.RelativePath(path, true), path, 0)
+		}
+
+		add(task.Text, task.Path, task.Line)
+	}
+
+	list.Length = len(list.Items)
+	list.LastIndex = max(0, list.Length-1)
+	list.SelectedIndex = 0
+	list.FirstVisibleLine = 0
+	list.LastVisibleLine = list.height - 1
+	list.VisibleLines = list.height - 1
+	list.Viewport.GotoTop()
+
+	return nil
+}
+
+// Init initialises the Model on program load.
+// It partly implements the tea.Model interface.
+func (list *TaskList) Init() tea.Cmd {
+	return nil
+}
+
+func (list *TaskList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		list.UpdateSize()
+	}
+
+	if list.Focused() {
+		if !list.IsReady {
+			list.Viewport = viewport.New()
+			list.Viewport.KeyMap = viewport.KeyMap{}
+			list.UpdateSize()
+			list.IsReady = true
+		}
+
+		list.updateOverlay()
+
+		var cmd tea.Cmd
+		list.Viewport, cmd = list.Viewport.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	return list, tea.Batch(cmds...)
+}
+
+func (list *TaskList) View() tea.View {
+	var view tea.View
+	view.SetContent(list.Content())
+	return view
+}
+
+func (list *TaskList) Content() string {
+	list.Viewport.SetContent(list.render())
+
+	t := list.Theme()
+	body := lipgloss.NewStyle().
+		Border(t.BorderStyle()).
+		BorderTop(false).
+		BorderForeground(theme.ColourBorderFocused).
+		Width(list.width).
+		Render(list.Viewport.View())
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		t.Header(list.Title(), list.width, true),
+		body,
+	)
+}
+
+func (list *TaskList) render() string {
+	if len(list.Items) == 0 {
+		return lipgloss.NewStyle().
+			Foreground(theme.ColourBorder).
+			Render(" " + message.StatusBar.NoTasks)
+	}
+
+	lines := make([]string, 0, len(list.Items))
+
+	for i, item := range list.Items {
+		item.IsSelected = list.SelectedIndex == i
+		lines = append(lines, item.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SelectedTask returns the selected task or note
+func (list *TaskList) SelectedTask() *TaskListItem {
+	if list.Length == 0 {
+		return nil
+	}
+
+	return list.SelectedItem(nil)
+}
+
+func (list *TaskList) updateOverlay() {
+	x, y := list.Overlay.CalculatePosition(list.width)
+
+	list.Overlay.SetPosition(x, y)
+	list.Overlay.SetContent(list.Content())
+}
+
+func (list *TaskList) ConfirmAction() message.StatusBarMsg {
+	return message.StatusBarMsg{}
+}
+
+func (list *TaskList) PasteSelectedItems() message.StatusBarMsg {
+	return message.StatusBarMsg{}
+}
+
+func (list *TaskList) TogglePinnedItems() message.StatusBarMsg {
+	return message.StatusBarMsg{}
+}
@@ -0,0 +1,228 @@
+package textarea
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	rw "github.com/mattn/go-runewidth"
+)
+
+// Block is a rectangular selection spanning the rows StartRow to EndRow.
+// The columns are display columns rather than rune offsets so that
+// blocks stay aligned in lines containing wide runes
+type Block struct {
+	StartRow, EndRow int
+
+	// StartCol is the first selected display column,
+	// EndCol is the display column after the last selected one
+	StartCol, EndCol int
+
+	// ToLineEnd indicates that the block extends to the end
+	// of every line, e.g. after pressing `$`
+	ToLineEnd bool
+}
+
+// DisplayCol returns the display column of the rune at `col`
+func DisplayCol(line []rune, col int) int {
+	width := 0
+	for _, r := range line[:clamp(col, 0, len(line))] {
+		width += rw.RuneWidth(r)
+	}
+	return width
+}
+
+// runeWidth returns the display width of the rune at `col`.
+// Positions past the end of the line count as one column
+func runeWidth(line []rune, col int) int {
+	if col < 0 || col >= len(line) {
+		return 1
+	}
+	return max(rw.RuneWidth(line[col]), 1)
+}
+
+// Cols returns the range of runes of the given line that are
+// within the block. The rune at the end offset is not included.
+// Wide runes that are only partially covered by the block are included
+func (b Block) Cols(line []rune) (int, int) {
+	start, end := len(line), len(line)
+	width := 0
+
+	for i, r := range line {
+		w := rw.RuneWidth(r)
+
+		if start == len(line) && width+w > b.StartCol {
+			start = i
+		}
+
+		if !b.ToLineEnd && width >= b.EndCol {
+			end = i
+			break
+		}
+
+		width += w
+	}
+
+	return start, max(start, end)
+}
+
+// BlockSelection returns the block spanned by the start of the
+// selection and the cursor
+func (m *Model) BlockSelection() Block {
+	startRow := clamp(m.Selection.StartRow, 0, len(m.value)-1)
+	startLine := m.value[startRow]
+	line := m.value[m.row]
+
+	startCol := DisplayCol(startLine, m.Selection.StartCol)
+	cursorCol := DisplayCol(line, m.col)
+
+	return Block{
+		StartRow: min(startRow, m.row),
+		EndRow:   max(startRow, m.row),
+		StartCol: min(startCol, cursorCol),
+		EndCol: max(
+			startCol+runeWidth(startLine, m.Selection.StartCol),
+			cursorCol+runeWidth(line, m.col),
+		),
+		ToLineEnd: m.Selection.ToLineEnd && m.col >= len(line)-1,
+	}
+}
+
+// BlockStr returns the text within the block, one string per row
+func (m *Model) BlockStr(b Block) []string {
+	lines := []string{}
+
+	for row := b.StartRow; row <= b.EndRow && row < len(m.value); row++ {
+		start, end := b.Cols(m.value[row])
+		lines = append(lines, string(m.value[row][start:end]))
+	}
+
+	return lines
+}
+
+// DeleteBlock deletes the text within the block, moves the cursor to
+// its top left corner and returns the deleted text, one string per row
+func (m *Model) DeleteBlock(b Block) []string {
+	lines := m.BlockStr(b)
+
+	for row := b.StartRow; row <= b.EndRow && row < len(m.value); row++ {
+		start, end := b.Cols(m.value[row])
+		m.value[row] = slices.Delete(m.value[row], start, end)
+	}
+	m.version++
+
+	m.row = b.StartRow
+	m.SetCursorColumn(m.ColumnAt(b.StartRow, b.StartCol))
+
+	return lines
+}
+
+// MapBlock replaces every rune within the block with the result of fn
+func (m *Model) MapBlock(b Block, fn func(rune) rune) {
+	m.version++
+
+	for row := b.StartRow; row <= b.EndRow && row < len(m.value); row++ {
+		start, end := b.Cols(m.value[row])
+
+		for i := start; i < end; i++ {
+			m.value[row][i] = fn(m.value[row][i])
+		}
+	}
+}
+
+// ColumnAt returns the offset of the rune at the given display column
+// of the line at `row`. Display columns past the end of the line
+// return the length of the line
+func (m *Model) ColumnAt(row int, displayCol int) int {
+	return columnAt(m.value[row], displayCol)
+}
+
+// columnAt returns the offset of the rune of the line at the given
+// display column or the length of the line past its end
+func columnAt(line []rune, displayCol int) int {
+	width := 0
+
+	for i, r := range line {
+		width += rw.RuneWidth(r)
+		if width > displayCol {
+			return i
+		}
+	}
+
+	return len(line)
+}
+
+// InsertAtDisplayCol inserts text into the line at `row` at the given
+// display column. Lines that are too short are padded with spaces if
+// pad is true, otherwise nothing is inserted.
+// Returns false if nothing has been inserted
+func (m *Model) InsertAtDisplayCol(row int, displayCol int, text string, pad bool) bool {
+	if row < 0 || row >= len(m.value) {
+		return false
+	}
+
+	line := m.value[row]
+
+	if width := DisplayCol(line, len(line)); width < displayCol {
+		if !pad {
+			return false
+		}
+		line = append(line, []rune(strings.Repeat(" ", displayCol-width))...)
+	}
+
+	col := columnAt(line, displayCol)
+	m.value[row] = slices.Insert(line, col, []rune(text)...)
+	m.version++
+	return true
+}
+
+// PasteBlock pastes the given lines as a block below each other
+// starting at the cursor's display column. Lines are added at the
+// end of the buffer if the block doesn't fit
+func (m *Model) PasteBlock(lines []string, displayCol int) {
+	width := 0
+	for _, l := range lines {
+		width = max(width, rw.StringWidth(l))
+	}
+
+	for i, l := range lines {
+		row := m.row + i
+
+		if row >= len(m.value) {
+			m.spliceLines(row, row, [][]rune{{}}, nil)
+		}
+
+		// pad the pasted line so that the text after it stays aligned
+		// unless it's inserted at the end of the line
+		text := []rune(l)
+		if DisplayCol(m.value[row], len(m.value[row])) > displayCol {
+			padding := strings.Repeat(" ", width-rw.StringWidth(l))
+			text = append(text, []rune(padding)...)
+		}
+
+		m.InsertAtDisplayCol(row, displayCol, string(text), true)
+	}
+}
+
+// RenderBlockSelection renders a wrapped line of a line that is part
+// of a block selection.
+// `offset` is the rune offset of the wrapped line within the whole line
+func (m *Model) RenderBlockSelection(
+	line, wrappedLine *[]rune,
+	l, offset int,
+	s *strings.Builder,
+	style *lipgloss.Style,
+) {
+	b := m.BlockSelection()
+	if l < b.StartRow || l > b.EndRow {
+		m.RenderLine(wrappedLine, l, offset, s, style)
+		return
+	}
+
+	wrLine := *wrappedLine
+	start, end := b.Cols(*line)
+	start = clamp(start-offset, 0, len(wrLine))
+	end = clamp(end-offset, start, len(wrLine))
+
+	m.renderSelected(wrLine, start, end, m.cursorCol(l, offset, len(wrLine)), s, style)
+}
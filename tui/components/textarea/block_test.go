@@ -0,0 +1,67 @@
+package textarea
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInsertAtDisplayCol(t *testing.T) {
+	tests := []struct {
+		line     string
+		col      int
+		pad      bool
+		expected string
+	}{
+		{"abcd", 2, false, "ab!cd"},
+		{"abcd", 4, false, "abcd!"},
+		{"e", 3, true, "e  !"},
+		{"", 2, true, "  !"},
+		{"e", 3, false, "e"},
+		{"日本", 2, false, "日!本"},
+	}
+
+	for _, test := range tests {
+		m := New()
+		m.SetValue(test.line)
+		m.InsertAtDisplayCol(0, test.col, "!", test.pad)
+
+		if got := m.Value(); got != test.expected {
+			t.Errorf("%q at %d: expected %q, got %q", test.line, test.col, test.expected, got)
+		}
+	}
+}
+
+func TestPasteBlock(t *testing.T) {
+	m := New()
+	m.SetValue("abcd\ne\nabcd")
+	m.MoveCursor(0, 0, 0)
+
+	m.PasteBlock([]string{"x", "yy", "z"}, 3)
+
+	expected := "abcx d\ne  yy\nabcz d"
+	if got := m.Value(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestBlockSelectionWideRunes(t *testing.T) {
+	m := New()
+	m.SetValue("abcd\n日本語\nabcd")
+	m.SelectRange(
+		SelectVisualBlock,
+		CursorPos{Row: 0, ColumnOffset: 2},
+		CursorPos{Row: 2, ColumnOffset: 2},
+	)
+
+	// the wide rune that is partially covered by the block is included
+	b := m.BlockSelection()
+	if got := strings.Join(m.BlockStr(b), "\n"); got != "c\n本\nc" {
+		t.Errorf("expected block with wide rune, got %q", got)
+	}
+
+	m.DeleteBlock(b)
+
+	if got := m.Value(); got != "abd\n日語\nabd" {
+		t.Errorf("expected the block to be deleted, got %q", got)
+	}
+}
@@ -0,0 +1,337 @@
+package textarea
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"bellbird-notes/tui/theme"
+
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// Highlight is the kind of markdown construct a character belongs to
+type Highlight int
+
+const (
+	HighlightNone Highlight = iota
+	HighlightHeading
+	HighlightBold
+	HighlightItalic
+	HighlightCode
+	HighlightLink
+	HighlightURL
+	HighlightListMarker
+	HighlightQuote
+)
+
+// DefaultHighlightStyles returns the styles of the markdown highlights
+func DefaultHighlightStyles() map[Highlight]lipgloss.Style {
+	return map[Highlight]lipgloss.Style{
+		HighlightHeading:    lipgloss.NewStyle().Foreground(theme.ColourBorderFocused).Bold(true),
+		HighlightBold:       lipgloss.NewStyle().Bold(true),
+		HighlightItalic:     lipgloss.NewStyle().Italic(true),
+		HighlightCode:       lipgloss.NewStyle().Foreground(theme.ColourSearchHighlightFocused),
+		HighlightLink:       lipgloss.NewStyle().Foreground(theme.ColourBorderFocused),
+		HighlightURL:        lipgloss.NewStyle().Foreground(theme.ColourBorder).Underline(true),
+		HighlightListMarker: lipgloss.NewStyle().Foreground(theme.ColourBorderFocused),
+		HighlightQuote:      lipgloss.NewStyle().Foreground(theme.ColourTitle).Italic(true),
+	}
+}
+
+var (
+	headingRegex    = regexp.MustCompile(`^ {0,3}#{1,6}(?:\s|$)`)
+	quoteRegex      = regexp.MustCompile(`^ {0,3}>`)
+	listMarkerRegex = regexp.MustCompile(`^\s*(?:[-*+]|\d{1,9}[.)])(?:\s+\[[ xX]\])?(?:\s|$)`)
+	fenceRegex      = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+
+	linkRegex = regexp.MustCompile(`!?\[([^\]]*)\]\(([^)\s]*(?:\s+"[^"]*")?)\)`)
+	urlRegex  = regexp.MustCompile(`<https?://[^>\s]+>|https?://[^\s<>()\[\]]+`)
+
+	// emphasis has to start and end with a non white space character.
+	// Underscores only emphasise whole words
+	boldRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`\*\*[^\s*](?:[^*]*[^\s*])?\*\*`),
+		regexp.MustCompile(`(?:^|[^\pL\pN_])(__[^\s_](?:[^_]*[^\s_])?__)(?:[^\pL\pN_]|$)`),
+	}
+	italicRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`\*[^\s*](?:[^*]*[^\s*])?\*`),
+		regexp.MustCompile(`(?:^|[^\pL\pN_])(_[^\s_](?:[^_]*[^\s_])?_)(?:[^\pL\pN_]|$)`),
+	}
+)
+
+// fence is the opening line of a fenced code block, e.g. "```go".
+// The block is closed by a line with at least as many of the same
+// characters. The zero value is outside of a block
+type fence struct {
+	char   rune
+	length int
+}
+
+// highlightInput is the input of the memoised highlighting of a line
+type highlightInput struct {
+	runes []rune
+	fence fence
+}
+
+// Hash returns a hash of the line and the fence it's within
+func (h highlightInput) Hash() string {
+	v := fmt.Sprintf("%s:%c:%d", string(h.runes), h.fence.char, h.fence.length)
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(v)))
+}
+
+// highlightedLine holds the highlight of every rune of a line
+// and the fenced code block that is open after the line
+type highlightedLine struct {
+	highlights []Highlight
+	fence      fence
+}
+
+func (m Model) memoizedHighlight(runes []rune, f fence) highlightedLine {
+	input := highlightInput{runes: runes, fence: f}
+	if v, ok := m.highlightCache.Get(input); ok {
+		return v
+	}
+	v := highlightLine(runes, f)
+	m.highlightCache.Set(input, v)
+	return v
+}
+
+// highlightLine returns the markdown highlights of a line that
+// follows the given fenced code block
+func highlightLine(runes []rune, f fence) highlightedLine {
+	str := string(runes)
+	h := highlightedLine{
+		highlights: make([]Highlight, len(runes)),
+		fence:      f,
+	}
+
+	fill := func(hl Highlight) {
+		for i := range h.highlights {
+			h.highlights[i] = hl
+		}
+	}
+
+	if m := fenceRegex.FindStringSubmatch(str); m != nil {
+		char, _ := utf8.DecodeRuneInString(m[1])
+		length := utf8.RuneCountInString(m[1])
+
+		switch {
+		// the closing fence must not have an info string
+		case f.length > 0 && char == f.char && length >= f.length &&
+			strings.TrimSpace(str[len(m[0]):]) == "":
+
+			h.fence = fence{}
+		case f.length == 0:
+			h.fence = fence{char: char, length: length}
+		}
+
+		fill(HighlightCode)
+		return h
+	}
+
+	if f.length > 0 {
+		fill(HighlightCode)
+		return h
+	}
+
+	if headingRegex.MatchString(str) {
+		fill(HighlightHeading)
+		return h
+	}
+
+	if quoteRegex.MatchString(str) {
+		fill(HighlightQuote)
+	} else if loc := listMarkerRegex.FindStringIndex(str); loc != nil {
+		h.set(str, loc[0], loc[1], HighlightListMarker, true)
+	}
+
+	h.highlightInline(str)
+
+	return h
+}
+
+// set highlights the runes within the given byte offsets.
+// Runes that are highlighted already are kept unless override is true
+func (h *highlightedLine) set(str string, start, end int, hl Highlight, override bool) {
+	from := utf8.RuneCountInString(str[:start])
+	to := from + utf8.RuneCountInString(str[start:end])
+
+	for i := from; i < to && i < len(h.highlights); i++ {
+		if override || h.highlights[i] == HighlightNone || h.highlights[i] == HighlightQuote {
+			h.highlights[i] = hl
+		}
+	}
+}
+
+// highlightInline highlights code spans, links and emphasis.
+// Code spans come first since nothing within them is highlighted
+func (h *highlightedLine) highlightInline(str string) {
+	for i := 0; i < len(str); {
+		if str[i] != '`' {
+			i++
+			continue
+		}
+
+		n := 0
+		for i+n < len(str) && str[i+n] == '`' {
+			n++
+		}
+
+		// a code span ends with as many backticks as it starts with
+		marker := str[i : i+n]
+		end := -1
+
+		for j := i + n; j < len(str); {
+			k := strings.Index(str[j:], marker)
+			if k < 0 {
+				break
+			}
+
+			k += j
+			if k+n < len(str) && str[k+n] == '`' {
+				j = k + n
+				for j < len(str) && str[j] == '`' {
+					j++
+				}
+				continue
+			}
+
+			end = k + n
+			break
+		}
+
+		if end < 0 {
+			i += n
+			continue
+		}
+
+		h.set(str, i, end, HighlightCode, true)
+		i = end
+	}
+
+	for _, m := range linkRegex.FindAllStringSubmatchIndex(str, -1) {
+		h.set(str, m[2], m[3], HighlightLink, false)
+		h.set(str, m[4], m[5], HighlightURL, false)
+	}
+
+	for _, loc := range urlRegex.FindAllStringIndex(str, -1) {
+		h.set(str, loc[0], loc[1], HighlightURL, false)
+	}
+
+	for _, hl := range []struct {
+		regexes   []*regexp.Regexp
+		highlight Highlight
+	}{
+		{boldRegexes, HighlightBold},
+		{italicRegexes, HighlightItalic},
+	} {
+		for _, re := range hl.regexes {
+			for _, m := range re.FindAllStringSubmatchIndex(str, -1) {
+				start, end := m[0], m[1]
+				if len(m) > 2 {
+					start, end = m[2], m[3]
+				}
+
+				h.set(str, start, end, hl.highlight, false)
+			}
+		}
+	}
+}
+
+// updateHighlights highlights the markdown of every line if
+// highlighting is enabled and the matches of the highlight rules
+func (m *Model) updateHighlights() {
+	m.lineHighlights = nil
+	m.lineRules = nil
+
+	if len(m.rules) > 0 {
+		m.lineRules = make([][]int, len(m.value))
+
+		for row, line := range m.value {
+			m.lineRules[row] = m.memoizedRules(line)
+		}
+	}
+
+	if !m.Markdown {
+		return
+	}
+
+	m.lineHighlights = make([][]Highlight, len(m.value))
+	f := fence{}
+
+	for row, line := range m.value {
+		h := m.memoizedHighlight(line, f)
+		m.lineHighlights[row] = h.highlights
+		f = h.fence
+	}
+}
+
+// setWrappedHighlights selects the highlights of the wrapped part
+// of a line that starts at offset
+func (m *Model) setWrappedHighlights(row, offset, length int) {
+	m.highlights = nil
+	m.ruleHits = nil
+
+	if row < len(m.lineHighlights) {
+		m.highlights = wrappedPart(m.lineHighlights[row], offset, length)
+	}
+
+	if row < len(m.lineRules) {
+		m.ruleHits = wrappedPart(m.lineRules[row], offset, length)
+	}
+}
+
+// wrappedPart returns the values of a wrapped part of a line
+func wrappedPart[T any](values []T, offset, length int) []T {
+	start := clamp(offset, 0, len(values))
+	return values[start:clamp(offset+length, start, len(values))]
+}
+
+// writeHighlighted writes the runes of the wrapped line from start
+// to end in the given style with their markdown highlights and the
+// styles of the highlight rules they match
+func (m *Model) writeHighlighted(
+	wrLine []rune,
+	start, end int,
+	s *strings.Builder,
+	st *lipgloss.Style,
+) {
+	type highlight struct {
+		markdown Highlight
+		rule     int
+	}
+
+	highlightAt := func(i int) highlight {
+		var hl highlight
+		if i < len(m.highlights) {
+			hl.markdown = m.highlights[i]
+		}
+		if i < len(m.ruleHits) {
+			hl.rule = m.ruleHits[i]
+		}
+		return hl
+	}
+
+	for start < end {
+		hl := highlightAt(start)
+
+		next := start + 1
+		for next < end && highlightAt(next) == hl {
+			next++
+		}
+
+		style := *st
+		if hlStyle, ok := m.HighlightStyles[hl.markdown]; ok {
+			style = hlStyle.Inherit(style)
+		}
+		if hl.rule > 0 && hl.rule <= len(m.rules) {
+			style = m.rules[hl.rule-1].Style.Inherit(style)
+		}
+
+		m.write(wrLine[start:next], s, &style)
+		start = next
+	}
+}
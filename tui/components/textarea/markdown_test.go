@@ -0,0 +1,89 @@
+package textarea
+
+import (
+	"testing"
+)
+
+func TestHighlightLine(t *testing.T) {
+	// highlights are written as one character per rune:
+	// h heading, b bold, i italic, c code, l link, u url,
+	// m list marker, q quote and . for none
+	short := map[Highlight]byte{
+		HighlightNone:       '.',
+		HighlightHeading:    'h',
+		HighlightBold:       'b',
+		HighlightItalic:     'i',
+		HighlightCode:       'c',
+		HighlightLink:       'l',
+		HighlightURL:        'u',
+		HighlightListMarker: 'm',
+		HighlightQuote:      'q',
+	}
+
+	tests := []struct {
+		name     string
+		lines    []string
+		expected []string
+	}{
+		{
+			name:     "heading",
+			lines:    []string{"## Title", "#hashtag"},
+			expected: []string{"hhhhhhhh", "........"},
+		},
+		{
+			name:     "emphasis",
+			lines:    []string{"a **b** _c_ *d*", "snake_case_word"},
+			expected: []string{"..bbbbb.iii.iii", "..............."},
+		},
+		{
+			name:     "code span",
+			lines:    []string{"x `a *b*` y", "``a ` b``"},
+			expected: []string{"..ccccccc..", "ccccccccc"},
+		},
+		{
+			name:     "link",
+			lines:    []string{"[a](b) <https://c>"},
+			expected: []string{".l..u..uuuuuuuuuuu"},
+		},
+		{
+			name:     "list",
+			lines:    []string{"- [ ] a", "  1. *b*", "-no"},
+			expected: []string{"mmmmmm.", "mmmmmiii", "..."},
+		},
+		{
+			name:     "quote",
+			lines:    []string{"> a `b`"},
+			expected: []string{"qqqqccc"},
+		},
+		{
+			name:     "fenced code block",
+			lines:    []string{"```go", "# not a heading", "~~~", "```", "# heading"},
+			expected: []string{"ccccc", "ccccccccccccccc", "ccc", "ccc", "hhhhhhhhh"},
+		},
+		{
+			name:     "unclosed fence",
+			lines:    []string{"~~~~", "```", "~~~", "*a*"},
+			expected: []string{"cccc", "ccc", "ccc", "ccc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := fence{}
+
+			for i, line := range tt.lines {
+				h := highlightLine([]rune(line), f)
+				f = h.fence
+
+				got := make([]byte, len(h.highlights))
+				for j, hl := range h.highlights {
+					got[j] = short[hl]
+				}
+
+				if string(got) != tt.expected[i] {
+					t.Errorf("line %q: expected %s, got %s", line, tt.expected[i], got)
+				}
+			}
+		})
+	}
+}
@@ -42,16 +42,15 @@ type Selection struct {
 	// The row the selection has been started on
 	StartRow int
 
-	// The offset from the start row on multilines.
-	StartRowOffset int
-
-	// The column offset the selection has been started in
+	// The column offset the selection has been started in,
+	// relative to the beginning of the whole line
 	StartCol int
 
-	Mode SelectionMode
+	// ToLineEnd indicates that a block selection extends to the
+	// end of every line
+	ToLineEnd bool
 
-	wrappedLline []rune
-	lineIndex    int
+	Mode SelectionMode
 
 	Content *string
 }
@@ -59,9 +58,8 @@ type Selection struct {
 // SelectionRange determines the range of the active selection
 func (s *Selection) Range(cursorPos CursorPos) (CursorPos, CursorPos) {
 	selectionStart := CursorPos{
-		s.StartRow,
-		s.StartRowOffset,
-		s.StartCol,
+		Row:          s.StartRow,
+		ColumnOffset: s.StartCol,
 	}
 
 	// current cursor position which usually indicates the end of the selection
@@ -88,12 +86,6 @@ const (
 	SelectVisualBlock
 )
 
-type SelectionContent struct {
-	Before  string
-	Content string
-	After   string
-}
-
 // CharacterLeft moves the cursor one character to the left.
 // If insideLine is set, the cursor is moved to the last
 // character in the previous line, instead of one past that.
@@ -194,8 +186,9 @@ func (m *Model) WordRight() {
 func (m *Model) WordRightEnd() {
 	m.col = clamp(m.col, 0, len(m.value[m.row])-1)
 
+	row := m.row
 	if m.tryNextLine() {
-		if len(m.value[m.row]) > 1 {
+		if m.row != row && len(m.value[m.row]) > 1 {
 			m.WordRightEnd()
 		}
 		return
@@ -235,9 +228,14 @@ func (m *Model) WordRightEnd() {
 	}
 }
 
+// tryNextLine moves the cursor to the beginning of the next line if it's
+// at the end of the current line. The cursor stays at the end of the last line.
+// Returns whether the cursor is at the end of the line
 func (m *Model) tryNextLine() bool {
 	if m.col >= len(m.value[m.row])-1 {
-		m.MoveCursor(m.row+1, m.LineInfo().RowOffset, 0)
+		if m.row < len(m.value)-1 {
+			m.MoveCursor(m.row+1, 0, 0)
+		}
 		return true
 	}
 	return false
@@ -307,7 +305,7 @@ func (m *Model) skipSymbols() {
 func (m *Model) FindCharacter(char string, back bool) *CursorPos {
 	if back {
 		for offset := len(m.value[m.row]); offset >= 0; offset-- {
-			if m.LineInfo().CharOffset <= offset {
+			if m.col <= offset {
 				continue
 			}
 
@@ -323,7 +321,7 @@ func (m *Model) FindCharacter(char string, back bool) *CursorPos {
 		}
 	} else {
 		for offset, r := range m.value[m.row] {
-			if offset <= m.LineInfo().CharOffset {
+			if offset <= m.col {
 				continue
 			}
 
@@ -362,6 +360,7 @@ func (m *Model) DeleteFromCursorToChar(char string, includeChar bool, prev bool)
 		}
 
 		m.value[m.row] = []rune(before + after)
+		m.version++
 
 		return true
 	}
@@ -409,8 +408,20 @@ func (m *Model) write(runes []rune, s *strings.Builder, st *lipgloss.Style) {
 	s.WriteString(st.Render(string(runes)))
 }
 
+// cursorCol returns the column of the cursor within the displayed part
+// of line `l` that starts at `offset` and is `length` runes long.
+// Returns -1 if the cursor isn't on it
+func (m *Model) cursorCol(l, offset, length int) int {
+	if m.row != l || m.col < offset || m.col >= offset+length {
+		return -1
+	}
+	return m.col - offset
+}
+
+// writeWithCursor writes the runes of the wrapped line from `start` to `end`
+// and the cursor at `cursor` if it's within them
 func (m *Model) writeWithCursor(
-	start, end int,
+	start, end, cursor int,
 	wrappedLine *[]rune,
 	s *strings.Builder,
 	st *lipgloss.Style,
@@ -420,12 +431,12 @@ func (m *Model) writeWithCursor(
 		return
 	}
 
-	if m.col >= start && m.col < end {
+	if cursor >= start && cursor < end {
 		// Before cursor
-		m.write(wrLine[start:m.col], s, st)
+		m.writeHighlighted(wrLine, start, cursor, s, st)
 
 		// cursor
-		m.virtualCursor.SetChar(string(wrLine[m.col]))
+		m.virtualCursor.SetChar(string(wrLine[cursor]))
 
 		// @todo make this fetch colours either from terminal
 		// or from config
@@ -436,123 +447,147 @@ func (m *Model) writeWithCursor(
 		s.WriteString(st.Render(m.virtualCursor.View()))
 
 		// After cursor
-		m.write(wrLine[m.col+1:end], s, st)
+		m.writeHighlighted(wrLine, cursor+1, end, s, st)
 	} else {
-		m.write(wrLine[start:end], s, st)
+		m.writeHighlighted(wrLine, start, end, s, st)
 	}
 }
 
+// RenderLine renders the part of line `l` starting at `offset`
+// that is displayed on a row
 func (m *Model) RenderLine(
-	line, wrappedLine *[]rune,
-	l, wl int,
+	wrappedLine *[]rune,
+	l, offset int,
 	s *strings.Builder,
 	style *lipgloss.Style,
 ) {
-	lineInfo := m.LineInfo()
-	m.col = clamp(m.col, 0, len(m.value[m.row]))
+	wrLine := *wrappedLine
+	cursor := m.cursorCol(l, offset, len(wrLine))
+
+	if cursor == -1 {
+		m.writeHighlighted(wrLine, 0, len(wrLine), s, style)
+		return
+	}
+
+	m.writeHighlighted(wrLine, 0, cursor, s, style)
+	m.virtualCursor.SetChar(string(wrLine[cursor]))
+	m.write([]rune(m.virtualCursor.View()), s, style)
+	m.writeHighlighted(wrLine, cursor+1, len(wrLine), s, style)
+}
 
+// RenderSelection renders a wrapped line of a line that is part of
+// a visual or visual line selection. `start` and `end` are the selected
+// columns of the whole line, `offset` is the offset of the wrapped line
+func (m *Model) RenderSelection(
+	start, end int,
+	wrappedLine *[]rune,
+	l, offset int,
+	s *strings.Builder,
+	style *lipgloss.Style,
+) {
 	wrLine := *wrappedLine
+	start = clamp(start-offset, 0, len(wrLine))
+	end = clamp(end-offset, start, len(wrLine))
 
-	if m.row == l && lineInfo.RowOffset == wl && len(wrLine) > m.col {
-		s.WriteString(style.Render(string(wrLine[:m.col])))
+	m.renderSelected(wrLine, start, end, m.cursorCol(l, offset, len(wrLine)), s, style)
+}
 
-		if m.col >= len(*line) && lineInfo.CharOffset >= m.width {
-			m.virtualCursor.SetChar(" ")
-			m.write([]rune(m.virtualCursor.View()), s, style)
+// renderSelected renders a wrapped line with the runes from `start`
+// to `end` in the selection style and the cursor at `cursor`
+func (m *Model) renderSelected(
+	wrLine []rune,
+	start, end, cursor int,
+	s *strings.Builder,
+	style *lipgloss.Style,
+) {
+	selStyle := style.Background(theme.ColourSelection)
+
+	segments := []struct {
+		start, end int
+		style      *lipgloss.Style
+	}{
+		{0, start, style},
+		{start, end, &selStyle},
+		{end, len(wrLine), style},
+	}
+
+	for _, seg := range segments {
+		if cursor >= seg.start && cursor < seg.end {
+			m.writeHighlighted(wrLine, seg.start, cursor, s, seg.style)
+			m.virtualCursor.SetChar(string(wrLine[cursor]))
+			m.write([]rune(m.virtualCursor.View()), s, seg.style)
+			m.writeHighlighted(wrLine, cursor+1, seg.end, s, seg.style)
 		} else {
-			m.virtualCursor.SetChar(string(wrLine[m.col]))
-			m.write([]rune(m.virtualCursor.View()), s, style)
-			m.write(wrLine[m.col+1:], s, style)
+			m.writeHighlighted(wrLine, seg.start, seg.end, s, seg.style)
 		}
-	} else {
-		m.write(wrLine, s, style)
 	}
 }
 
-func (m *Model) RenderSelection(
-	selection *SelectionContent,
-	line, wrappedLine *[]rune,
-	l, wl int,
+// RenderMultiSelection renders a wrapped line with the given ranges
+// highlighted as search matches
+func (m *Model) RenderMultiSelection(
+	matches [][2]int,
+	wrappedLine *[]rune,
+	l, offset int,
 	s *strings.Builder,
 	style *lipgloss.Style,
 ) {
-	s.WriteString(style.Render(selection.Before))
-
-	switch m.Selection.Mode {
-	case SelectVisual:
-		if m.LineInfo().RowOffset == m.Selection.StartRowOffset {
-			s.WriteString(style.Render(m.CursorBeforeSelection()))
-		}
+	hlStyle := lipgloss.NewStyle().
+		Background(theme.ColourSearchHighlight).
+		Foreground(theme.ColourSearchFg)
 
-		visStyle := style.Background(theme.ColourSelection)
-		m.write([]rune(selection.Content), s, &visStyle)
+	m.renderRanges(matches, &hlStyle, wrappedLine, l, offset, s, style)
+}
 
-		if m.LineInfo().RowOffset == m.Selection.StartRowOffset {
-			s.WriteString(style.Render(m.CursorAfterSelection()))
-		}
+// RenderMisspelled renders a wrapped line with the given ranges
+// underlined as misspelled words
+func (m *Model) RenderMisspelled(
+	ranges [][2]int,
+	wrappedLine *[]rune,
+	l, offset int,
+	s *strings.Builder,
+	style *lipgloss.Style,
+) {
+	hlStyle := style.
+		Underline(true).
+		Foreground(theme.ColourMisspelled)
 
-	case SelectVisualLine:
-		st := style.Background(theme.ColourSelection)
-		m.RenderLine(line, wrappedLine, l, wl, s, &st)
-	}
-	s.WriteString(selection.After)
+	m.renderRanges(ranges, &hlStyle, wrappedLine, l, offset, s, style)
 }
 
-func (m *Model) RenderMultiSelection(
-	matches *[]int,
+// renderRanges renders a wrapped line with the given ranges
+// in the given highlight style
+func (m *Model) renderRanges(
+	ranges [][2]int,
+	hlStyle *lipgloss.Style,
 	wrappedLine *[]rune,
-	l, wl int,
+	l, offset int,
 	s *strings.Builder,
 	style *lipgloss.Style,
 ) {
-	queryLen := len(m.Search.Query)
-	lineInfo := m.LineInfo()
 	wrLine := *wrappedLine
-	cursorRowMatch := (m.row == l && lineInfo.RowOffset == wl)
+	cursor := m.cursorCol(l, offset, len(wrLine))
 
 	cursorPos := 0
 
-	for _, hlStart := range *matches {
-		hlEnd := hlStart + queryLen
-
-		if hlStart > len(wrLine)-queryLen {
-			break
-		}
+	for _, match := range ranges {
+		hlStart, hlEnd := match[0], match[1]
 
 		// text segments before highlight
 		if hlStart > cursorPos {
-			if cursorRowMatch {
-				m.writeWithCursor(cursorPos, hlStart, wrappedLine, s, style)
-			} else {
-				m.write(wrLine[cursorPos:hlStart], s, style)
-			}
+			m.writeWithCursor(cursorPos, hlStart, cursor, wrappedLine, s, style)
 		}
 
-		match := wrLine[hlStart:hlEnd]
-
 		// Highlightes matches
-		hlStyle := lipgloss.NewStyle().
-			Background(theme.ColourSearchHighlight).
-			Foreground(theme.ColourSearchFg)
-
-		if cursorRowMatch {
-			m.writeWithCursor(hlStart, hlEnd, wrappedLine, s, &hlStyle)
-		} else {
-			m.write(match, s, &hlStyle)
-		}
+		m.writeWithCursor(hlStart, hlEnd, cursor, wrappedLine, s, hlStyle)
 
 		cursorPos = hlEnd
 	}
 
 	// Remainder after last matches
 	if cursorPos < len(wrLine) {
-		if cursorRowMatch {
-			m.writeWithCursor(cursorPos, len(wrLine), wrappedLine, s, style)
-		} else {
-			m.write(wrLine[cursorPos:], s, style)
-		}
+		m.writeWithCursor(cursorPos, len(wrLine), cursor, wrappedLine, s, style)
 	}
-
 }
 
 func (m *Model) LineLength(index int) int {
@@ -588,8 +623,9 @@ func (m *Model) IsAtLineEnd() bool {
 
 // MoveCursor() moves the cursor to the given position. If the position is
 // out of bounds the cursor will be moved to the start or end accordingly.
+// The column is relative to the beginning of the whole line, the soft
+// wrapped row follows from it so that rowOffset is ignored
 func (m *Model) MoveCursor(row int, rowOffset int, col int) {
-	//debug.LogDebug(row, rowOffset, col)
 	if row < 0 {
 		row = 0
 	}
@@ -602,11 +638,6 @@ func (m *Model) MoveCursor(row int, rowOffset int, col int) {
 		col = 0
 	}
 
-	for range rowOffset {
-		//debug.LogDebug(i, rowOffset)
-		m.CursorDown()
-	}
-
 	m.SetCursorColumn(col)
 
 	// Any time that we move the cursor horizontally we need to reset the last
@@ -614,11 +645,14 @@ func (m *Model) MoveCursor(row int, rowOffset int, col int) {
 	//m.lastCharOffset = 0
 }
 
+// CursorPos returns the cursor position. The column offset is relative
+// to the beginning of the whole line, RowOffset is the soft wrapped row
+// the cursor is on
 func (m *Model) CursorPos() CursorPos {
 	return CursorPos{
 		Row:          m.row,
 		RowOffset:    m.LineInfo().RowOffset,
-		ColumnOffset: m.LineInfo().ColumnOffset,
+		ColumnOffset: m.col,
 	}
 }
 
@@ -635,10 +669,8 @@ func (m *Model) SetCursorColor(color color.Color) {
 
 func (m *Model) EmptyLineAbove() {
 	if m.row == 0 {
-		// extend slice
-		m.value = m.value[:len(m.value)]
 		// add empty item at the beginning
-		m.value = append([][]rune{{}}, m.value...)
+		m.spliceLines(0, 0, [][]rune{{}}, nil)
 		// move column offset internally to the beginning of the line
 		m.SetCursorColumn(0)
 	} else {
@@ -649,7 +681,7 @@ func (m *Model) EmptyLineAbove() {
 }
 
 func (m *Model) EmptyLineBelow() {
-	m.value = slices.Insert(m.value, m.row+1, []rune{})
+	m.spliceLines(m.row+1, m.row+1, [][]rune{{}}, nil)
 	m.CursorDown()
 	m.RepositionView()
 }
@@ -659,6 +691,7 @@ func (m *Model) DeleteOuterWord() {
 
 	if m.col < len(m.value[m.row]) && unicode.IsSpace(m.value[m.row][m.col]) {
 		m.value[m.row] = slices.Delete(m.value[m.row], m.col, m.col+1)
+		m.version++
 	}
 
 	if m.col > 0 {
@@ -674,6 +707,7 @@ func (m *Model) DeleteInnerWord() {
 	// and don't walk back
 	if unicode.IsSpace(col) {
 		m.value[m.row] = slices.Delete(m.value[m.row], m.col, m.col+1)
+		m.version++
 	} else {
 		for {
 			m.characterLeft(false)
@@ -703,16 +737,16 @@ func (m *Model) DeleteInnerWord() {
 // DeleteLine deletes current line
 func (m *Model) DeleteLine() {
 	if m.row >= len(m.value)-1 {
-		m.value = m.value[:len(m.value)-1]
-		// if we're on the only availabe line create a fresh slice
+		m.spliceLines(len(m.value)-1, len(m.value), nil, nil)
+		// if we're on the only availabe line create a fresh line
 		// to ensure there's always at least one line available
 		if len(m.value) == 0 {
-			m.value = make([][]rune, 1)
+			m.spliceLines(0, 0, [][]rune{{}}, nil)
 		} else {
 			m.row--
 		}
 	} else {
-		m.value = slices.Delete(m.value, m.row, m.row+1)
+		m.spliceLines(m.row, m.row+1, nil, nil)
 	}
 }
 
@@ -735,9 +769,10 @@ func (m *Model) DeleteSelectedLines() {
 		// we just empty it
 		if len(m.value) == 1 {
 			m.value[0] = slices.Delete(m.value[0], 0, len(m.value[m.row]))
+			m.version++
 			break
 		}
-		m.value = slices.Delete(m.value, m.row, m.row+1)
+		m.spliceLines(m.row, m.row+1, nil, nil)
 		m.row = minRange.Row
 	}
 }
@@ -770,6 +805,7 @@ func (m *Model) DeleteWordRight() {
 	} else {
 		m.value[m.row] = append(m.value[m.row][:oldCol], m.value[m.row][m.col:]...)
 	}
+	m.version++
 
 	m.SetCursorColumn(oldCol)
 	//m.deleteWordRight()
@@ -781,7 +817,7 @@ func (m *Model) VimMergeLineBelow(row int) {
 	m.row = row
 	m.CursorLineEnd()
 	m.InsertRune(' ')
-	m.SetCursorColumn(m.LineInfo().ColumnOffset - 1)
+	m.SetCursorColumn(m.col - 1)
 	m.mergeLineBelow(row)
 }
 
@@ -816,6 +852,7 @@ func (m *Model) UpHalfPage() {
 // on with `replaceRune` rune
 func (m *Model) ReplaceRune(replaceWith rune) {
 	m.value[m.row][m.col] = replaceWith
+	m.version++
 }
 
 // FirstVisibleLine returns the first line of the viewport
@@ -827,63 +864,63 @@ func (p CursorPos) GreaterThan(other CursorPos) bool {
 	return p.Row > other.Row || (p.Row == other.Row && p.ColumnOffset > other.ColumnOffset)
 }
 
+// SelectionStr returns the selected text of a visual or visual line
+// selection. Selected line breaks are included
 func (m *Model) SelectionStr() string {
 	minRange, maxRange := m.Selection.Range(m.CursorPos())
-	minRow, maxRow := minRange.Row, maxRange.Row
 
-	if minRow < 0 {
+	if minRange.Row < 0 {
 		return ""
 	}
 
 	var str strings.Builder
-	minCol := minRange.ColumnOffset
-	maxCol := maxRange.ColumnOffset
 
-	// select whole lines in range in visual line mode
-	if m.Selection.Mode == SelectVisualLine {
-		for i := minRow; i <= maxRow; i++ {
-			str.WriteString(string(m.value[i]))
-			str.WriteRune('\n')
-		}
-	} else {
-		if minRow == maxRow {
-			line := string(m.value[minRow])
-			// selection on the same line
-			if minCol <= maxCol && maxCol < len(m.value[minRow]) {
-				str.WriteString(line[minCol : maxCol+1])
-			}
-		} else {
-			// get the selected part of the first line
-			if minCol <= len(m.value[minRow]) {
-				line := string(m.value[minRow])
-				// handles backward selection (if the selection starts at a lower
-				// line and ends on a higher line)
-				if m.row < maxRow && minCol > 0 {
-					minCol -= 1
-				}
-				str.WriteString(line[minCol:])
-				str.WriteRune('\n')
-			}
+	for row := minRange.Row; row <= maxRange.Row && row < len(m.value); row++ {
+		start, end, _ := m.selectedCols(row)
+		line := m.value[row]
 
-			// get any fully selected lines in between
-			if maxRow > minRow+1 {
-				for i := minRow + 1; i < maxRow; i++ {
-					str.WriteString(string(m.value[i]))
-					str.WriteRune('\n')
-				}
-			}
+		str.WriteString(string(line[start:min(end, len(line))]))
 
-			// get the selection of the last line
-			if maxCol+1 < len(m.value[maxRow]) {
-				line := string(m.value[maxRow])
-				str.WriteString(line[:maxCol+1])
-			}
+		if end > len(line) {
+			str.WriteRune('\n')
 		}
 	}
 
 	content := str.String()
 	m.Selection.Content = &content
-	return str.String()
+	return content
+}
+
+// selectedCols returns the columns of the line at `row` that are within
+// the visual or visual line selection. The column at `end` isn't selected,
+// an `end` past the end of the line means that the line break is selected.
+// Returns false if the line isn't selected
+func (m *Model) selectedCols(row int) (int, int, bool) {
+	if m.Selection.Mode != SelectVisual && m.Selection.Mode != SelectVisualLine {
+		return 0, 0, false
+	}
+
+	minRange, maxRange := m.Selection.Range(m.CursorPos())
+	if minRange.Row < 0 || row < minRange.Row || row > maxRange.Row {
+		return 0, 0, false
+	}
+
+	lineLen := len(m.value[row])
+	start, end := 0, lineLen+1
+
+	if m.Selection.Mode == SelectVisual {
+		if row == minRange.Row {
+			start = minRange.ColumnOffset
+		}
+		if row == maxRange.Row {
+			end = maxRange.ColumnOffset + 1
+		}
+	}
+
+	start = clamp(start, 0, lineLen)
+	end = clamp(end, start, lineLen+1)
+
+	return start, end, true
 }
 
 // DeleteRune deletes the rune at `col` on `row`.
@@ -892,6 +929,7 @@ func (m *Model) DeleteRune(row int, col int) string {
 	if col+1 <= len(m.value[row]) {
 		deletedChar = string(m.value[row][col])
 		m.value[row] = slices.Delete(m.value[row], col, col+1)
+		m.version++
 	}
 	return deletedChar
 }
@@ -949,16 +987,15 @@ func (m *Model) DeleteRunesInRange(minRange CursorPos, maxRange CursorPos) {
 
 		// remove any fully selected lines in between
 		if maxRow > minRow+1 {
-			val = slices.Delete(val, minRow+1, maxRow)
+			m.spliceLines(minRow+1, maxRow, nil, nil)
 		}
 
 		// merge first and last line
-		if len(val) > minRow+1 {
+		if len(m.value) > minRow+1 {
 			m.mergeLineBelow(minRow)
 		}
 	}
 
-	m.value = val
 	m.row = minRow
 	m.SetCursorColumn(minCol)
 	m.ResetSelection()
@@ -969,8 +1006,7 @@ func (m *Model) StartSelection(selectionMode SelectionMode) {
 	m.Selection.Cursor.Focus()
 	if m.Selection.StartRow < 0 {
 		m.Selection.StartRow = m.row
-		m.Selection.StartRowOffset = m.LineInfo().RowOffset
-		m.Selection.StartCol = m.LineInfo().ColumnOffset
+		m.Selection.StartCol = m.col
 	}
 	m.Selection.Mode = selectionMode
 }
@@ -1074,7 +1110,7 @@ func (m *Model) NewMultiSelection() [][]Selection {
 
 func (m *Model) ResetMultiSelection() {
 	m.Search.Query = ""
-	m.Search.Matches = make(map[int][]int, 0)
+	m.Search.Matches = nil
 }
 
 func (m *Model) SelectionStyle() lipgloss.Style {
@@ -1084,195 +1120,285 @@ func (m *Model) SelectionStyle() lipgloss.Style {
 // ResetSelection clears a selection
 func (m *Model) ResetSelection() {
 	m.Selection.StartRow = -1
-	m.Selection.StartRowOffset = -1
 	m.Selection.StartCol = -1
+	m.Selection.ToLineEnd = false
 	m.Selection.Mode = SelectNone
 }
 
-// SelectionContent returns the buffer content within the current selection
-// range, along with the unselected text before and after it.
-func (m *Model) SelectionContent() SelectionContent {
-	sel := m.Selection
+func (m *Model) CapitalizeRight() {
+	m.capitalizeRight()
+}
 
-	var (
-		line                       []rune
-		l, colOffset               int
-		minRange, maxRange, cursor CursorPos
-	)
+func (m *Model) Val() [][]rune {
+	return m.value
+}
 
-	line = sel.wrappedLline
-	l = sel.lineIndex
+// AbsCursorPos returns the cursor position with the column offset
+// relative to the beginning of the whole line instead of the wrapped line
+func (m *Model) AbsCursorPos() CursorPos {
+	return CursorPos{
+		Row:          m.row,
+		ColumnOffset: m.col,
+	}
+}
 
-	colOffset = m.LineInfo().ColumnOffset
-	rowOffset := m.LineInfo().RowOffset
-	minRange, maxRange = m.Selection.Range(m.CursorPos())
-	cursor = CursorPos{m.row, rowOffset, colOffset}
+// RangeStr returns the text between `from` and `to` excluding
+// the character at `to`.
+// Column offsets are relative to the beginning of the whole line.
+func (m *Model) RangeStr(from CursorPos, to CursorPos) string {
+	var str strings.Builder
 
-	isInRange := cursor.InRange(minRange, maxRange)
+	for row := from.Row; row <= to.Row && row < len(m.value); row++ {
+		line := m.value[row]
+		start, end := 0, len(line)
 
-	wrappedStr := string(line)
+		if row == from.Row {
+			start = clamp(from.ColumnOffset, 0, len(line))
+		}
 
-	var (
-		before    string
-		selection string
-		after     string
-	)
+		if row == to.Row {
+			end = clamp(to.ColumnOffset, start, len(line))
+		}
 
-	cursorOffset := colOffset
+		str.WriteString(string(line[start:end]))
 
-	if minRange.ColumnOffset < sel.StartCol {
-		cursorOffset = minRange.ColumnOffset
+		if row < to.Row {
+			str.WriteRune('\n')
+		}
 	}
 
-	isCursorBeforeSel := l == minRange.Row && cursorOffset < maxRange.ColumnOffset
+	return str.String()
+}
 
-	// slice for unicode safety
-	runes := []rune(wrappedStr)
-	lineLen := len(runes)
+// LinesStr returns the lines from row `from` to row `to` including
+// the trailing new line of the last line
+func (m *Model) LinesStr(from int, to int) string {
+	var str strings.Builder
 
-	if isInRange {
-		if sel.Mode == SelectVisualLine {
-			before = ""
-			if l >= minRange.Row && l <= maxRange.Row {
-				selection = string(runes)
-			}
-			after = ""
-		} else {
-			minCol := clamp(minRange.ColumnOffset, 0, lineLen)
-			maxCol := clamp(maxRange.ColumnOffset, 0, lineLen)
-			minRow, maxRow := minRange.Row, maxRange.Row
+	for row := max(from, 0); row <= to && row < len(m.value); row++ {
+		str.WriteString(string(m.value[row]))
+		str.WriteRune('\n')
+	}
 
-			if colOffset == minCol {
-				colOffset = maxCol
-			}
+	return str.String()
+}
+
+// ReplaceLines replaces the lines from row `from` to row `to` with
+// the given lines
+func (m *Model) ReplaceLines(from int, to int, lines []string) {
+	if from < 0 || to >= len(m.value) || from > to {
+		return
+	}
 
-			switch {
-			// single line selection
-			case minRow == l && maxRow == l:
-				before = string(runes[:minCol])
+	runes := make([][]rune, len(lines))
+	for i, line := range lines {
+		runes[i] = []rune(line)
+	}
 
-				if isCursorBeforeSel {
-					minCol = clamp(minCol+1, 0, lineLen)
-					colOffset = clamp(sel.StartCol+1, 0, lineLen)
-				}
+	// the replaced lines keep their ids as far as possible
+	m.syncLineIDs()
+	ids := slices.Clone(m.lineIDs[from:min(to+1, from+len(runes))])
+	ids = append(ids, m.newLineIDs(len(runes)-len(ids))...)
 
-				if colOffset <= lineLen {
-					selection = string(runes[minCol:colOffset])
-				}
+	m.spliceLines(from, to+1, runes, ids)
+	m.row = clamp(m.row, 0, len(m.value)-1)
+	m.SetCursorColumn(m.col)
+}
 
-				if maxCol < lineLen {
-					after = string(runes[maxCol+1:])
-				}
+// MoveLines moves the lines from row `start` to row `end` below the
+// line at row `dest` and moves the cursor to the last moved line.
+// A dest of -1 moves the lines above the first line.
+// Lines can't be moved into themselves
+func (m *Model) MoveLines(start int, end int, dest int) {
+	if start < 0 || end >= len(m.value) || start > end ||
+		dest < -1 || dest >= len(m.value) || (dest >= start && dest < end) {
 
-			// first line of multi selection
-			case minRow == l:
-				beforePos := minCol
-
-				if sel.StartRow > minRow {
-					if minCol < sel.StartCol {
-						minCol = clamp(minCol+1, 0, lineLen)
-					} else {
-						beforePos = minCol - 1
-						cursorOffset = minCol
-					}
-				}
+		return
+	}
 
-				if beforePos <= lineLen && beforePos >= 0 {
-					before = string(runes[:beforePos])
-				}
+	m.syncLineIDs()
+	lines := slices.Clone(m.value[start : end+1])
+	ids := slices.Clone(m.lineIDs[start : end+1])
+	m.spliceLines(start, end+1, nil, nil)
 
-				if minCol <= lineLen {
-					selection = string(runes[minCol:])
-				}
+	if dest > end {
+		dest -= len(lines)
+	}
 
-			// last line of multi selection
-			case maxRow == l:
-				beforePos := clamp(maxCol+1, 0, lineLen)
-				afterPos := maxCol
+	m.spliceLines(dest+1, dest+1, lines, ids)
+	m.row = dest + len(lines)
+	m.SetCursorColumn(m.col)
+}
 
-				if sel.StartRow > minRow {
-					afterPos = clamp(maxCol+1, 0, lineLen)
-				}
+// CopyLines inserts a copy of the lines from row `start` to row `end`
+// below the line at row `dest` and moves the cursor to the last copied
+// line. A dest of -1 inserts the copy above the first line
+func (m *Model) CopyLines(start int, end int, dest int) {
+	if start < 0 || end >= len(m.value) || start > end ||
+		dest < -1 || dest >= len(m.value) {
 
-				if afterPos <= lineLen {
-					selection = string(runes[:afterPos])
-				}
+		return
+	}
 
-				if beforePos <= lineLen {
-					after = string(runes[beforePos:])
-				}
+	lines := make([][]rune, 0, end-start+1)
+	for _, line := range m.value[start : end+1] {
+		lines = append(lines, slices.Clone(line))
+	}
 
-			// full line within selection
-			case l > minRow && l < maxRow:
-				selection = string(runes)
-			}
-		}
+	m.spliceLines(dest+1, dest+1, lines, nil)
+	m.row = dest + len(lines)
+	m.SetCursorColumn(m.col)
+}
+
+// JoinLines joins the lines from row `start` to row `end` into one line
+// and moves the cursor to the last join.
+// If spaces is true, the white space around the joins is replaced with
+// a single space, otherwise the lines are joined as they are
+func (m *Model) JoinLines(start int, end int, spaces bool) {
+	if start < 0 || end >= len(m.value) || start >= end {
+		return
 	}
 
-	if selection != "" {
-		return SelectionContent{
-			Before:  before,
-			Content: selection,
-			After:   after,
+	line := slices.Clone(m.value[start])
+	col := 0
+
+	for _, next := range m.value[start+1 : end+1] {
+		if spaces {
+			line = []rune(strings.TrimRightFunc(string(line), unicode.IsSpace))
+			next = []rune(strings.TrimLeftFunc(string(next), unicode.IsSpace))
+			col = len(line)
+
+			if len(line) > 0 && len(next) > 0 {
+				line = append(line, ' ')
+			}
+		} else {
+			col = len(line)
 		}
+
+		line = append(line, next...)
 	}
 
-	return SelectionContent{}
+	m.syncLineIDs()
+	m.spliceLines(start, end+1, [][]rune{line}, []int{m.lineIDs[start]})
+	m.row = start
+	m.SetCursorColumn(col)
 }
 
-// CursorBeforeSelection returns the cursor that is at the beginning
-// of a selection as a string.
-// Returns an empty string of the selection doesn't require a
-// cursor at the beginning (e.g. a forward selection)
-func (m *Model) CursorBeforeSelection() string {
-	wrappedLine := m.Selection.wrappedLline
-	lineIndex := m.Selection.lineIndex
-	cursorOffset := m.LineInfo().ColumnOffset
-	minRange, maxRange := m.Selection.Range(m.CursorPos())
+// MapRunesInRange replaces every rune between `from` and `to`, excluding
+// the rune at `to`, with the result of fn
+func (m *Model) MapRunesInRange(from CursorPos, to CursorPos, fn func(rune) rune) {
+	m.version++
+
+	for row := from.Row; row <= to.Row && row < len(m.value); row++ {
+		line := m.value[row]
+		start, end := 0, len(line)
+
+		if row == from.Row {
+			start = clamp(from.ColumnOffset, 0, len(line))
+		}
 
-	if lineIndex == minRange.Row &&
-		maxRange.Row <= m.Selection.StartRow &&
-		cursorOffset < len(wrappedLine) {
+		if row == to.Row {
+			end = clamp(to.ColumnOffset, start, len(line))
+		}
 
-		if cursorOffset < maxRange.ColumnOffset {
-			m.virtualCursor.SetChar(string(wrappedLine[cursorOffset]))
-		} else if lineIndex < m.Selection.StartRow && cursorOffset-1 >= 0 {
-			m.virtualCursor.SetChar(string(wrappedLine[cursorOffset-1]))
+		for i := start; i < end; i++ {
+			line[i] = fn(line[i])
 		}
-		return m.virtualCursor.View()
 	}
+}
+
+// DeleteRange deletes the text between `from` and `to` excluding
+// the character at `to` and returns the deleted text.
+// Column offsets are relative to the beginning of the whole line.
+func (m *Model) DeleteRange(from CursorPos, to CursorPos) string {
+	if from.Row < 0 || to.Row >= len(m.value) || from.GreaterThan(to) {
+		return ""
+	}
+
+	str := m.RangeStr(from, to)
+
+	startCol := clamp(from.ColumnOffset, 0, len(m.value[from.Row]))
+	endCol := clamp(to.ColumnOffset, 0, len(m.value[to.Row]))
+
+	if from.Row == to.Row {
+		endCol = max(startCol, endCol)
+	}
+
+	line := slices.Clone(m.value[from.Row][:startCol])
+	line = append(line, m.value[to.Row][endCol:]...)
 
-	return ""
+	m.spliceLines(from.Row+1, to.Row+1, nil, nil)
+	m.value[from.Row] = line
+	m.row = from.Row
+	m.SetCursorColumn(startCol)
+
+	return str
 }
 
-// CursorAfterSelection returns the cursor that is at the end
-// of a selection as a string.
-// Returns an empty string of the selection doesn't require a
-// cursor at the beginning (e.g. a backward selection)
-func (m *Model) CursorAfterSelection() string {
-	wrappedLine := m.Selection.wrappedLline
-	cursorOffset := m.LineInfo().ColumnOffset
+// LineID returns the id of the line at `row`.
+// A line keeps its id while it is edited or moved,
+// so it can still be found after lines above it have changed
+func (m *Model) LineID(row int) int {
+	m.syncLineIDs()
+	if row < 0 || row >= len(m.lineIDs) {
+		return 0
+	}
+	return m.lineIDs[row]
+}
 
-	minRange, maxRange := m.Selection.Range(m.CursorPos())
+// LineRow returns the current row of the line with the given id.
+// Returns false if the line doesn't exist anymore
+func (m *Model) LineRow(id int) (int, bool) {
+	m.syncLineIDs()
+	row := slices.Index(m.lineIDs, id)
+	return row, row >= 0
+}
 
-	if m.Selection.lineIndex == maxRange.Row &&
-		minRange.Row >= m.Selection.StartRow &&
-		cursorOffset < len(wrappedLine) &&
-		cursorOffset == maxRange.ColumnOffset {
+// Indentation returns the leading white space of the line at `row`
+func (m *Model) Indentation(row int) string {
+	if row < 0 || row >= len(m.value) {
+		return ""
+	}
+
+	line := m.value[row]
+	n := 0
 
-		m.virtualCursor.SetChar(string(wrappedLine[cursorOffset]))
-		return m.virtualCursor.View()
+	for n < len(line) && (line[n] == ' ' || line[n] == '\t') {
+		n++
 	}
 
-	return ""
+	return string(line[:n])
 }
 
-func (m *Model) CapitalizeRight() {
-	m.capitalizeRight()
+// SetIndentation replaces the leading white space of the line at `row`.
+// If the cursor is on that line it keeps its position within the text
+func (m *Model) SetIndentation(row int, indent string) {
+	m.ReplacePrefix(row, len([]rune(m.Indentation(row))), indent)
 }
 
-func (m *Model) Val() [][]rune {
-	return m.value
+// ReplacePrefix replaces the first `n` runes of the line at `row` with
+// the given prefix. If the cursor is on that line and behind the prefix
+// it keeps its position within the text
+func (m *Model) ReplacePrefix(row int, n int, prefix string) {
+	if row < 0 || row >= len(m.value) {
+		return
+	}
+
+	runes := []rune(prefix)
+	n = min(n, len(m.value[row]))
+
+	m.value[row] = append(runes, m.value[row][n:]...)
+	m.version++
+
+	if row != m.row {
+		return
+	}
+
+	if m.col >= n {
+		m.SetCursorColumn(m.col + len(runes) - n)
+	} else {
+		m.SetCursorColumn(min(m.col, len(runes)))
+	}
 }
 
 func (m *Model) GoTO(row int) {
@@ -0,0 +1,59 @@
+package textarea
+
+import (
+	"regexp"
+	"unicode/utf8"
+
+	"bellbird-notes/tui/components/textarea/memoization"
+
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// HighlightRule highlights the matches of a regular expression
+type HighlightRule struct {
+	Name  string
+	Regex *regexp.Regexp
+	Style lipgloss.Style
+}
+
+// SetHighlightRules replaces the rules whose matches are highlighted.
+// Where the matches of several rules overlap the last rule wins
+func (m *Model) SetHighlightRules(rules []HighlightRule) {
+	m.rules = rules
+	m.ruleCache = memoization.NewMemoCache[line, []int](maxLines)
+}
+
+// HighlightRules returns the rules whose matches are highlighted
+func (m Model) HighlightRules() []HighlightRule {
+	return m.rules
+}
+
+// memoizedRules returns, for every rune of the line, the number
+// of the rule it's highlighted by, starting at 1. 0 is no rule
+func (m Model) memoizedRules(runes []rune) []int {
+	input := line{runes: runes}
+	if v, ok := m.ruleCache.Get(input); ok {
+		return v
+	}
+	v := m.applyRules(runes)
+	m.ruleCache.Set(input, v)
+	return v
+}
+
+func (m Model) applyRules(runes []rune) []int {
+	str := string(runes)
+	res := make([]int, len(runes))
+
+	for i, rule := range m.rules {
+		for _, loc := range rule.Regex.FindAllStringIndex(str, -1) {
+			from := utf8.RuneCountInString(str[:loc[0]])
+			to := from + utf8.RuneCountInString(str[loc[0]:loc[1]])
+
+			for j := from; j < to; j++ {
+				res[j] = i + 1
+			}
+		}
+	}
+
+	return res
+}
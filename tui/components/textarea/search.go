@@ -1,166 +1,249 @@
 package textarea
 
 import (
+	"regexp"
 	"sort"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 type Search struct {
 	Query      string
 	IgnoreCase bool
-	Matches    map[int][]int
-	ExactWord  bool
+
+	// SmartCase respects the case if the query contains upper case
+	// letters even if IgnoreCase is set
+	SmartCase bool
+
+	// Regex interprets the query as a regular expression,
+	// otherwise the query is searched literally
+	Regex bool
+
+	// Backward is set for searches started with `?`.
+	// It reverses the direction of `n` and `N`
+	Backward bool
+
+	ExactWord bool
+
+	// Matches holds all matches of the query sorted by their position
+	Matches []Match
+
+	// cache holds the result of the last search so that the text is only
+	// searched again if the query or the text has changed
+	cache *searchCache
+}
+
+type searchCache struct {
+	expr      string
+	exactWord bool
+	re        *regexp.Regexp
+
+	// searched is set once the text has been searched for expr.
+	// version is the version of the text at that time
+	searched bool
+	version  int
+	matches  []Match
+}
+
+// Match is a match of the search query from Start up to but not
+// including End. Matches may span multiple lines.
+// Column offsets are relative to the beginning of the whole line
+type Match struct {
+	Start, End CursorPos
 }
 
 // FirstMatch returns the first item of a search result
 func (s Search) FirstMatch() CursorPos {
-	for _, r := range s.sortedRows(false) {
-		return CursorPos{
-			Row:          r,
-			RowOffset:    0,
-			ColumnOffset: s.Matches[r][0],
-		}
+	if len(s.Matches) == 0 {
+		return CursorPos{}
 	}
-	return CursorPos{}
+	return s.Matches[0].Start
 }
 
 // FindMatch returns the next or previous match from the current position.
-// If reverse is false, it searches forward
-// If reverse is true, it searches backward
+// If prev is false, it searches forward
+// If prev is true, it searches backward.
+// The search wraps around at the beginning and the end of the text
 func (s Search) FindMatch(current CursorPos, prev bool) (CursorPos, bool) {
-	matches := s.Matches
-	pos := CursorPos{}
-
-	if len(matches) == 0 {
-		return pos, false
+	if len(s.Matches) == 0 {
+		return CursorPos{}, false
 	}
 
-	rows := s.sortedRows(prev)
+	current.RowOffset = 0
 
-	// Helper: find next column in sorted slice depending
-	// on matching direction
-	findCol := func(cols []int, col int) (int, bool) {
-		sort.Ints(cols)
-		if prev {
-			for i := len(cols) - 1; i >= 0; i-- {
-				if cols[i] < col {
-					return cols[i], true
-				}
-			}
-		} else {
-			for _, c := range cols {
-				if c > col {
-					return c, true
-				}
+	if prev {
+		for i := len(s.Matches) - 1; i >= 0; i-- {
+			if current.GreaterThan(s.Matches[i].Start) {
+				return s.Matches[i].Start, true
 			}
 		}
-		return 0, false
+		return s.Matches[len(s.Matches)-1].Start, true
 	}
 
-	// Check current row for next column
-	if cols, ok := matches[current.Row]; ok {
-		if c, ok := findCol(cols, current.ColumnOffset); ok {
-			return CursorPos{current.Row, 0, c}, true
+	for _, match := range s.Matches {
+		if match.Start.GreaterThan(current) {
+			return match.Start, true
 		}
 	}
 
-	// Check rows > curRow for first column
-	for _, r := range rows {
-		if (prev && r < current.Row) || (!prev && r > current.Row) {
-			cols := matches[r]
-			sort.Ints(cols)
+	return s.Matches[0].Start, true
+}
+
+// hasUpper returns whether the query contains upper case letters
+// that aren't part of an escape sequence like `\S`
+func (s Search) hasUpper() bool {
+	runes := []rune(s.Query)
 
-			if len(cols) > 0 {
-				index := 0
-				if prev {
-					index = len(cols) - 1
-				}
-				return CursorPos{r, 0, cols[index]}, true
-			}
+	for i, r := range runes {
+		escaped := s.Regex && i > 0 && runes[i-1] == '\\'
+		if unicode.IsUpper(r) && !escaped {
+			return true
 		}
 	}
 
-	// Wrap around - return first column of smallest row
-	firstRow := rows[0]
-	cols := matches[firstRow]
-	sort.Ints(cols)
+	return false
+}
 
-	if len(cols) > 0 {
-		index := 0
-		if prev {
-			index = len(cols) - 1
-		}
-		return CursorPos{firstRow, 0, cols[index]}, true
+// Pattern returns the query as a regular expression without any flags
+func (s Search) Pattern() string {
+	if !s.Regex || s.ExactWord {
+		return regexp.QuoteMeta(s.Query)
 	}
+	return s.Query
+}
 
-	return CursorPos{}, false
+// expr returns the pattern of the query with its flags
+func (s Search) expr() string {
+	flags := "(?m)"
+	if s.IgnoreCase && !(s.SmartCase && s.hasUpper()) {
+		flags = "(?mi)"
+	}
+
+	return flags + s.Pattern()
 }
 
-// FindMatches returns all occurences of the current search query for
-// the given row
-func (s *Search) FindMatches(line *[]rune, row int) []int {
-	var positions []int
+// Regexp returns the regular expression for the query.
+// `^` and `$` match at the beginning and the end of every line
+func (s Search) Regexp() (*regexp.Regexp, error) {
+	expr := s.expr()
 
-	query := s.Query
+	cache := s.cache
+	if cache != nil && cache.re != nil && cache.expr == expr && cache.exactWord == s.ExactWord {
+		return cache.re, nil
+	}
 
-	if query == "" {
-		return nil
+	re, err := regexp.Compile(expr)
+	if err == nil && cache != nil {
+		*cache = searchCache{expr: expr, exactWord: s.ExactWord, re: re}
 	}
 
-	lineStr := string(*line)
-	queryLen := len(query)
+	return re, err
+}
 
-	if s.IgnoreCase || s.ExactWord {
-		query = strings.ToLower(query)
-		lineStr = strings.ToLower(lineStr)
+// FindMatches returns all matches of the query within the given lines.
+// Returns nothing if the query is empty and an error if it's not a
+// valid regular expression
+func (s Search) FindMatches(lines [][]rune) ([]Match, error) {
+	if s.Query == "" {
+		return nil, nil
 	}
 
-	for i := 0; i <= len(lineStr)-len(query); i++ {
-		if lineStr[i:i+queryLen] == query {
-			if s.ExactWord {
-				line := *line
-				bef := clamp(i-1, 0, len(line))
-				aft := clamp(i+queryLen, 0, len(line))
+	re, err := s.Regexp()
+	if err != nil {
+		return nil, err
+	}
 
-				if len(line) <= aft {
-					continue
-				}
+	// the lines are searched as a whole so that
+	// matches can span line breaks
+	var text strings.Builder
+	rowStarts := make([]int, len(lines))
 
-				// if we need to look for the exact word check if there are any
-				// letters surrounding the search query and break if there are
-				if (bef > 0 && unicode.IsLetter(line[bef])) ||
-					unicode.IsLetter(line[aft]) {
+	for row, line := range lines {
+		if row > 0 {
+			text.WriteRune('\n')
+		}
+		rowStarts[row] = text.Len()
+		text.WriteString(string(line))
+	}
 
-					continue
-				}
-			}
+	str := text.String()
 
-			positions = append(positions, i)
+	// pos converts a byte offset of the text into a cursor position
+	pos := func(offset int) CursorPos {
+		row := sort.Search(len(rowStarts), func(i int) bool {
+			return rowStarts[i] > offset
+		}) - 1
+
+		return CursorPos{
+			Row:          row,
+			ColumnOffset: utf8.RuneCountInString(str[rowStarts[row]:offset]),
 		}
 	}
 
-	return positions
+	matches := []Match{}
+
+	for _, loc := range re.FindAllStringIndex(str, -1) {
+		match := Match{Start: pos(loc[0]), End: pos(loc[1])}
+
+		if s.ExactWord && !s.isWord(lines, match) {
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
 }
 
-// sortedRows returns a sorted slice of the s.Matches
-func (s Search) sortedRows(reverse bool) []int {
-	rows := make([]int, 0, len(s.Matches))
+// isWord returns whether the match is not surrounded by letters
+func (s Search) isWord(lines [][]rune, match Match) bool {
+	before := lines[match.Start.Row]
+	after := lines[match.End.Row]
 
-	for r := range s.Matches {
-		rows = append(rows, r)
+	if col := match.Start.ColumnOffset; col > 0 && unicode.IsLetter(before[col-1]) {
+		return false
 	}
 
-	sort.Ints(rows)
+	if col := match.End.ColumnOffset; col < len(after) && unicode.IsLetter(after[col]) {
+		return false
+	}
+
+	return true
+}
+
+// lineMatches returns the ranges of the matches within the part of
+// the line at `row` that starts at `offset` and is `length` runes long.
+// The ranges are relative to `offset`
+func (s Search) lineMatches(row int, offset int, length int) [][2]int {
+	ranges := [][2]int{}
+
+	// matches don't overlap so they're sorted by their end as well
+	first := sort.Search(len(s.Matches), func(i int) bool {
+		return s.Matches[i].End.Row >= row
+	})
+
+	for _, match := range s.Matches[first:] {
+		if match.Start.Row > row {
+			break
+		}
+
+		start, end := 0, length
+
+		if match.Start.Row == row {
+			start = clamp(match.Start.ColumnOffset-offset, 0, length)
+		}
+
+		if match.End.Row == row {
+			end = clamp(match.End.ColumnOffset-offset, 0, length)
+		}
 
-	if reverse {
-		// Reverse rows in place
-		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
-			rows[i], rows[j] = rows[j], rows[i]
+		if start < end {
+			ranges = append(ranges, [2]int{start, end})
 		}
 	}
 
-	return rows
+	return ranges
 }
 
 func (m *Model) isAnyMatchInViewport() bool {
@@ -171,8 +254,8 @@ func (m *Model) isAnyMatchInViewport() bool {
 	minimum := m.viewport.YOffset
 	maximum := minimum + m.viewport.Height()
 
-	for row := range m.Search.Matches {
-		if row >= minimum && row <= maximum {
+	for _, match := range m.Search.Matches {
+		if match.End.Row >= minimum && match.Start.Row <= maximum {
 			return true
 		}
 	}
@@ -193,16 +276,58 @@ func (m *Model) MoveToFirstSearchMatch() {
 	}
 }
 
-func (m *Model) FindNextMatch() {
-	if pos, ok := m.Search.FindMatch(m.CursorPos(), false); ok {
-		m.row = pos.Row
-		m.SetCursorColumn(pos.ColumnOffset)
+// UpdateSearchMatches searches the whole text for the current query.
+// The matches are cached until the query or the text changes.
+// Returns an error if the query is not a valid regular expression
+func (m *Model) UpdateSearchMatches() error {
+	if m.Search.cache == nil {
+		m.Search.cache = &searchCache{}
 	}
+
+	cache := m.Search.cache
+
+	if cache.searched &&
+		cache.version == m.version &&
+		cache.expr == m.Search.expr() &&
+		cache.exactWord == m.Search.ExactWord {
+
+		m.Search.Matches = cache.matches
+		return nil
+	}
+
+	matches, err := m.Search.FindMatches(m.value)
+	m.Search.Matches = matches
+
+	// compiling the regular expression has stored the query in the cache
+	if err == nil && m.Search.Query != "" {
+		cache.searched = true
+		cache.version = m.version
+		cache.matches = matches
+	}
+
+	return err
+}
+
+// FindNextMatch moves the cursor to the next match of the search.
+// Returns false if there is no match
+func (m *Model) FindNextMatch() bool {
+	return m.moveToMatch(false)
 }
 
-func (m *Model) FindPrevMatch() {
-	if pos, ok := m.Search.FindMatch(m.CursorPos(), true); ok {
+// FindPrevMatch moves the cursor to the previous match of the search.
+// Returns false if there is no match
+func (m *Model) FindPrevMatch() bool {
+	return m.moveToMatch(true)
+}
+
+func (m *Model) moveToMatch(prev bool) bool {
+	m.UpdateSearchMatches()
+
+	pos, ok := m.Search.FindMatch(m.AbsCursorPos(), prev)
+	if ok {
 		m.row = pos.Row
 		m.SetCursorColumn(pos.ColumnOffset)
 	}
+
+	return ok
 }
@@ -0,0 +1,168 @@
+package textarea
+
+import (
+	"testing"
+)
+
+func TestFindMatches(t *testing.T) {
+	lines := [][]rune{[]rune("Foo foo"), []rune("a.b axb"), []rune("food")}
+
+	tests := []struct {
+		name   string
+		search Search
+		want   []Match
+	}{
+		{
+			name:   "literal",
+			search: Search{Query: "a.b"},
+			want:   []Match{{CursorPos{Row: 1}, CursorPos{Row: 1, ColumnOffset: 3}}},
+		},
+		{
+			name:   "regex",
+			search: Search{Query: "a.b", Regex: true},
+			want: []Match{
+				{CursorPos{Row: 1}, CursorPos{Row: 1, ColumnOffset: 3}},
+				{CursorPos{Row: 1, ColumnOffset: 4}, CursorPos{Row: 1, ColumnOffset: 7}},
+			},
+		},
+		{
+			name:   "ignore case",
+			search: Search{Query: "foo ", IgnoreCase: true},
+			want:   []Match{{CursorPos{}, CursorPos{ColumnOffset: 4}}},
+		},
+		{
+			name:   "smart case",
+			search: Search{Query: "Foo", IgnoreCase: true, SmartCase: true},
+			want:   []Match{{CursorPos{}, CursorPos{ColumnOffset: 3}}},
+		},
+		{
+			name:   "smart case ignores escape sequences",
+			search: Search{Query: `o\S`, Regex: true, IgnoreCase: true, SmartCase: true},
+			want: []Match{
+				{CursorPos{ColumnOffset: 1}, CursorPos{ColumnOffset: 3}},
+				{CursorPos{ColumnOffset: 5}, CursorPos{ColumnOffset: 7}},
+				{CursorPos{Row: 2, ColumnOffset: 1}, CursorPos{Row: 2, ColumnOffset: 3}},
+			},
+		},
+		{
+			name:   "across lines",
+			search: Search{Query: `b\nf`, Regex: true},
+			want:   []Match{{CursorPos{Row: 1, ColumnOffset: 6}, CursorPos{Row: 2, ColumnOffset: 1}}},
+		},
+		{
+			name:   "exact word",
+			search: Search{Query: "foo", ExactWord: true},
+			want:   []Match{{CursorPos{ColumnOffset: 4}, CursorPos{ColumnOffset: 7}}},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.search.FindMatches(lines)
+		if err != nil {
+			t.Errorf("%s: FindMatches failed: %v", tt.name, err)
+			continue
+		}
+
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+				break
+			}
+		}
+	}
+}
+
+func TestFindMatch(t *testing.T) {
+	search := Search{Matches: []Match{
+		{Start: CursorPos{Row: 0, ColumnOffset: 2}},
+		{Start: CursorPos{Row: 2, ColumnOffset: 0}},
+	}}
+
+	tests := []struct {
+		current CursorPos
+		prev    bool
+		want    CursorPos
+	}{
+		{CursorPos{Row: 0, ColumnOffset: 2}, false, CursorPos{Row: 2}},
+		{CursorPos{Row: 2, ColumnOffset: 1}, false, CursorPos{ColumnOffset: 2}},
+		{CursorPos{Row: 1}, true, CursorPos{ColumnOffset: 2}},
+		{CursorPos{Row: 0, ColumnOffset: 1}, true, CursorPos{Row: 2}},
+	}
+
+	for _, tt := range tests {
+		if got, ok := search.FindMatch(tt.current, tt.prev); !ok || got != tt.want {
+			t.Errorf("%v (prev %v): expected %v, got %v", tt.current, tt.prev, tt.want, got)
+		}
+	}
+
+	if _, ok := (Search{}).FindMatch(CursorPos{}, false); ok {
+		t.Error("expected no match without matches")
+	}
+}
+
+func TestFindMatchesInvalidRegex(t *testing.T) {
+	lines := [][]rune{[]rune("(a)")}
+
+	if got, err := (Search{Query: "(", Regex: true}).FindMatches(lines); err == nil {
+		t.Errorf("expected an error for an invalid regex, got %v", got)
+	}
+
+	// the same query is fine when searching literally
+	got, err := Search{Query: "("}.FindMatches(lines)
+	if err != nil || len(got) != 1 {
+		t.Errorf("expected one literal match, got %v (%v)", got, err)
+	}
+}
+
+func TestUpdateSearchMatches(t *testing.T) {
+	ta := New()
+	ta.SetValue("foo\nbar foo")
+	ta.Search = Search{Query: "foo"}
+
+	if err := ta.UpdateSearchMatches(); err != nil || len(ta.Search.Matches) != 2 {
+		t.Fatalf("expected 2 matches, got %v (%v)", ta.Search.Matches, err)
+	}
+
+	// the text isn't searched again as long as it doesn't change
+	cached := ta.Search.Matches
+	ta.UpdateSearchMatches()
+
+	if &ta.Search.Matches[0] != &cached[0] {
+		t.Error("expected the cached matches to be reused")
+	}
+
+	ta.InsertString(" foo")
+	ta.UpdateSearchMatches()
+
+	if n := len(ta.Search.Matches); n != 3 {
+		t.Errorf("expected 3 matches after inserting text, got %d", n)
+	}
+
+	ta.DeleteRange(CursorPos{Row: 1}, CursorPos{Row: 1, ColumnOffset: 8})
+	ta.UpdateSearchMatches()
+
+	if n := len(ta.Search.Matches); n != 2 {
+		t.Errorf("expected 2 matches after deleting text, got %d", n)
+	}
+
+	// a new query is searched even if the text hasn't changed
+	ta.Search.Query = "bar"
+	ta.UpdateSearchMatches()
+
+	if n := len(ta.Search.Matches); n != 0 {
+		t.Errorf("expected no match for the deleted text, got %d", n)
+	}
+
+	// rendering doesn't search the text
+	ta.Search.Query = "foo"
+	ta.View()
+
+	if n := len(ta.Search.Matches); n != 0 {
+		t.Errorf("expected the matches to be left to UpdateSearchMatches, got %d", n)
+	}
+}
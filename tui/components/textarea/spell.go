@@ -0,0 +1,22 @@
+package textarea
+
+// misspelled returns the ranges of the misspelled words of a line
+// within the wrapped part of the line starting at offset
+func (m Model) misspelled(line []rune, offset int, length int) [][2]int {
+	if m.SpellCheck == nil {
+		return nil
+	}
+
+	ranges := [][2]int{}
+
+	for _, r := range m.SpellCheck(line) {
+		start := clamp(r[0]-offset, 0, length)
+		end := clamp(r[1]-offset, 0, length)
+
+		if start < end {
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+
+	return ranges
+}
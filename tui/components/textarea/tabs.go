@@ -0,0 +1,92 @@
+package textarea
+
+import (
+	"slices"
+)
+
+// defaultTabStop is the width of a tab if TabStop isn't set
+const defaultTabStop = 4
+
+// tabStop returns the number of columns a tab character is rendered with
+func (m Model) tabStop() int {
+	if m.TabStop > 0 {
+		return m.TabStop
+	}
+	return defaultTabStop
+}
+
+// ExpandTabs returns the line with each tab character replaced by
+// spaces up to the next multiple of tabStop
+func ExpandTabs(line []rune, tabStop int) []rune {
+	expanded := make([]rune, 0, len(line))
+
+	for _, r := range line {
+		if r != '\t' {
+			expanded = append(expanded, r)
+			continue
+		}
+
+		for range tabStop - len(expanded)%tabStop {
+			expanded = append(expanded, ' ')
+		}
+	}
+
+	return expanded
+}
+
+// IndentWidth returns the number of columns the leading white space of
+// the line takes up when tabs are expanded to the given tab stop
+func IndentWidth(line []rune, tabStop int) int {
+	width := 0
+
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += tabStop - width%tabStop
+		default:
+			return width
+		}
+	}
+
+	return width
+}
+
+// expandedCol returns the offset the rune at `col` has after
+// the tabs of the line have been expanded
+func expandedCol(line []rune, col int, tabStop int) int {
+	return len(ExpandTabs(line[:clamp(col, 0, len(line))], tabStop))
+}
+
+// expandTabs replaces the tab characters of the lines with spaces so that
+// they're rendered at the width of the tab stop. It's only called on the
+// copy of the model that's rendered. The cursor and the start of the
+// selection are moved to the columns they have after the expansion
+func (m *Model) expandTabs() {
+	if !slices.ContainsFunc(m.value, func(line []rune) bool {
+		return slices.Contains(line, '\t')
+	}) {
+		return
+	}
+
+	tabStop := m.tabStop()
+	value := make([][]rune, len(m.value))
+
+	for row, line := range m.value {
+		value[row] = line
+		if slices.Contains(line, '\t') {
+			value[row] = ExpandTabs(line, tabStop)
+		}
+	}
+
+	sel := &m.Selection
+
+	if sel.StartRow >= 0 && sel.StartRow < len(m.value) {
+		sel.StartCol = expandedCol(m.value[sel.StartRow], sel.StartCol, tabStop)
+	}
+
+	m.col = expandedCol(m.value[m.row], m.col, tabStop)
+	m.value = value
+	m.version++
+}
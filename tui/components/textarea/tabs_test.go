@@ -0,0 +1,36 @@
+package textarea
+
+import (
+	"testing"
+)
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		line     string
+		tabStop  int
+		expected string
+		indent   int
+	}{
+		{"a\tb\t\tc", 4, "a   b       c", 0},
+		{"\tfoo", 4, "    foo", 4},
+		{"  \tfoo", 4, "    foo", 4},
+		{"    \tfoo", 2, "      foo", 6},
+		{"foo", 8, "foo", 0},
+	}
+
+	for _, tt := range tests {
+		line := []rune(tt.line)
+
+		if got := string(ExpandTabs(line, tt.tabStop)); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.line, tt.expected, got)
+		}
+
+		if got := IndentWidth(line, tt.tabStop); got != tt.indent {
+			t.Errorf("%q: expected an indentation of %d, got %d", tt.line, tt.indent, got)
+		}
+	}
+
+	if got := expandedCol([]rune("a\tb"), 2, 4); got != 4 {
+		t.Errorf("expected b in column 4, got %d", got)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"image/color"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -245,7 +246,7 @@ type Model struct {
 	Err error
 
 	// General settings.
-	cache *memoization.MemoCache[line, [][]rune]
+	cache *memoization.MemoCache[wrapInput, [][]rune]
 
 	// Prompt is printed at the beginning of each line.
 	//
@@ -263,6 +264,44 @@ type Model struct {
 	// after the prompt.
 	ShowLineNumbers bool
 
+	// TabStop is the number of columns a tab character is rendered with.
+	// If 0 or less the default of 4 is used
+	TabStop int
+
+	// NoWrap turns off soft wrapping. Lines that are wider than the
+	// textarea are scrolled horizontally instead
+	NoWrap bool
+
+	// BreakIndent indents the continuation rows of soft wrapped lines
+	// by the indentation of the line, including the markers of list items
+	BreakIndent bool
+
+	// ShowBreak is displayed at the beginning of continuation rows
+	ShowBreak string
+
+	// SpellCheck returns the ranges of the misspelled words of a line,
+	// which are underlined. Spell checking is disabled if it's nil
+	SpellCheck func(line []rune) [][2]int
+
+	// Markdown enables the highlighting of the markdown syntax
+	// with the given HighlightStyles
+	Markdown        bool
+	HighlightStyles map[Highlight]lipgloss.Style
+
+	// highlightCache memoises the highlights of the lines,
+	// lineHighlights holds them while rendering and highlights
+	// those of the wrapped line that is rendered
+	highlightCache *memoization.MemoCache[highlightInput, highlightedLine]
+	lineHighlights [][]Highlight
+	highlights     []Highlight
+
+	// rules highlight the matches of user defined patterns. Like the
+	// markdown highlights their matches are memoised per line
+	rules     []HighlightRule
+	ruleCache *memoization.MemoCache[line, []int]
+	lineRules [][]int
+	ruleHits  []int
+
 	// EndOfBufferCharacter is displayed at the end of the input.
 	EndOfBufferCharacter rune
 
@@ -311,6 +350,18 @@ type Model struct {
 	// Underlying text value.
 	value [][]rune
 
+	// lineIDs identifies each line of value, so that a line can still be
+	// found after lines above it have been inserted, deleted or moved.
+	lineIDs []int
+
+	// nextLineID is the last id handed out to a line.
+	nextLineID int
+
+	// version is incremented whenever the value changes, so that
+	// results computed from the value like the search matches can be
+	// cached until the next change
+	version int
+
 	// focus indicates whether user input focus should be on this input
 	// component. When false, ignore keyboard input and hide the cursor.
 	focus bool
@@ -329,6 +380,10 @@ type Model struct {
 	// input.
 	viewport *viewport.Model
 
+	// leftCol is the first display column of the lines that is
+	// displayed if NoWrap is set
+	leftCol int
+
 	// rune sanitizer for input.
 	rsan runeutil.Sanitizer
 
@@ -351,7 +406,10 @@ func New() Model {
 		MaxWidth:             defaultMaxWidth,
 		Prompt:               lipgloss.ThickBorder().Left + " ",
 		Styles:               styles,
-		cache:                memoization.NewMemoCache[line, [][]rune](maxLines),
+		cache:                memoization.NewMemoCache[wrapInput, [][]rune](maxLines),
+		HighlightStyles:      DefaultHighlightStyles(),
+		highlightCache:       memoization.NewMemoCache[highlightInput, highlightedLine](maxLines),
+		ruleCache:            memoization.NewMemoCache[line, []int](maxLines),
 		EndOfBufferCharacter: ' ',
 		ShowLineNumbers:      true,
 		VirtualCursor:        true,
@@ -514,31 +572,15 @@ func (m *Model) insertRunesFromUserInput(runes []rune) {
 	m.col += len(lines[0])
 
 	if numExtraLines := len(lines) - 1; numExtraLines > 0 {
-		// Add the new lines.
-		// We try to reuse the slice if there's already space.
-		var newGrid [][]rune
-		if cap(m.value) >= len(m.value)+numExtraLines {
-			// Can reuse the extra space.
-			newGrid = m.value[:len(m.value)+numExtraLines]
-		} else {
-			// No space left; need a new slice.
-			newGrid = make([][]rune, len(m.value)+numExtraLines)
-			copy(newGrid, m.value[:m.row+1])
-		}
-		// Add all the rows that were after the cursor in the original
-		// grid at the end of the new grid.
-		copy(newGrid[m.row+1+numExtraLines:], m.value[m.row+1:])
-		m.value = newGrid
-		// Insert all the new lines in the middle.
-		for _, l := range lines[1:] {
-			m.row++
-			m.value[m.row] = l
-			m.col = len(l)
-		}
+		// Insert all the new lines below the cursor.
+		m.spliceLines(m.row+1, m.row+1, lines[1:], nil)
+		m.row += numExtraLines
+		m.col = len(lines[numExtraLines])
 	}
 
 	// Finally add the tail at the end of the last line inserted.
 	m.value[m.row] = append(m.value[m.row], tail...)
+	m.version++
 
 	m.SetCursorColumn(m.col)
 }
@@ -578,59 +620,60 @@ func (m Model) Line() int {
 	return m.row
 }
 
-// CursorDown moves the cursor down by one line.
-// Returns whether or not the cursor blink should be reset.
+// CursorDown moves the cursor down by one row, which is the next soft
+// wrapped row of the line or the first row of the next line.
+// The cursor keeps its column on the screen as far as possible
 func (m *Model) CursorDown() {
 	li := m.LineInfo()
-	charOffset := max(m.lastCharOffset, li.CharOffset)
+	charOffset := max(m.lastCharOffset, m.screenOffset(li))
 	m.lastCharOffset = charOffset
 
-	if li.RowOffset+1 >= li.Height && m.row < len(m.value)-1 {
+	switch {
+	case li.RowOffset+1 < li.Height:
+		// the end of a row is the start of the next row
+		m.col = li.StartColumn + li.Width
+	case m.row < len(m.value)-1:
 		m.row++
 		m.col = 0
-	} else {
-		// Move the cursor to the start of the next line so that we can get
-		// the line information. We need to add 2 columns to account for the
-		// trailing space wrapping.
-		const trailingSpace = 2
-		m.col = min(li.StartColumn+li.Width+trailingSpace, len(m.value[m.row])-1)
+	default:
+		m.col = li.StartColumn
 	}
 
-	nli := m.LineInfo()
-	m.col = nli.StartColumn
-
-	if nli.Width <= 0 {
-		return
-	}
-
-	offset := 0
-	for offset < charOffset {
-		if m.row >= len(m.value) || m.col >= len(m.value[m.row]) || offset >= nli.CharWidth-1 {
-			break
-		}
-		offset += rw.RuneWidth(m.value[m.row][m.col])
-		m.col++
-	}
+	m.moveToScreenOffset(charOffset)
 }
 
-// CursorUp moves the cursor up by one line.
+// CursorUp moves the cursor up by one row, which is the previous soft
+// wrapped row of the line or the last row of the previous line.
+// The cursor keeps its column on the screen as far as possible
 func (m *Model) CursorUp() {
 	li := m.LineInfo()
-	charOffset := max(m.lastCharOffset, li.CharOffset)
+	charOffset := max(m.lastCharOffset, m.screenOffset(li))
 	m.lastCharOffset = charOffset
 
-	if li.RowOffset <= 0 && m.row > 0 {
+	switch {
+	case li.RowOffset > 0:
+		// the last rune of the previous row
+		m.col = li.StartColumn - 1
+	case m.row > 0:
 		m.row--
 		m.col = len(m.value[m.row])
-	} else {
-		// Move the cursor to the end of the previous line.
-		// This can be done by moving the cursor to the start of the line and
-		// then subtracting 2 to account for the trailing space we keep on
-		// soft-wrapped lines.
-		const trailingSpace = 2
-		m.col = li.StartColumn - trailingSpace
+	default:
+		m.col = li.StartColumn
 	}
 
+	m.moveToScreenOffset(charOffset)
+}
+
+// screenOffset returns the number of columns the cursor is offset from the
+// start of the row on the screen, including the break prefix
+func (m Model) screenOffset(li LineInfo) int {
+	return li.CharOffset + m.continuationWidth(m.value[m.row], li.RowOffset)
+}
+
+// moveToScreenOffset moves the cursor within its row to the rune that is
+// displayed at the given offset from the start of the row. The cursor stays
+// in front of the break prefix and before the end of the row
+func (m *Model) moveToScreenOffset(screenOffset int) {
 	nli := m.LineInfo()
 	m.col = nli.StartColumn
 
@@ -638,12 +681,16 @@ func (m *Model) CursorUp() {
 		return
 	}
 
-	offset := 0
-	for offset < charOffset {
-		if m.col >= len(m.value[m.row]) || offset >= nli.CharWidth-1 {
+	line := m.value[m.row]
+	offset := m.continuationWidth(line, nli.RowOffset)
+	end := min(nli.StartColumn+nli.Width-1, len(line))
+
+	for m.col < end {
+		w := rw.RuneWidth(line[m.col])
+		if offset+w > screenOffset {
 			break
 		}
-		offset += rw.RuneWidth(m.value[m.row][m.col])
+		offset += w
 		m.col++
 	}
 }
@@ -698,6 +745,8 @@ func (m *Model) Blur() {
 // Reset sets the input to its default state with no input.
 func (m *Model) Reset() {
 	m.value = make([][]rune, minHeight, maxLines)
+	m.lineIDs = m.newLineIDs(minHeight)
+	m.version++
 	m.col = 0
 	m.row = 0
 	m.viewport.GotoTop()
@@ -707,9 +756,8 @@ func (m *Model) Reset() {
 // san initializes or retrieves the rune sanitizer.
 func (m *Model) san() runeutil.Sanitizer {
 	if m.rsan == nil {
-		// Textinput has all its input on a single line so collapse
-		// newlines/tabs to single spaces.
-		m.rsan = runeutil.NewSanitizer()
+		// Tabs are kept and expanded when the text is rendered
+		m.rsan = runeutil.NewSanitizer(runeutil.ReplaceTabs("\t"))
 	}
 	return m.rsan
 }
@@ -718,6 +766,7 @@ func (m *Model) san() runeutil.Sanitizer {
 // not the cursor blink should be reset.
 func (m *Model) deleteBeforeCursor() {
 	m.value[m.row] = m.value[m.row][m.col:]
+	m.version++
 	m.SetCursorColumn(0)
 }
 
@@ -726,6 +775,7 @@ func (m *Model) deleteBeforeCursor() {
 // the cursor so as not to reveal word breaks in the masked input.
 func (m *Model) deleteAfterCursor() {
 	m.value[m.row] = m.value[m.row][:m.col]
+	m.version++
 	m.SetCursorColumn(len(m.value[m.row]))
 }
 
@@ -741,6 +791,7 @@ func (m *Model) transposeLeft() {
 		m.SetCursorColumn(m.col - 1)
 	}
 	m.value[m.row][m.col-1], m.value[m.row][m.col] = m.value[m.row][m.col], m.value[m.row][m.col-1]
+	m.version++
 	if m.col < len(m.value[m.row]) {
 		m.SetCursorColumn(m.col + 1)
 	}
@@ -784,6 +835,7 @@ func (m *Model) deleteWordLeft() {
 	} else {
 		m.value[m.row] = append(m.value[m.row][:m.col], m.value[m.row][oldCol:]...)
 	}
+	m.version++
 }
 
 // deleteWordRight deletes the word right to the cursor.
@@ -812,6 +864,7 @@ func (m *Model) deleteWordRight() {
 	} else {
 		m.value[m.row] = append(m.value[m.row][:oldCol], m.value[m.row][m.col:]...)
 	}
+	m.version++
 
 	m.SetCursorColumn(oldCol)
 }
@@ -893,6 +946,7 @@ func (m *Model) doWordRight(fn func(charIdx int, pos int)) {
 
 // uppercaseRight changes the word to the right to uppercase.
 func (m *Model) uppercaseRight() {
+	m.version++
 	m.doWordRight(func(_ int, i int) {
 		m.value[m.row][i] = unicode.ToUpper(m.value[m.row][i])
 	})
@@ -900,6 +954,7 @@ func (m *Model) uppercaseRight() {
 
 // lowercaseRight changes the word to the right to lowercase.
 func (m *Model) lowercaseRight() {
+	m.version++
 	m.doWordRight(func(_ int, i int) {
 		m.value[m.row][i] = unicode.ToLower(m.value[m.row][i])
 	})
@@ -907,6 +962,7 @@ func (m *Model) lowercaseRight() {
 
 // capitalizeRight changes the word to the right to title case.
 func (m *Model) capitalizeRight() {
+	m.version++
 	m.doWordRight(func(charIdx int, i int) {
 		if charIdx == 0 {
 			m.value[m.row][i] = unicode.ToTitle(m.value[m.row][i])
@@ -938,7 +994,7 @@ func (m Model) LineInfo() LineInfo {
 				RowOffset:    i + 1,
 				StartColumn:  m.col,
 				Width:        len(grid[i+1]),
-				CharWidth:    uniseg.StringWidth(string(line)),
+				CharWidth:    uniseg.StringWidth(string(grid[i+1])),
 			}
 		}
 
@@ -962,6 +1018,8 @@ func (m Model) LineInfo() LineInfo {
 // repositionView repositions the view of the viewport based on the defined
 // scrolling behavior.
 func (m *Model) repositionView() {
+	m.scrollHorizontally()
+
 	minimum := m.viewport.YOffset
 	maximum := minimum + m.viewport.Height() - 1
 	if row := m.cursorLineNumber(); row < minimum {
@@ -1083,7 +1141,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	}
 
 	if m.MaxHeight > 0 && m.MaxHeight != m.cache.Capacity() {
-		m.cache = memoization.NewMemoCache[line, [][]rune](m.MaxHeight)
+		m.cache = memoization.NewMemoCache[wrapInput, [][]rune](m.MaxHeight)
 	}
 
 	switch msg := msg.(type) {
@@ -1113,6 +1171,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 			if len(m.value[m.row]) > 0 {
 				m.value[m.row] = append(m.value[m.row][:max(0, m.col-1)], m.value[m.row][m.col:]...)
+				m.version++
 				if m.col > 0 {
 					m.SetCursorColumn(m.col - 1)
 				}
@@ -1120,6 +1179,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case key.Matches(msg, m.KeyMap.DeleteCharacterForward):
 			if len(m.value[m.row]) > 0 && m.col < len(m.value[m.row]) {
 				m.value[m.row] = append(m.value[m.row][:m.col], m.value[m.row][m.col+1:]...)
+				m.version++
 			}
 			if m.col >= len(m.value[m.row]) {
 				m.mergeLineBelow(m.row)
@@ -1205,6 +1265,8 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 // View renders the text area in its current state.
 func (m Model) View() string {
+	m.expandTabs()
+	m.scrollHorizontally()
 	m.updateVirtualCursorStyle()
 	if m.Value() == "" && m.row == 0 && m.col == 0 && m.Placeholder != "" {
 		return m.placeholderView()
@@ -1220,6 +1282,8 @@ func (m Model) View() string {
 		styles = m.activeStyle()
 	)
 
+	m.updateHighlights()
+
 	displayLine := 0
 	for l, line := range m.value {
 		wrappedLines := m.memoizedWrap(line, m.width)
@@ -1230,6 +1294,12 @@ func (m Model) View() string {
 			style = styles.computedText()
 		}
 
+		offset := 0
+		prefix := ""
+		if len(wrappedLines) > 1 {
+			prefix = m.breakPrefix(line, m.width)
+		}
+
 		for wl, wrappedLine := range wrappedLines {
 			prompt := m.promptView(displayLine)
 			prompt = styles.computedPrompt().Render(prompt)
@@ -1253,40 +1323,48 @@ func (m Model) View() string {
 				widestLineNumber = lnw
 			}
 
-			strwidth := uniseg.StringWidth(string(wrappedLine))
-			padding := m.width - strwidth
-			// If the trailing space causes the line to be wider than the
-			// width, we should not draw it to the screen since it will result
-			// in an extra space at the end of the line which can look off when
-			// the cursor line is showing.
-			if strwidth > m.width {
-				// The character causing the line to be wider than the width is
-				// guaranteed to be a space since any other character would
-				// have been wrapped.
-				wrappedLine = []rune(strings.TrimSuffix(string(wrappedLine), " "))
-				padding -= m.width - strwidth
+			width := m.width
+			if wl > 0 {
+				s.WriteString(m.breakView(prefix, m.row == l, &style))
+				width -= uniseg.StringWidth(prefix)
+			}
+
+			// the next row starts after the whole wrapped line
+			// even if only a part of it is displayed
+			next := offset + len(wrappedLine)
+
+			if m.NoWrap {
+				start := m.visibleStart(wrappedLine)
+				wrappedLine = wrappedLine[start:]
+				offset += start
 			}
 
+			// Blanks at the end of a row and the extra space of the
+			// last row may exceed the width and aren't displayed.
+			wrappedLine = fitWidth(wrappedLine, width)
+			padding := width - uniseg.StringWidth(string(wrappedLine))
+
 			// --- visual selection
 			// ---- NEEDS TO BE MERGED WHEN UPDATING BUBBLES!
-			m.Selection.wrappedLline = wrappedLine
-			m.Selection.lineIndex = l
-			m.Selection.CurrentCursorPos = m.CursorPos()
-			selection := m.SelectionContent()
+			m.setWrappedHighlights(l, offset, len(wrappedLine))
 
-			if selection.Content != "" {
-				m.RenderSelection(&selection, &line, &wrappedLine, l, wl, &s, &style)
+			if m.Selection.Mode == SelectVisualBlock {
+				m.RenderBlockSelection(&line, &wrappedLine, l, offset, &s, &style)
+			} else if selStart, selEnd, ok := m.selectedCols(l); ok {
+				m.RenderSelection(selStart, selEnd, &wrappedLine, l, offset, &s, &style)
 			} else {
-				matches := m.Search.FindMatches(&wrappedLine, l)
+				matches := m.Search.lineMatches(l, offset, len(wrappedLine))
+				misspelled := m.misspelled(line, offset, len(wrappedLine))
 
-				if len(matches) == 0 {
-					delete(m.Search.Matches, l)
-					m.RenderLine(&line, &wrappedLine, l, wl, &s, &style)
+				if len(matches) != 0 {
+					m.RenderMultiSelection(matches, &wrappedLine, l, offset, &s, &style)
+				} else if len(misspelled) != 0 {
+					m.RenderMisspelled(misspelled, &wrappedLine, l, offset, &s, &style)
 				} else {
-					m.RenderMultiSelection(&matches, &wrappedLine, l, wl, &s, &style)
-					m.Search.Matches[l] = matches
+					m.RenderLine(&wrappedLine, l, offset, &s, &style)
 				}
 			}
+			offset = next
 			// --- MERGE END
 			s.WriteString(style.Render(strings.Repeat(" ", max(0, padding))))
 			s.WriteRune('\n')
@@ -1370,6 +1448,23 @@ func (m Model) lineNumberView(n int, isCursorLine bool) (str string) {
 	return textStyle.Render(lineNumberStyle.Render(str))
 }
 
+// breakView renders the break prefix of a continuation row. ShowBreak
+// is styled like the line numbers
+func (m Model) breakView(prefix string, isCursorLine bool, style *lipgloss.Style) string {
+	if prefix == "" {
+		return ""
+	}
+
+	indent := strings.TrimSuffix(prefix, m.ShowBreak)
+
+	lineNumberStyle := m.activeStyle().computedLineNumber()
+	if isCursorLine {
+		lineNumberStyle = m.activeStyle().computedCursorLineNumber()
+	}
+
+	return style.Render(indent) + style.Render(lineNumberStyle.Render(m.ShowBreak))
+}
+
 // placeholderView returns the prompt and placeholder, if any.
 func (m Model) placeholderView() string {
 	var (
@@ -1472,16 +1567,29 @@ func (m Model) Cursor() *tea.Cursor {
 		return nil
 	}
 
+	xOffset, yOffset := m.CursorOffset()
+
+	c := tea.NewCursor(xOffset, yOffset)
+	c.Blink = m.Styles.Cursor.Blink
+	c.Color = m.Styles.Cursor.Color
+	c.Shape = m.Styles.Cursor.Shape
+	return c
+}
+
+// CursorOffset returns the position of the cursor relative
+// to the top left corner of the textarea
+func (m Model) CursorOffset() (int, int) {
 	lineInfo := m.LineInfo()
 	w := lipgloss.Width
 	baseStyle := m.activeStyle().Base
 
-	xOffset := lineInfo.CharOffset +
+	xOffset := m.screenOffset(lineInfo) +
 		w(m.promptView(0)) +
 		w(m.lineNumberView(0, false)) +
 		baseStyle.GetMarginLeft() +
 		baseStyle.GetPaddingLeft() +
-		baseStyle.GetBorderLeftSize()
+		baseStyle.GetBorderLeftSize() -
+		m.leftCol
 
 	yOffset := m.cursorLineNumber() -
 		m.viewport.YOffset +
@@ -1489,21 +1597,7 @@ func (m Model) Cursor() *tea.Cursor {
 		baseStyle.GetPaddingTop() +
 		baseStyle.GetBorderTopSize()
 
-	c := tea.NewCursor(xOffset, yOffset)
-	c.Blink = m.Styles.Cursor.Blink
-	c.Color = m.Styles.Cursor.Color
-	c.Shape = m.Styles.Cursor.Shape
-	return c
-}
-
-func (m Model) memoizedWrap(runes []rune, width int) [][]rune {
-	input := line{runes: runes, width: width}
-	if v, ok := m.cache.Get(input); ok {
-		return v
-	}
-	v := wrap(runes, width)
-	m.cache.Set(input, v)
-	return v
+	return xOffset, yOffset
 }
 
 // cursorLineNumber returns the line number that the cursor is on.
@@ -1528,15 +1622,8 @@ func (m *Model) mergeLineBelow(row int) {
 	// To perform a merge, we will need to combine the two lines and then
 	m.value[row] = append(m.value[row], m.value[row+1]...)
 
-	// Shift all lines up by one
-	for i := row + 1; i < len(m.value)-1; i++ {
-		m.value[i] = m.value[i+1]
-	}
-
-	// And, remove the last line
-	if len(m.value) > 0 {
-		m.value = m.value[:len(m.value)-1]
-	}
+	// remove the line below
+	m.spliceLines(row+1, row+2, nil, nil)
 }
 
 // mergeLineAbove merges the current line the cursor is on with the line above.
@@ -1551,15 +1638,8 @@ func (m *Model) mergeLineAbove(row int) {
 	// To perform a merge, we will need to combine the two lines and then
 	m.value[row-1] = append(m.value[row-1], m.value[row]...)
 
-	// Shift all lines up by one
-	for i := row; i < len(m.value)-1; i++ {
-		m.value[i] = m.value[i+1]
-	}
-
-	// And, remove the last line
-	if len(m.value) > 0 {
-		m.value = m.value[:len(m.value)-1]
-	}
+	// remove the merged line
+	m.spliceLines(row, row+1, nil, nil)
 }
 
 func (m *Model) splitLine(row, col int) {
@@ -1570,91 +1650,50 @@ func (m *Model) splitLine(row, col int) {
 	tail := make([]rune, len(tailSrc))
 	copy(tail, tailSrc)
 
-	m.value = append(m.value[:row+1], m.value[row:]...)
-
 	m.value[row] = head
-	m.value[row+1] = tail
+	m.spliceLines(row+1, row+1, [][]rune{tail}, nil)
 
 	m.col = 0
 	m.row++
 }
 
-// Paste is a command for pasting from the clipboard into the text input.
-func Paste() tea.Msg {
-	str, err := clipboard.ReadAll()
-	if err != nil {
-		return pasteErrMsg{err}
+// spliceLines replaces the lines from `start` up to but not including `end`
+// with the given lines. The inserted lines get the given ids, or new ones
+// if ids is nil.
+func (m *Model) spliceLines(start, end int, lines [][]rune, ids []int) {
+	m.syncLineIDs()
+	if ids == nil {
+		ids = m.newLineIDs(len(lines))
 	}
-	return pasteMsg(str)
+	m.value = slices.Replace(m.value, start, end, lines...)
+	m.version++
+	m.lineIDs = slices.Replace(m.lineIDs, start, end, ids...)
 }
 
-func wrap(runes []rune, width int) [][]rune {
-	var (
-		lines  = [][]rune{{}}
-		word   = []rune{}
-		row    int
-		spaces int
-	)
-
-	// Word wrap the runes
-	for _, r := range runes {
-		if unicode.IsSpace(r) {
-			spaces++
-		} else {
-			word = append(word, r)
-		}
-
-		if spaces > 0 { //nolint:nestif
-			if uniseg.StringWidth(string(lines[row]))+uniseg.StringWidth(string(word))+spaces > width {
-				row++
-				lines = append(lines, []rune{})
-				lines[row] = append(lines[row], word...)
-				lines[row] = append(lines[row], repeatSpaces(spaces)...)
-				spaces = 0
-				word = nil
-			} else {
-				lines[row] = append(lines[row], word...)
-				lines[row] = append(lines[row], repeatSpaces(spaces)...)
-				spaces = 0
-				word = nil
-			}
-		} else {
-			// If the last character is a double-width rune, then we may not be able to add it to this line
-			// as it might cause us to go past the width.
-			lastCharLen := rw.RuneWidth(word[len(word)-1])
-			if uniseg.StringWidth(string(word))+lastCharLen > width {
-				// If the current line has any content, let's move to the next
-				// line because the current word fills up the entire line.
-				if len(lines[row]) > 0 {
-					row++
-					lines = append(lines, []rune{})
-				}
-				lines[row] = append(lines[row], word...)
-				word = nil
-			}
-		}
+// syncLineIDs gives every line a new id if the ids no longer match the lines
+func (m *Model) syncLineIDs() {
+	if len(m.lineIDs) != len(m.value) {
+		m.lineIDs = m.newLineIDs(len(m.value))
 	}
+}
 
-	if uniseg.StringWidth(string(lines[row]))+uniseg.StringWidth(string(word))+spaces >= width {
-		lines = append(lines, []rune{})
-		lines[row+1] = append(lines[row+1], word...)
-		// We add an extra space at the end of the line to account for the
-		// trailing space at the end of the previous soft-wrapped lines so that
-		// behaviour when navigating is consistent and so that we don't need to
-		// continually add edges to handle the last line of the wrapped input.
-		spaces++
-		lines[row+1] = append(lines[row+1], repeatSpaces(spaces)...)
-	} else {
-		lines[row] = append(lines[row], word...)
-		spaces++
-		lines[row] = append(lines[row], repeatSpaces(spaces)...)
+// newLineIDs returns n ids that haven't been used before
+func (m *Model) newLineIDs(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		m.nextLineID++
+		ids[i] = m.nextLineID
 	}
-
-	return lines
+	return ids
 }
 
-func repeatSpaces(n int) []rune {
-	return []rune(strings.Repeat(string(' '), n))
+// Paste is a command for pasting from the clipboard into the text input.
+func Paste() tea.Msg {
+	str, err := clipboard.ReadAll()
+	if err != nil {
+		return pasteErrMsg{err}
+	}
+	return pasteMsg(str)
 }
 
 // numDigits returns the number of digits in an integer.
@@ -0,0 +1,412 @@
+package textarea
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// selectRange selects the text from `start` up to and including `end`
+// and moves the cursor to `end`.
+// Column offsets are relative to the beginning of the whole line
+func (m *Model) selectRange(mode SelectionMode, start CursorPos, end CursorPos) {
+	m.Selection.Mode = mode
+	m.Selection.StartRow = start.Row
+	m.Selection.StartCol = start.ColumnOffset
+
+	m.row = end.Row
+	m.SetCursorColumn(end.ColumnOffset)
+}
+
+// isBlankLine returns whether the line at `row` contains nothing but white space
+func (m *Model) isBlankLine(row int) bool {
+	return strings.TrimSpace(string(m.value[row])) == ""
+}
+
+// runeAt returns the rune at the given position.
+// Positions at the end of a line return a line break
+func (m *Model) runeAt(pos CursorPos) rune {
+	line := m.value[pos.Row]
+	if pos.ColumnOffset >= len(line) {
+		return '\n'
+	}
+	return line[pos.ColumnOffset]
+}
+
+// nextPos returns the position after `pos`. The end of a line counts
+// as a position of its own. Returns false at the end of the buffer
+func (m *Model) nextPos(pos CursorPos) (CursorPos, bool) {
+	if pos.ColumnOffset < len(m.value[pos.Row]) {
+		pos.ColumnOffset++
+		return pos, true
+	}
+
+	if pos.Row+1 >= len(m.value) {
+		return pos, false
+	}
+
+	return CursorPos{Row: pos.Row + 1}, true
+}
+
+// prevPos returns the position before `pos`. The end of a line counts
+// as a position of its own. Returns false at the beginning of the buffer
+func (m *Model) prevPos(pos CursorPos) (CursorPos, bool) {
+	if pos.ColumnOffset > 0 {
+		pos.ColumnOffset--
+		return pos, true
+	}
+
+	if pos.Row == 0 {
+		return pos, false
+	}
+
+	return CursorPos{
+		Row:          pos.Row - 1,
+		ColumnOffset: len(m.value[pos.Row-1]),
+	}, true
+}
+
+// lastCharPos returns the position of the last character before `pos`
+// skipping line ends, so that it can be used as the inclusive end of
+// a selection
+func (m *Model) lastCharPos(pos CursorPos) CursorPos {
+	prev, ok := m.prevPos(pos)
+	for ok && prev.ColumnOffset >= len(m.value[prev.Row]) && len(m.value[prev.Row]) > 0 {
+		prev, ok = m.prevPos(prev)
+	}
+	return prev
+}
+
+// SelectParagraph selects the paragraph the cursor is in.
+// Paragraphs are separated by blank lines and blank lines between
+// paragraphs count as a paragraph of their own.
+// If outer is true the blank lines after the paragraph are selected
+// as well or, if there are none, the blank lines before it.
+// Returns false if there's nothing to select
+func (m *Model) SelectParagraph(outer bool) bool {
+	blank := m.isBlankLine(m.row)
+	start, end := m.row, m.row
+
+	for start > 0 && m.isBlankLine(start-1) == blank {
+		start--
+	}
+
+	for end < len(m.value)-1 && m.isBlankLine(end+1) == blank {
+		end++
+	}
+
+	if outer {
+		if blank {
+			// blank lines are selected with the following paragraph
+			for end < len(m.value)-1 && !m.isBlankLine(end+1) {
+				end++
+			}
+		} else {
+			next := end
+			for next < len(m.value)-1 && m.isBlankLine(next+1) {
+				next++
+			}
+
+			// select the blank lines before the paragraph
+			// if there are none after it
+			if next == end {
+				for start > 0 && m.isBlankLine(start-1) {
+					start--
+				}
+			}
+
+			end = next
+		}
+	}
+
+	m.selectRange(
+		SelectVisualLine,
+		CursorPos{Row: start},
+		CursorPos{Row: end},
+	)
+
+	return true
+}
+
+// isSentenceEnd returns whether the rune at `col` of the given line
+// ends a sentence, which is a `.`, `!` or `?` followed by any closing
+// brackets or quotes and white space or the end of the line
+func isSentenceEnd(line []rune, col int) bool {
+	if !slices.Contains([]rune{'.', '!', '?'}, line[col]) {
+		return false
+	}
+
+	col++
+	for col < len(line) && slices.Contains([]rune{')', ']', '"', '\''}, line[col]) {
+		col++
+	}
+
+	return col >= len(line) || unicode.IsSpace(line[col])
+}
+
+// SelectSentence selects the sentence the cursor is in.
+// Sentences may span multiple lines but don't cross blank lines.
+// If outer is true the white space after the sentence is selected
+// as well or, if there is none, the white space before it.
+// If the cursor is on the white space between two sentences only the
+// white space is selected, or with outer, the white space and the
+// following sentence.
+// Returns false if there's nothing to select
+func (m *Model) SelectSentence(outer bool) bool {
+	if m.isBlankLine(m.row) {
+		return m.SelectParagraph(outer)
+	}
+
+	// the text of the paragraph with line breaks as spaces and
+	// the position of every rune within the buffer
+	first, last := m.row, m.row
+	for first > 0 && !m.isBlankLine(first-1) {
+		first--
+	}
+	for last < len(m.value)-1 && !m.isBlankLine(last+1) {
+		last++
+	}
+
+	text := []rune{}
+	positions := []CursorPos{}
+	cursor := 0
+
+	for row := first; row <= last; row++ {
+		for col, r := range m.value[row] {
+			if row == m.row && col == min(m.col, len(m.value[row])-1) {
+				cursor = len(text)
+			}
+			text = append(text, r)
+			positions = append(positions, CursorPos{Row: row, ColumnOffset: col})
+		}
+
+		if row < last {
+			text = append(text, ' ')
+			positions = append(positions, CursorPos{Row: row, ColumnOffset: len(m.value[row])})
+		}
+	}
+
+	// split the text into alternating runs of sentences and white space
+	type span struct {
+		start, end int
+		space      bool
+	}
+
+	spans := []span{}
+	for i := 0; i < len(text); {
+		j := i
+		space := unicode.IsSpace(text[i])
+
+		if space {
+			for j < len(text) && unicode.IsSpace(text[j]) {
+				j++
+			}
+		} else {
+			for j < len(text) && !isSentenceEnd(text, j) {
+				j++
+			}
+			// include the closing brackets and quotes after the punctuation
+			for j+1 < len(text) && !unicode.IsSpace(text[j+1]) {
+				j++
+			}
+			j = min(j+1, len(text))
+		}
+
+		spans = append(spans, span{i, j, space})
+		i = j
+	}
+
+	current := 0
+	for i, s := range spans {
+		if cursor >= s.start && cursor < s.end {
+			current = i
+		}
+	}
+
+	start, end := spans[current].start, spans[current].end
+
+	if outer {
+		switch {
+		case current+1 < len(spans):
+			end = spans[current+1].end
+		case current > 0 && spans[current-1].space:
+			start = spans[current-1].start
+		}
+	}
+
+	// line breaks at the end are not part of the selection
+	for end > start+1 && positions[end-1].ColumnOffset >= len(m.value[positions[end-1].Row]) {
+		end--
+	}
+
+	m.selectRange(SelectVisual, positions[start], positions[end-1])
+	return true
+}
+
+// quotePositions returns the offsets of all unescaped quotes in the line
+func quotePositions(line []rune, quote rune) []int {
+	positions := []int{}
+
+	for i, r := range line {
+		if r == quote && (i == 0 || line[i-1] != '\\') {
+			positions = append(positions, i)
+		}
+	}
+
+	return positions
+}
+
+// SelectQuoted selects the text between the quotes the cursor is in
+// or, if the cursor isn't within quotes, the next quoted text of the line.
+// Quotes are paired from the beginning of the line and quotes escaped
+// with a backslash are ignored.
+// If outer is true the quotes and the white space after them are
+// selected as well or, if there is none, the white space before them.
+// Returns false if there's nothing to select
+func (m *Model) SelectQuoted(quote rune, outer bool) bool {
+	line := m.value[m.row]
+	quotes := quotePositions(line, quote)
+
+	open, closing := -1, -1
+
+	for i := 0; i+1 < len(quotes); i += 2 {
+		if quotes[i+1] >= m.col {
+			open, closing = quotes[i], quotes[i+1]
+			break
+		}
+	}
+
+	if open < 0 {
+		return false
+	}
+
+	start, end := open+1, closing-1
+
+	if outer {
+		start, end = open, closing
+
+		switch {
+		case end+1 < len(line) && unicode.IsSpace(line[end+1]):
+			for end+1 < len(line) && unicode.IsSpace(line[end+1]) {
+				end++
+			}
+		default:
+			for start > 0 && unicode.IsSpace(line[start-1]) {
+				start--
+			}
+		}
+	}
+
+	if start > end {
+		return false
+	}
+
+	m.selectRange(
+		SelectVisual,
+		CursorPos{Row: m.row, ColumnOffset: start},
+		CursorPos{Row: m.row, ColumnOffset: end},
+	)
+
+	return true
+}
+
+// findOpenBracket returns the position of the unmatched open bracket
+// before or at `pos`
+func (m *Model) findOpenBracket(pos CursorPos, open rune, closing rune) (CursorPos, bool) {
+	depth := 0
+
+	// a closing bracket at the cursor belongs to the block we're looking for
+	if m.runeAt(pos) == closing {
+		depth--
+	}
+
+	for ok := true; ok; pos, ok = m.prevPos(pos) {
+		switch m.runeAt(pos) {
+		case closing:
+			depth++
+		case open:
+			if depth == 0 {
+				return pos, true
+			}
+			depth--
+		}
+	}
+
+	return pos, false
+}
+
+// findClosingBracket returns the position of the bracket that closes
+// the open bracket at `pos`
+func (m *Model) findClosingBracket(pos CursorPos, open rune, closing rune) (CursorPos, bool) {
+	depth := 0
+
+	for ok := true; ok; pos, ok = m.nextPos(pos) {
+		switch m.runeAt(pos) {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return pos, true
+			}
+		}
+	}
+
+	return pos, false
+}
+
+// SelectBrackets selects the text between the given brackets the
+// cursor is in. Nested brackets are skipped and the brackets may be on
+// different lines.
+// If outer is true the brackets are selected as well.
+// If the brackets are on lines of their own, the lines between them are
+// selected linewise.
+// Returns false if there's nothing to select
+func (m *Model) SelectBrackets(open rune, closing rune, outer bool) bool {
+	cursor := CursorPos{
+		Row:          m.row,
+		ColumnOffset: min(m.col, max(len(m.value[m.row])-1, 0)),
+	}
+
+	start, ok := m.findOpenBracket(cursor, open, closing)
+	if !ok {
+		return false
+	}
+
+	end, ok := m.findClosingBracket(start, open, closing)
+	if !ok {
+		return false
+	}
+
+	if outer {
+		m.selectRange(SelectVisual, start, end)
+		return true
+	}
+
+	start, _ = m.nextPos(start)
+
+	// the content starts on the line after the open bracket and the
+	// closing bracket is preceded by nothing but indentation
+	linewise := m.runeAt(start) == '\n' &&
+		strings.TrimSpace(string(m.value[end.Row][:end.ColumnOffset])) == ""
+
+	if linewise {
+		if start.Row+1 > end.Row-1 {
+			return false
+		}
+
+		m.selectRange(
+			SelectVisualLine,
+			CursorPos{Row: start.Row + 1},
+			CursorPos{Row: end.Row - 1},
+		)
+		return true
+	}
+
+	if start == end {
+		return false
+	}
+
+	m.selectRange(SelectVisual, start, m.lastCharPos(end))
+	return true
+}
@@ -0,0 +1,61 @@
+package textarea
+
+import (
+	"testing"
+)
+
+func TestTextObjects(t *testing.T) {
+	quoted := func(outer bool) func(m *Model) bool {
+		return func(m *Model) bool { return m.SelectQuoted('"', outer) }
+	}
+	brackets := func(open, closing rune, outer bool) func(m *Model) bool {
+		return func(m *Model) bool { return m.SelectBrackets(open, closing, outer) }
+	}
+	sentence := func(outer bool) func(m *Model) bool {
+		return func(m *Model) bool { return m.SelectSentence(outer) }
+	}
+	paragraph := func(outer bool) func(m *Model) bool {
+		return func(m *Model) bool { return m.SelectParagraph(outer) }
+	}
+
+	tests := []struct {
+		content  string
+		row, col int
+		selectFn func(m *Model) bool
+		expected string
+	}{
+		{"say \"hello there\" now", 0, 6, quoted(false), "hello there"},
+		{"say \"hello there\" now", 0, 6, quoted(true), "\"hello there\" "},
+		{"no quotes \"here\"", 0, 0, quoted(false), "here"},
+		{"fn(a, (b), c)", 0, 3, brackets('(', ')', false), "a, (b), c"},
+		{"fn(a, (b), c)", 0, 7, brackets('(', ')', true), "(b)"},
+		{"<b>bold</b>", 0, 1, brackets('<', '>', false), "b"},
+		{"One. Two. Three.", 0, 5, sentence(false), "Two."},
+		{"One. Two. Three.", 0, 5, sentence(true), "Two. "},
+		{"first\nparagraph\n\nsecond", 0, 0, paragraph(false), "first\nparagraph\n"},
+		{"first\nparagraph\n\nsecond", 0, 0, paragraph(true), "first\nparagraph\n\n"},
+	}
+
+	for _, tt := range tests {
+		m := New()
+		m.SetValue(tt.content)
+		m.MoveCursor(tt.row, 0, tt.col)
+
+		if !tt.selectFn(&m) {
+			t.Errorf("%q at %d: expected a selection", tt.content, tt.col)
+			continue
+		}
+
+		if got := m.SelectionStr(); got != tt.expected {
+			t.Errorf("%q at %d: expected %q, got %q", tt.content, tt.col, tt.expected, got)
+		}
+	}
+
+	// there's nothing to select without brackets
+	m := New()
+	m.SetValue("no brackets")
+
+	if m.SelectBrackets('(', ')', false) {
+		t.Error("expected nothing to be selected without brackets")
+	}
+}
@@ -0,0 +1,269 @@
+package textarea
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	rw "github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+var (
+	// breakIndentRegex matches the beginning of a line whose width the
+	// continuation rows are indented by if BreakIndent is set:
+	// the indentation, blockquote markers and list markers
+	breakIndentRegex = regexp.MustCompile(`^[ \t]*(?:>[ \t]*)*(?:(?:[-*+]|\d{1,9}[.)])[ \t]+(?:\[[ xX]\][ \t]+)?)?`)
+)
+
+const (
+	// minBreakWidth is the number of columns the indentation of
+	// BreakIndent leaves at least for the text of continuation rows
+	minBreakWidth = 20
+
+	// noWrapWidth is the row width lines are "wrapped" at if NoWrap is set
+	noWrapWidth = math.MaxInt32
+)
+
+// wrapInput is the input to the text wrapping function. This is stored in
+// a struct so that it can be hashed and memoized.
+type wrapInput struct {
+	runes []rune
+
+	// width is the width of the first row of the line,
+	// nextWidth the width of its continuation rows
+	width, nextWidth int
+
+	tabStop int
+}
+
+// Hash returns a hash of the wrap input.
+func (w wrapInput) Hash() string {
+	v := fmt.Sprintf("%s:%d:%d:%d", string(w.runes), w.width, w.nextWidth, w.tabStop)
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(v)))
+}
+
+// memoizedWrap returns the rows the line is displayed on.
+// Continuation rows are narrower by the width of the break prefix.
+// If NoWrap is set the whole line is displayed on a single row
+func (m Model) memoizedWrap(runes []rune, width int) [][]rune {
+	input := wrapInput{
+		runes:     runes,
+		width:     noWrapWidth,
+		nextWidth: noWrapWidth,
+		tabStop:   m.tabStop(),
+	}
+
+	if !m.NoWrap {
+		input.width = width
+		input.nextWidth = width - uniseg.StringWidth(m.breakPrefix(runes, width))
+	}
+
+	if v, ok := m.cache.Get(input); ok {
+		return v
+	}
+	v := wrap(input)
+	m.cache.Set(input, v)
+	return v
+}
+
+// breakPrefix returns what is displayed at the beginning of the
+// continuation rows of the line: the indentation of BreakIndent
+// followed by ShowBreak
+func (m Model) breakPrefix(runes []rune, width int) string {
+	if m.NoWrap || (!m.BreakIndent && m.ShowBreak == "") {
+		return ""
+	}
+
+	showBreak := uniseg.StringWidth(m.ShowBreak)
+	indent := 0
+
+	if m.BreakIndent {
+		prefix := []rune(breakIndentRegex.FindString(string(runes)))
+		indent = uniseg.StringWidth(string(ExpandTabs(prefix, m.tabStop())))
+		indent = max(min(indent, width-showBreak-minBreakWidth), 0)
+	}
+
+	// the prefix is left out if there's no space for the text
+	if indent+showBreak >= width {
+		return ""
+	}
+
+	return strings.Repeat(" ", indent) + m.ShowBreak
+}
+
+// continuationWidth returns the width of the break prefix that is displayed
+// in front of the soft wrapped row `rowOffset` of the line
+func (m Model) continuationWidth(runes []rune, rowOffset int) int {
+	if rowOffset == 0 {
+		return 0
+	}
+	return uniseg.StringWidth(m.breakPrefix(runes, m.width))
+}
+
+// wrap breaks the line into the rows it's displayed on. Lines are broken
+// after the blanks between words, only words that are wider than a row are
+// broken within. Blanks at the end of a row are kept on the row even if they
+// exceed its width so that no continuation row starts with a blank.
+//
+// The last row gets an extra space the cursor is displayed on when it's at
+// the end of the line. If the last row is full the space is put on a row
+// of its own
+func wrap(in wrapInput) [][]rune {
+	var (
+		rows  = [][]rune{{}}
+		width = in.width
+
+		// used is the number of columns of the current row that are taken
+		used int
+
+		// col is the display column within the whole line,
+		// tabs are expanded to the next tab stop
+		col int
+	)
+
+	newRow := func() {
+		rows = append(rows, []rune{})
+		width = in.nextWidth
+		used = 0
+	}
+
+	add := func(r rune) {
+		w := rw.RuneWidth(r)
+		if r == '\t' {
+			w = in.tabStop - col%in.tabStop
+		}
+
+		rows[len(rows)-1] = append(rows[len(rows)-1], r)
+		used += w
+		col += w
+	}
+
+	for i := 0; i < len(in.runes); {
+		if unicode.IsSpace(in.runes[i]) {
+			add(in.runes[i])
+			i++
+			continue
+		}
+
+		end := i
+		wordWidth := 0
+		for end < len(in.runes) && !unicode.IsSpace(in.runes[end]) {
+			wordWidth += rw.RuneWidth(in.runes[end])
+			end++
+		}
+
+		if used > 0 && used+wordWidth > width {
+			newRow()
+		}
+
+		for _, r := range in.runes[i:end] {
+			// words that are wider than a row are broken at its end
+			if used > 0 && used+rw.RuneWidth(r) > width {
+				newRow()
+			}
+			add(r)
+		}
+
+		i = end
+	}
+
+	if used >= width {
+		newRow()
+	}
+	rows[len(rows)-1] = append(rows[len(rows)-1], ' ')
+
+	return rows
+}
+
+// fitWidth returns the beginning of the runes that fits into `width` columns
+func fitWidth(runes []rune, width int) []rune {
+	w := 0
+	for i, r := range runes {
+		w += rw.RuneWidth(r)
+		if w > width {
+			return runes[:i]
+		}
+	}
+	return runes
+}
+
+// scrollHorizontally changes the first displayed column of lines if NoWrap
+// is set so that the cursor is visible, but scrolls as little as possible
+func (m *Model) scrollHorizontally() {
+	if !m.NoWrap || len(m.value) == 0 {
+		m.leftCol = 0
+		return
+	}
+
+	line := ExpandTabs(m.value[m.row], m.tabStop())
+	col := expandedCol(m.value[m.row], m.col, m.tabStop())
+	start := DisplayCol(line, col)
+	end := start + runeWidth(line, col)
+
+	if start < m.leftCol {
+		m.leftCol = start
+	} else if end > m.leftCol+m.width {
+		m.leftCol = end - m.width
+	}
+}
+
+// ScrollHorizontally scrolls lines `n` columns to the right if NoWrap is
+// set, or to the left if `n` is negative. Lines can be scrolled until the
+// last character of the cursor line is the first displayed one.
+// The cursor is moved to the nearest column that is still visible
+func (m *Model) ScrollHorizontally(n int) {
+	if !m.NoWrap {
+		return
+	}
+
+	line := ExpandTabs(m.value[m.row], m.tabStop())
+	lineWidth := DisplayCol(line, len(line))
+	m.leftCol = clamp(m.leftCol+n, 0, max(lineWidth-1, 0))
+
+	col := expandedCol(m.value[m.row], m.col, m.tabStop())
+	start := DisplayCol(line, col)
+
+	target := -1
+	if start < m.leftCol {
+		target = m.leftCol
+	} else if start+runeWidth(line, col) > m.leftCol+m.width {
+		target = m.leftCol + m.width - 1
+	}
+
+	if target == -1 {
+		return
+	}
+
+	// move to the rune that covers the target column
+	width := 0
+	for i, r := range m.value[m.row] {
+		w := rw.RuneWidth(r)
+		if r == '\t' {
+			w = m.tabStop() - width%m.tabStop()
+		}
+
+		if width+w > target {
+			m.SetCursorColumn(i)
+			return
+		}
+		width += w
+	}
+	m.SetCursorColumn(max(len(m.value[m.row])-1, 0))
+}
+
+// visibleStart returns the offset of the first rune of the line that is
+// displayed if NoWrap is set. Wide runes that are cut off aren't displayed
+func (m Model) visibleStart(line []rune) int {
+	width := 0
+	for i, r := range line {
+		if width >= m.leftCol {
+			return i
+		}
+		width += rw.RuneWidth(r)
+	}
+	return len(line)
+}
@@ -0,0 +1,111 @@
+package textarea
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		width     int
+		nextWidth int
+		expected  []string
+	}{
+		{
+			name:      "word boundaries",
+			line:      "the quick brown fox",
+			width:     10,
+			nextWidth: 10,
+			expected:  []string{"the quick ", "brown fox "},
+		},
+		{
+			name:      "blanks hang at the end of a row",
+			line:      "the quick   brown",
+			width:     9,
+			nextWidth: 9,
+			expected:  []string{"the quick   ", "brown "},
+		},
+		{
+			name:      "long words are broken",
+			line:      "a abcdefghijkl b",
+			width:     5,
+			nextWidth: 5,
+			expected:  []string{"a ", "abcde", "fghij", "kl b "},
+		},
+		{
+			name:      "narrower continuation rows",
+			line:      "one two three four",
+			width:     10,
+			nextWidth: 6,
+			expected:  []string{"one two ", "three ", "four "},
+		},
+		{
+			name:      "full last row",
+			line:      "abcd",
+			width:     4,
+			nextWidth: 4,
+			expected:  []string{"abcd", " "},
+		},
+		{
+			name:      "tabs",
+			line:      "\tab cd",
+			width:     7,
+			nextWidth: 7,
+			expected:  []string{"\tab ", "cd "},
+		},
+		{
+			name:      "empty line",
+			line:      "",
+			width:     10,
+			nextWidth: 10,
+			expected:  []string{" "},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows := wrap(wrapInput{
+				runes:     []rune(test.line),
+				width:     test.width,
+				nextWidth: test.nextWidth,
+				tabStop:   4,
+			})
+
+			got := []string{}
+			for _, row := range rows {
+				got = append(got, string(row))
+			}
+
+			if !slices.Equal(got, test.expected) {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestBreakPrefix(t *testing.T) {
+	m := New()
+	m.BreakIndent = true
+	m.ShowBreak = "> "
+
+	tests := []struct {
+		line     string
+		width    int
+		expected string
+	}{
+		{"plain text", 40, "> "},
+		{"    indented", 40, "    > "},
+		{"  - list item", 40, "    > "},
+		{"12. numbered", 40, "    > "},
+		{"- [ ] task", 40, "      > "},
+		{"        deeply indented", 25, "   > "},
+	}
+
+	for _, test := range tests {
+		if got := m.breakPrefix([]rune(test.line), test.width); got != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.line, test.expected, got)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"maps"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -20,7 +21,13 @@ import (
 
 type Handler interface {
 	FnRegistry() MotionRegistry
+	OperatorRegistry() OperatorRegistry
 	Mode() *mode.ModeInstance
+
+	// Changes returns the number of changes that have been made
+	// to any buffer. It's used to find out whether a command
+	// changed the text so that it can be repeated with `.`
+	Changes() int
 }
 
 // FocusedComponent represents any UI component that can report whether
@@ -33,16 +40,53 @@ type FocusedComponent interface {
 
 type ResetSequenceMsg struct{}
 
+// recordedKey is a key that is part of a recorded change
+type recordedKey struct {
+	key tea.Key
+
+	// count indicates that the key is part of a count prefix
+	// which is dropped if the change is repeated with a new count
+	count bool
+}
+
 type Action struct {
-	binding string
-	exec    func() message.StatusBarMsg
-	modes   []mode.Mode
-	opts    Options
+	binding  string
+	motion   Motion
+	operator Operator
+	modes    []mode.Mode
+	opts     Options
 }
 
 func (a *Action) Opts() Options   { return a.opts }
 func (a *Action) Binding() string { return a.binding }
 
+// exec runs the action's motion.
+// The given count and register are passed to the motion as the `count`
+// and `register` options so that every motion can decide on its own
+// how to handle them.
+func (a *Action) exec(count int, register rune) message.StatusBarMsg {
+	return a.motion(a.execOpts(count, register))()
+}
+
+// execOpts returns a copy of the action's options including
+// the given count and register
+func (a *Action) execOpts(count int, register rune) Options {
+	opts := maps.Clone(a.opts)
+	if opts == nil {
+		opts = Options{}
+	}
+
+	if count > 0 {
+		opts[Args.Count] = count
+	}
+
+	if register != 0 {
+		opts[Args.Register] = string(register)
+	}
+
+	return opts
+}
+
 // Input represents the state and configuration of the input handler,
 // including current key sequences, modifier states, mode, and the
 // list of all configured key actions.
@@ -52,6 +96,59 @@ type Input struct {
 	sequenceKeys   []string
 	sequenceLength int
 
+	// AllowCount indicates whether numeric key input is treated as
+	// a count prefix for the following motion
+	AllowCount bool
+
+	// count is the numeric prefix typed before a motion, e.g. the 5 in `5j`.
+	// It is 0 if no count has been typed.
+	count int
+
+	// operator is the pending operator in operator-pending mode,
+	// e.g. the `d` in `dw`. It is applied to the next motion or text object.
+	operator *Action
+
+	// operatorCount is the count typed before the pending operator,
+	// e.g. the 2 in `2d3w`
+	operatorCount int
+
+	// register is the register selected with `"x`, e.g. the `a` in `"ayy`.
+	// It is 0 if no register has been selected.
+	register rune
+
+	// awaitRegister indicates that `"` has been typed and the next
+	// key is the name of a register
+	awaitRegister bool
+
+	// recording holds the keys of the command that is currently
+	// typed, starting with the first key typed in normal mode.
+	// Once the command is complete and has changed the text,
+	// it becomes the lastChange
+	recording []recordedKey
+
+	// recordingStart is the number of changes at the time
+	// the recording started
+	recordingStart int
+
+	// lastChange holds the keys of the last command that changed
+	// the text, e.g. `ciw` and the inserted text. It's repeated with `.`
+	lastChange []recordedKey
+
+	// replaying is the depth of the currently replayed keys, e.g. 2 if
+	// a macro is played by another macro. It's 0 if nothing is replayed.
+	replaying int
+
+	// macroRegister is the register the macro that is currently
+	// recorded is stored in. It's 0 if no macro is recorded
+	macroRegister rune
+
+	// macro holds the keys of the macro that is currently recorded
+	macro []tea.Key
+
+	// awaitKey handles the next key instead of any key binding,
+	// e.g. the name of the register after `q` or `@`
+	awaitKey func(key tea.Key) message.StatusBarMsg
+
 	// AwaitInputAction stores the action to execute after receiving additional input.
 	// This is used when a keybind has "await_input": true in the keymap,
 	// meaning the action should not run immediately but wait for further key input.
@@ -74,12 +171,43 @@ type Input struct {
 	UserKeyMap    []byte
 
 	Registry   MotionRegistry
+	Operators  OperatorRegistry
 	Components []FocusedComponent
+
+	handler Handler
 }
 
+// KeyHandler handles a key just as if it has been typed.
+// It's used to replay recorded keys
+type KeyHandler func(key tea.Key)
+
 type Motion func(opts Options) func() message.StatusBarMsg
 type MotionRegistry map[string]Motion
 
+// Operator is applied to the text that a motion or text object
+// moves over or selects, e.g. `d` in `dw` or `y` in `yiw`
+type Operator func(opts Options, motion OperatorMotion) func() message.StatusBarMsg
+type OperatorRegistry map[string]Operator
+
+// OperatorMotion describes the motion or text object an operator
+// is applied to
+type OperatorMotion struct {
+	// Exec executes the motion. It is nil if the operator has been
+	// repeated (e.g. `dd`) which applies it to whole lines
+	Exec func() message.StatusBarMsg
+
+	// Count is the count of the operator multiplied by the count
+	// of the motion. It's 1 if no count has been typed.
+	Count int
+
+	// Linewise indicates that the operator applies to whole lines
+	Linewise bool
+
+	// Inclusive indicates that the character under the cursor
+	// after the motion is part of the range
+	Inclusive bool
+}
+
 // New creates and returns a new Input instance with default state.
 func New(h Handler) *Input {
 	keymap := NewKeyMap()
@@ -101,6 +229,8 @@ func New(h Handler) *Input {
 		AllowSequences:   true,
 		sequenceKeys:     []string{},
 		sequenceLength:   0,
+		AllowCount:       true,
+		count:            0,
 		AwaitInputAction: nil,
 		sequenceTimeOut:  300,
 		Space:            false,
@@ -112,7 +242,9 @@ func New(h Handler) *Input {
 		DefaultKeyMap:    defaultKeyMap,
 		UserKeyMap:       userKeyMap,
 		Registry:         h.FnRegistry(),
+		Operators:        h.OperatorRegistry(),
 		Components:       []FocusedComponent{},
+		handler:          h,
 	}
 }
 
@@ -134,12 +266,63 @@ func (input *Input) isModifier(binding string) (string, bool) {
 // key sequence and modifier states as needed, and executing any matching
 // actions.
 func (input *Input) HandleSequences(key tea.Key) []message.StatusBarMsg {
-	if key.String() == "esc" && input.KeySequence != "" {
-		return []message.StatusBarMsg{input.ResetKeysDown()}
+	if input.replaying == 0 {
+		input.record(key)
+		input.recordMacro(key)
+	}
+
+	if key.String() == "esc" && input.isPending() {
+		msgs := []message.StatusBarMsg{}
+
+		// the awaiting function may need to clean up,
+		// e.g. a substitution waiting for confirmation
+		if fn := input.awaitKey; fn != nil {
+			input.awaitKey = nil
+			msgs = append(msgs, fn(key))
+		}
+
+		input.CancelOperator()
+		return append(msgs, input.ResetKeysDown())
+	}
+
+	if fn := input.awaitKey; fn != nil {
+		input.awaitKey = nil
+		return []message.StatusBarMsg{fn(key)}
+	}
+
+	if input.awaitRegister {
+		input.awaitRegister = false
+
+		if r := []rune(key.Text); len(r) == 1 {
+			input.register = r[0]
+		}
+
+		return []message.StatusBarMsg{{
+			Content: input.operatorString() + input.registerString(),
+			Column:  sbc.KeyInfo,
+		}}
+	}
+
+	if input.isRegisterKey(key) {
+		input.awaitRegister = true
+
+		return []message.StatusBarMsg{{
+			Content: input.operatorString() + input.registerString() + key.Text,
+			Column:  sbc.KeyInfo,
+		}}
+	}
+
+	if input.isCountKey(key) {
+		input.count = input.count*10 + int(key.Code-'0')
+
+		return []message.StatusBarMsg{{
+			Content: strconv.Itoa(input.count),
+			Column:  sbc.KeyInfo,
+		}}
 	}
 
 	// special treatment for space to make it simulate a leader key
-	if input.Mode.Current == mode.Normal && !input.Space && input.KeySequence == "" {
+	if input.currentMode() == mode.Normal && !input.Space && input.KeySequence == "" {
 		if key.Code == 32 {
 			input.KeySequence += key.Keystroke()
 			input.Space = true
@@ -158,7 +341,7 @@ func (input *Input) HandleSequences(key tea.Key) []message.StatusBarMsg {
 	}
 
 	// If we need to wait for further input cache the original action
-	if action, ok := input.componentActions[input.Mode.Current][input.KeySequence]; ok {
+	if action, ok := input.componentActions[input.currentMode()][input.KeySequence]; ok {
 		if input.AwaitInputAction == nil && action.opts.GetBool("await_input") {
 			input.AwaitInputAction = &action
 		}
@@ -187,7 +370,9 @@ func (input *Input) HandleSequences(key tea.Key) []message.StatusBarMsg {
 
 			keyInfo := keyInfoMsg.Content
 			if input.Mode.Current != mode.Insert {
-				keyInfo = strings.ReplaceAll(input.KeySequence, "ctrl", "^")
+				keyInfo = input.operatorString() + input.registerString() +
+					input.countString() +
+					strings.ReplaceAll(input.KeySequence, "ctrl", "^")
 				if input.Ctrl {
 					keyInfo = strings.ReplaceAll(keyInfo, "+", "")
 				}
@@ -210,15 +395,279 @@ func (input *Input) HandleSequences(key tea.Key) []message.StatusBarMsg {
 // executeAction attempts to find and execute an action matching the given
 // key binding string in the current mode and focused component.
 func (input *Input) executeAction(binding string) message.StatusBarMsg {
-	if action := input.AwaitInputAction; action != nil {
-		return action.exec()
+	action := input.AwaitInputAction
+
+	if action == nil {
+		if a, ok := input.componentActions[input.currentMode()][binding]; ok {
+			action = &a
+		}
 	}
 
-	if action, ok := input.componentActions[input.Mode.Current][binding]; ok {
-		return action.exec()
+	if input.operator != nil {
+		return input.applyOperator(binding, action)
 	}
 
-	return message.StatusBarMsg{}
+	if action == nil {
+		return message.StatusBarMsg{}
+	}
+
+	if action.operator != nil {
+		return input.startOperator(action)
+	}
+
+	return action.exec(input.count, input.register)
+}
+
+// record adds the key to the recording of the current command.
+// If the previous command has been completed and changed the text
+// its keys are kept as the last change and a new recording is started.
+func (input *Input) record(key tea.Key) {
+	idle := input.Mode.Current == mode.Normal &&
+		!input.isPending() &&
+		input.count == 0
+
+	if idle && len(input.recording) > 0 {
+		if input.handler.Changes() != input.recordingStart {
+			input.lastChange = input.recording
+		}
+		input.recording = nil
+	}
+
+	// commands and searches typed in the status bar are not repeatable
+	if !slices.Contains(recordableModes, input.Mode.Current) {
+		input.recording = nil
+		return
+	}
+
+	if len(input.recording) == 0 {
+		if !idle {
+			return
+		}
+		input.recordingStart = input.handler.Changes()
+	}
+
+	input.recording = append(input.recording, recordedKey{
+		key:   key,
+		count: input.isCountKey(key) && !input.Mode.IsAnyVisual(),
+	})
+}
+
+// recordableModes are the modes in which keys are recorded
+// so that the change they make can be repeated
+var recordableModes = []mode.Mode{
+	mode.Normal,
+	mode.Insert,
+	mode.Replace,
+	mode.Visual,
+	mode.VisualLine,
+	mode.VisualBlock,
+}
+
+// RepeatChange replays the keys of the last change at the cursor.
+// If count is greater than 0 it replaces the count of the change.
+// Returns false if there's no change that can be repeated.
+func (input *Input) RepeatChange(count int, handleKey KeyHandler) bool {
+	keys := []tea.Key{}
+
+	for _, k := range input.lastChange {
+		if count > 0 && k.count {
+			continue
+		}
+		keys = append(keys, k.key)
+	}
+
+	return input.replay(keys, count, handleKey)
+}
+
+// Replay passes the given keys to handleKey one after another.
+// Replayed keys are neither recorded as a change nor as part of a macro.
+// Returns false if there's nothing to replay
+func (input *Input) Replay(keys []tea.Key, handleKey KeyHandler) bool {
+	return input.replay(keys, 0, handleKey)
+}
+
+// maxReplayDepth limits how deep replays can be nested so that
+// a macro that plays itself doesn't run forever
+const maxReplayDepth = 100
+
+func (input *Input) replay(keys []tea.Key, count int, handleKey KeyHandler) bool {
+	// the keys that started the replay are not a change
+	// that can be repeated
+	input.recording = nil
+
+	if len(keys) == 0 || input.replaying >= maxReplayDepth {
+		return false
+	}
+
+	input.ResetKeysDown()
+	input.count = count
+
+	input.replaying++
+	defer func() { input.replaying-- }()
+
+	for _, key := range keys {
+		handleKey(key)
+	}
+
+	return true
+}
+
+// startOperator enters operator-pending mode.
+// The operator waits for a motion or text object it is applied to.
+func (input *Input) startOperator(action *Action) message.StatusBarMsg {
+	input.operator = action
+	input.operatorCount = input.count
+
+	return message.StatusBarMsg{
+		Content: input.operatorString(),
+		Column:  sbc.KeyInfo,
+	}
+}
+
+// applyOperator applies the pending operator to the given action and
+// leaves operator-pending mode.
+// If the operator is repeated, e.g. `dd` or `guu`, it's applied to
+// whole lines. Anything that is not a motion cancels the operator.
+func (input *Input) applyOperator(binding string, action *Action) message.StatusBarMsg {
+	operator := input.operator
+
+	// Just like in vim both counts are multiplied, so `2d3w` deletes 6 words
+	count := max(input.operatorCount, 1) * max(input.count, 1)
+	register := input.register
+	input.CancelOperator()
+
+	motion := OperatorMotion{Count: count}
+
+	switch {
+	case isOperatorRepeat(operator.binding, binding):
+		motion.Linewise = true
+
+	case action != nil && action.motion != nil:
+		opts := action.execOpts(count, register)
+		opts[Args.PendingOperator] = true
+		motion.Exec = action.motion(opts)
+		motion.Linewise = opts.GetBool(Args.Linewise)
+		motion.Inclusive = opts.GetBool(Args.Inclusive)
+
+	default:
+		return message.StatusBarMsg{Content: "", Column: sbc.KeyInfo}
+	}
+
+	return operator.operator(operator.execOpts(count, register), motion)()
+}
+
+// isOperatorRepeat returns whether the binding repeats the operator binding.
+// Operators with multiple keys can also be repeated with their last key,
+// e.g. `guu` instead of `gugu`
+func isOperatorRepeat(operator string, binding string) bool {
+	if binding == operator {
+		return true
+	}
+
+	runes := []rune(operator)
+	return len(runes) > 1 && binding == string(runes[len(runes)-1])
+}
+
+// CancelOperator leaves operator-pending mode without applying
+// the pending operator
+func (input *Input) CancelOperator() {
+	input.operator = nil
+	input.operatorCount = 0
+	input.register = 0
+}
+
+// OperatorPending returns whether an operator waits for a motion
+func (input *Input) OperatorPending() bool {
+	return input.operator != nil
+}
+
+// isPending returns whether a key sequence, an operator or
+// a register is waiting for further input
+func (input *Input) isPending() bool {
+	return input.KeySequence != "" ||
+		input.operator != nil ||
+		input.register != 0 ||
+		input.awaitRegister ||
+		input.awaitKey != nil
+}
+
+// isRegisterKey returns whether the key starts the selection of
+// a register, e.g. the `"` in `"ayy`
+func (input *Input) isRegisterKey(key tea.Key) bool {
+	return key.Text == `"` &&
+		input.KeySequence == "" &&
+		slices.Contains(mode.SupportsMotion(), input.currentMode())
+}
+
+// registerString returns the selected register as it has been typed
+// or an empty string if no register has been selected
+func (input *Input) registerString() string {
+	if input.register == 0 {
+		return ""
+	}
+	return `"` + string(input.register)
+}
+
+// currentMode returns the mode whose bindings are used for the
+// current key input.
+// While an operator is pending the bindings of operator-pending mode
+// are used, which is not reflected in the mode of the application.
+func (input *Input) currentMode() mode.Mode {
+	if input.operator != nil {
+		return mode.Operator
+	}
+	return input.Mode.Current
+}
+
+// operatorString returns the pending operator, including its count,
+// as a string or an empty string if no operator is pending
+func (input *Input) operatorString() string {
+	if input.operator == nil {
+		return ""
+	}
+
+	count := ""
+	if input.operatorCount > 0 {
+		count = strconv.Itoa(input.operatorCount)
+	}
+
+	return count + input.operator.binding
+}
+
+// Count returns the count typed before the current key sequence.
+// Returns 0 if no count has been typed.
+func (input *Input) Count() int {
+	return input.count
+}
+
+// isCountKey returns whether the given key is part of a count prefix.
+// A count can only be typed in modes that support motions and
+// before any other key of a sequence has been typed.
+// Just like in Vim `0` only counts if it's not the first digit,
+// otherwise it's the motion for going to the start of the line.
+func (input *Input) isCountKey(key tea.Key) bool {
+	if !input.AllowCount ||
+		!slices.Contains(mode.SupportsMotion(), input.currentMode()) ||
+		input.KeySequence != "" ||
+		key.Mod != 0 {
+
+		return false
+	}
+
+	if key.Code < '0' || key.Code > '9' {
+		return false
+	}
+
+	return key.Code != '0' || input.count > 0
+}
+
+// countString returns the current count as a string or an empty string
+// if no count has been typed
+func (input *Input) countString() string {
+	if input.count == 0 {
+		return ""
+	}
+	return strconv.Itoa(input.count)
 }
 
 // ReloadKeyMap reads the user's keymap.json and rewrites the cached map
@@ -299,8 +748,10 @@ func (input *Input) parseKeyMap(
 		}
 
 		for key, binding := range set.Bindings {
-			actionFn, ok := input.Registry[binding.Action]
-			if !ok {
+			actionFn, isMotion := input.Registry[binding.Action]
+			operatorFn, isOperator := input.Operators[binding.Action]
+
+			if !isMotion && !isOperator {
 				continue
 			}
 
@@ -330,9 +781,10 @@ func (input *Input) parseKeyMap(
 
 					if !ok {
 						action = Action{
-							binding: key,
-							exec:    actionFn(binding.Options),
-							opts:    binding.Options,
+							binding:  key,
+							motion:   actionFn,
+							operator: operatorFn,
+							opts:     binding.Options,
 						}
 					}
 
@@ -383,7 +835,7 @@ func (input *Input) addSequenceKey(binding string, force bool) {
 // isBinding returns wether the given key string is a
 // known and valid key binding
 func (input *Input) isBinding(key string) bool {
-	if action, ok := input.componentActions[input.Mode.Current][key]; ok {
+	if action, ok := input.componentActions[input.currentMode()][key]; ok {
 		o := action.opts
 		if o.GetBool("operator") && o.GetBool("await_input") {
 			return false
@@ -412,6 +864,13 @@ func (input *Input) ResetKeysDown() message.StatusBarMsg {
 	input.Alt = false
 	input.KeySequence = ""
 	input.AwaitInputAction = nil
+	input.count = 0
+	input.awaitRegister = false
+
+	// the register is used by the motion following the pending operator
+	if input.operator == nil {
+		input.register = 0
+	}
 
 	return message.StatusBarMsg{
 		Content: "",
@@ -422,8 +881,16 @@ func (input *Input) ResetKeysDown() message.StatusBarMsg {
 // ResetSequence resets the key sequence after the given delay.
 // Simulates Vim's `timeoutlen`
 func (input *Input) ResetSequence() tea.Cmd {
+	timeOut := input.sequenceTimeOut
+
 	return func() tea.Msg {
-		time.Sleep(input.sequenceTimeOut * time.Millisecond)
+		time.Sleep(timeOut * time.Millisecond)
 		return ResetSequenceMsg{}
 	}
 }
+
+// SetSequenceTimeOut sets the time in milliseconds to wait
+// for a mapped sequence to complete
+func (input *Input) SetSequenceTimeOut(ms int) {
+	input.sequenceTimeOut = time.Duration(ms)
+}
@@ -0,0 +1,45 @@
+package keyinput
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestRepeatChange(t *testing.T) {
+	input := &Input{
+		lastChange: []recordedKey{
+			{key: tea.Key{Code: '2', Text: "2"}, count: true},
+			{key: tea.Key{Code: 'x', Text: "x"}},
+		},
+	}
+
+	keys := ""
+	collect := func(key tea.Key) { keys += key.Text }
+
+	if !input.RepeatChange(0, collect) || keys != "2x" {
+		t.Errorf("expected the change to be repeated with its count, got %q", keys)
+	}
+
+	// a count replaces the count of the change
+	keys = ""
+	count := 0
+	input.recording = []recordedKey{{key: tea.Key{Code: '.', Text: "."}}}
+	input.RepeatChange(3, func(key tea.Key) {
+		count = input.Count()
+		collect(key)
+	})
+
+	if keys != "x" || count != 3 {
+		t.Errorf("expected %q with count 3, got %q with count %d", "x", keys, count)
+	}
+
+	// the keys that started the repeat are not a change
+	if input.recording != nil {
+		t.Errorf("expected the recording to be discarded, got %v", input.recording)
+	}
+
+	if (&Input{}).RepeatChange(0, collect) {
+		t.Error("expected nothing to be repeated without a change")
+	}
+}
@@ -24,7 +24,9 @@ const keyMapFileName = "keymap.json"
 
 var Args = struct {
 	Outer, Prev, WhiteSpace, Remaining, Operator, AwaitInput,
-	End, NewLine, MultiLine, Cycle, IgnoreCase, Insert, Include string
+	End, NewLine, MultiLine, Cycle, IgnoreCase, Insert, Include, Count,
+	Linewise, Inclusive, Till, PendingOperator, Register, Exact,
+	Quote, Brackets, List, Bad, KeepCursor string
 }{
 	Outer:      "outer",
 	Prev:       "prev",
@@ -39,6 +41,19 @@ var Args = struct {
 	IgnoreCase: "ignore_case",
 	Insert:     "insert",
 	Include:    "include",
+	Count:      "count",
+	Linewise:   "linewise",
+	Inclusive:  "inclusive",
+	Till:       "till",
+	// set when a motion is executed for a pending operator
+	PendingOperator: "pending_operator",
+	Register:        "register",
+	Exact:           "exact",
+	Quote:           "quote",
+	Brackets:        "brackets",
+	List:            "list",
+	Bad:             "bad",
+	KeepCursor:      "keep_cursor",
 }
 
 type KeyMap struct {
@@ -141,6 +156,8 @@ func (entry *KeyMapEntry) ResolveMode(ki *Input) mode.Mode {
 		return mode.Command
 	case "search":
 		return mode.SearchPrompt
+	case "operator":
+		return mode.Operator
 	}
 	return mode.Normal
 }
@@ -170,6 +187,45 @@ func (opts Options) GetString(key string) string {
 	return str
 }
 
+func (opts Options) GetInt(key string) int {
+	val, ok := opts[key]
+
+	if !ok {
+		return 0
+	}
+
+	switch i := val.(type) {
+	case int:
+		return i
+	// json numbers are always unmarshalled as float64
+	case float64:
+		return int(i)
+	}
+
+	return 0
+}
+
+// Count returns the count that was typed before the binding.
+// If no count was typed it returns 1 so the result can be used
+// directly to repeat a motion.
+func (opts Options) Count() int {
+	return max(opts.GetInt(Args.Count), 1)
+}
+
+// Register returns the register selected with `"x` before the binding
+// or 0 if no register has been selected
+func (opts Options) Register() rune {
+	if r := []rune(opts.GetString(Args.Register)); len(r) == 1 {
+		return r[0]
+	}
+	return 0
+}
+
+// HasCount returns whether a count was typed before the binding
+func (opts Options) HasCount() bool {
+	return opts.GetInt(Args.Count) > 0
+}
+
 type MapBinding struct {
 	Action  string
 	Options Options
@@ -0,0 +1,167 @@
+package keyinput
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"bellbird-notes/tui/message"
+)
+
+// AwaitKey passes the next key to fn instead of handling it
+// as a key binding
+func (input *Input) AwaitKey(fn func(key tea.Key) message.StatusBarMsg) {
+	input.awaitKey = fn
+}
+
+// StartMacro starts recording keys into the given register
+func (input *Input) StartMacro(register rune) {
+	input.macroRegister = register
+	input.macro = []tea.Key{}
+}
+
+// StopMacro stops recording a macro and returns the register
+// and the recorded keys without the key that stopped the recording.
+// Returns false if no macro is being recorded
+func (input *Input) StopMacro() (rune, []tea.Key, bool) {
+	if input.macroRegister == 0 {
+		return 0, nil, false
+	}
+
+	register := input.macroRegister
+	keys := input.macro[:max(len(input.macro)-1, 0)]
+
+	input.macroRegister = 0
+	input.macro = nil
+
+	return register, keys, true
+}
+
+// MacroRegister returns the register the macro that is currently
+// recorded is stored in or 0 if no macro is being recorded
+func (input *Input) MacroRegister() rune {
+	return input.macroRegister
+}
+
+func (input *Input) recordMacro(key tea.Key) {
+	if input.macroRegister != 0 {
+		input.macro = append(input.macro, key)
+	}
+}
+
+// namedKeys are the keys that are written as `<name>` when
+// keys are encoded as text
+var namedKeys = map[string]rune{
+	"esc":       tea.KeyEscape,
+	"enter":     tea.KeyEnter,
+	"tab":       tea.KeyTab,
+	"backspace": tea.KeyBackspace,
+	"delete":    tea.KeyDelete,
+	"insert":    tea.KeyInsert,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"space":     tea.KeySpace,
+}
+
+// keyModifiers are the modifiers that can prefix a named key,
+// e.g. `<ctrl+r>`
+var keyModifiers = map[string]tea.KeyMod{
+	"ctrl":  tea.ModCtrl,
+	"alt":   tea.ModAlt,
+	"shift": tea.ModShift,
+}
+
+// EncodeKeys returns the given keys as text so that they can be stored
+// in a register. Keys that insert text are written as the text,
+// every other key is written as `<name>`, e.g. `<esc>` or `<ctrl+r>`.
+// A literal `<` is written as `<lt>`.
+func EncodeKeys(keys []tea.Key) string {
+	var sb strings.Builder
+
+	for _, key := range keys {
+		switch {
+		case key.Text == "<":
+			sb.WriteString("<lt>")
+
+		case key.Text != "" && key.Mod&(tea.ModCtrl|tea.ModAlt) == 0:
+			sb.WriteString(key.Text)
+
+		default:
+			sb.WriteString("<" + key.Keystroke() + ">")
+		}
+	}
+
+	return sb.String()
+}
+
+// DecodeKeys converts text created by EncodeKeys back into keys.
+// Line breaks are decoded as enter and tabs as tab.
+// A `<` that doesn't start a known key name is a literal `<`
+func DecodeKeys(text string) []tea.Key {
+	keys := []tea.Key{}
+
+	for len(text) > 0 {
+		if strings.HasPrefix(text, "<") {
+			if end := strings.IndexByte(text, '>'); end > 0 {
+				if key, ok := decodeKeyName(text[1:end]); ok {
+					keys = append(keys, key)
+					text = text[end+1:]
+					continue
+				}
+			}
+		}
+
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+
+		switch r {
+		case '\n':
+			keys = append(keys, tea.Key{Code: tea.KeyEnter})
+		case '\t':
+			keys = append(keys, tea.Key{Code: tea.KeyTab})
+		default:
+			keys = append(keys, tea.Key{Code: r, Text: string(r)})
+		}
+	}
+
+	return keys
+}
+
+// decodeKeyName returns the key for a name written between `<` and `>`
+func decodeKeyName(name string) (tea.Key, bool) {
+	if name == "lt" {
+		return tea.Key{Code: '<', Text: "<"}, true
+	}
+
+	key := tea.Key{}
+	parts := strings.Split(name, "+")
+
+	for _, mod := range parts[:len(parts)-1] {
+		m, ok := keyModifiers[mod]
+		if !ok {
+			return key, false
+		}
+		key.Mod |= m
+	}
+
+	base := parts[len(parts)-1]
+
+	if code, ok := namedKeys[base]; ok {
+		key.Code = code
+		return key, true
+	}
+
+	if r := []rune(base); len(r) == 1 && key.Mod != 0 {
+		key.Code = r[0]
+		return key, true
+	}
+
+	return key, false
+}
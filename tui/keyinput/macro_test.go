@@ -0,0 +1,64 @@
+package keyinput
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestEncodeKeys(t *testing.T) {
+	keys := []tea.Key{
+		{Code: 'A', Text: "A"},
+		{Code: '<', Text: "<"},
+		{Code: tea.KeyEscape},
+		{Code: 'r', Mod: tea.ModCtrl},
+		{Code: tea.KeyEnter},
+	}
+
+	text := EncodeKeys(keys)
+	if text != "A<lt><esc><ctrl+r><enter>" {
+		t.Fatalf("expected the keys to be encoded as text, got %q", text)
+	}
+
+	decoded := DecodeKeys(text)
+	if EncodeKeys(decoded) != text {
+		t.Errorf("expected %q after decoding, got %q", text, EncodeKeys(decoded))
+	}
+
+	if decoded[3].Code != 'r' || decoded[3].Mod != tea.ModCtrl {
+		t.Errorf("expected ctrl+r to be decoded, got %v", decoded[3])
+	}
+
+	// unknown names and line breaks
+	if got := EncodeKeys(DecodeKeys("<foo>a\n")); got != "<lt>foo>a<enter>" {
+		t.Errorf("expected a literal < and enter, got %q", got)
+	}
+}
+
+func TestMacro(t *testing.T) {
+	input := &Input{}
+
+	if _, _, ok := input.StopMacro(); ok {
+		t.Fatal("expected no macro to be recorded")
+	}
+
+	input.StartMacro('a')
+	for _, r := range "dwq" {
+		input.recordMacro(tea.Key{Code: r, Text: string(r)})
+	}
+
+	if input.MacroRegister() != 'a' {
+		t.Errorf("expected the macro to be recorded into a, got %q", input.MacroRegister())
+	}
+
+	// the key that stopped the recording isn't part of the macro
+	register, keys, ok := input.StopMacro()
+	if !ok || register != 'a' || EncodeKeys(keys) != "dw" {
+		t.Errorf("expected %q in a, got %q in %q", "dw", EncodeKeys(keys), register)
+	}
+
+	input.recordMacro(tea.Key{Code: 'x', Text: "x"})
+	if input.MacroRegister() != 0 || input.macro != nil {
+		t.Error("expected keys not to be recorded after stopping")
+	}
+}
@@ -38,6 +38,15 @@ const (
 	SenderNotesList
 )
 
+// PluralSuffix returns the suffix of the plural of a noun
+// that is counted `n` times
+func PluralSuffix(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
 type StatusBarMsg struct {
 	Content string
 	Type    Type
@@ -2,7 +2,9 @@ package message
 
 var CmdPrompt = struct {
 	Yes, No, Quit, WriteBuf, WriteQuit, DeleteBufstring, ListBufs,
-	Set, Open, New, Reload, CheckTime string
+	Set, SetLocal, Open, New, Reload, CheckTime, Registers, Marks, Jumps,
+	Substitute, Global, VGlobal, Delete, Yank, Move, Copy, Join, Normal,
+	GoToLine, Tasks, Match string
 }{
 	Yes:             "y",
 	No:              "n",
@@ -12,21 +14,61 @@ var CmdPrompt = struct {
 	DeleteBufstring: "bd",
 	ListBufs:        "b",
 	Set:             "set",
+	SetLocal:        "setlocal",
 	Open:            "open",
 	New:             "new",
 	Reload:          "reload",
 	CheckTime:       "checktime",
+	Registers:       "registers",
+	Marks:           "marks",
+	Jumps:           "jumps",
+	Substitute:      "s",
+	Global:          "g",
+	VGlobal:         "v",
+	Delete:          "d",
+	Yank:            "y",
+	Move:            "m",
+	Copy:            "t",
+	Join:            "j",
+	Normal:          "normal",
+	GoToLine:        "goto",
+	Tasks:           "tasks",
+	Match:           "match",
 }
 
 var StatusBar = struct {
 	CmdPrompt, RemovePromptDirContent, RemovePrompt, NoteExists,
-	CtrlCExitNote, FileWritten string
+	CtrlCExitNote, FileWritten, RecordingMacro, MarkNotSet,
+	NoteNotFound, Substitutions, SubstituteConfirm, PatternNotFound,
+	InvalidPattern, InvalidRange, BackwardsRange, MoveIntoItself, NotACommand,
+	UnknownOption, InvalidArgument, NoTasks, SpellOff, NoMisspelled,
+	NoSuggestions, NoWordUnderCursor, WordAdded, WordMarkedWrong string
 }{
 	RemovePromptDirContent: "Delete `%s` and all of its content? [y(es),n(o)]",
 	RemovePrompt:           "Delete `%s`? [y(es),n(o)]",
 	NoteExists:             "Note already exists",
 	CtrlCExitNote:          "Type :q and press <Enter> to quit",
 	FileWritten:            "\"%s\" %dL, %dB written",
+	RecordingMacro:         "recording @%c",
+	MarkNotSet:             "Mark not set",
+	NoteNotFound:           "Can't open `%s`",
+	Substitutions:          "%d substitution%s on %d line%s",
+	SubstituteConfirm:      "replace with %s (y/n/a/q/l)?",
+	PatternNotFound:        "Pattern not found: %s",
+	InvalidPattern:         "Invalid pattern: %s",
+	InvalidRange:           "Invalid range",
+	BackwardsRange:         "Backwards range given, OK to swap (y/n)?",
+	MoveIntoItself:         "Cannot move a range of lines into itself",
+	NotACommand:            "Not an editor command: %s",
+	UnknownOption:          "Unknown option: %s",
+	InvalidArgument:        "Invalid argument: %s",
+	NoTasks:                "No open tasks",
+	SpellOff:               "Spell checking is not enabled, see :set spell",
+	NoMisspelled:           "No misspelled words",
+	NoSuggestions:          "Sorry, no suggestions",
+	NoWordUnderCursor:      "No word under cursor",
+	WordAdded:              "Word '%s' added to %s",
+	WordMarkedWrong:        "Word '%s' marked as wrong in %s",
 }
 
 //var msgColours = map[MsgType]lipgloss.TerminalColor{
@@ -42,7 +42,7 @@ var fullName = map[Mode]string{
 	VisualLine:   "visual_line",
 	VisualBlock:  "visual_block",
 	Replace:      "replace",
-	Operator:     "",
+	Operator:     "operator",
 	Command:      "command",
 	Search:       "/",
 	SearchPrompt: "search",
@@ -92,5 +92,5 @@ func (instance *ModeInstance) IsAnyVisual() bool {
 }
 
 func SupportsMotion() []Mode {
-	return []Mode{Normal, Visual, VisualLine, VisualBlock}
+	return []Mode{Normal, Visual, VisualLine, VisualBlock, Operator}
 }
@@ -0,0 +1,93 @@
+package theme
+
+import (
+	"fmt"
+	"image/color"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+var hexColour = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// colourNames maps the names of the basic terminal colours
+// to their ANSI numbers
+var colourNames = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+}
+
+// ParseColour parses a hex code like `#ffcb78`, an ANSI number
+// or the name of a basic terminal colour
+func ParseColour(s string) (color.Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if n, ok := colourNames[s]; ok {
+		s = n
+	}
+
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(s), nil
+	}
+
+	if hexColour.MatchString(s) {
+		return lipgloss.Color(s), nil
+	}
+
+	return nil, fmt.Errorf("invalid colour: %s", s)
+}
+
+// ParseStyle parses a comma separated list of a foreground colour,
+// `bg=colour` and text attributes, e.g. `#ffcb78,bg=black,bold`
+func ParseStyle(spec string) (lipgloss.Style, error) {
+	style := lipgloss.NewStyle()
+
+	for attr := range strings.SplitSeq(spec, ",") {
+		attr = strings.TrimSpace(attr)
+
+		switch attr {
+		case "":
+		case "bold":
+			style = style.Bold(true)
+		case "italic":
+			style = style.Italic(true)
+		case "underline":
+			style = style.Underline(true)
+		case "strikethrough":
+			style = style.Strikethrough(true)
+		case "reverse":
+			style = style.Reverse(true)
+		case "faint":
+			style = style.Faint(true)
+		default:
+			name, value, ok := strings.Cut(attr, "=")
+			if !ok {
+				name, value = "fg", attr
+			}
+
+			c, err := ParseColour(value)
+			if err != nil {
+				return style, err
+			}
+
+			switch name {
+			case "fg":
+				style = style.Foreground(c)
+			case "bg":
+				style = style.Background(c)
+			default:
+				return style, fmt.Errorf("invalid style: %s", attr)
+			}
+		}
+	}
+
+	return style, nil
+}
@@ -26,6 +26,8 @@ var (
 	ColourSearchHighlight        = lipgloss.Color("#ffcb78")
 	ColourSearchHighlightFocused = lipgloss.Color("#c59359")
 	ColourSearchFg               = lipgloss.Color("#333")
+
+	ColourMisspelled = lipgloss.Color("#d75a7d")
 )
 
 type icon struct {
@@ -143,6 +145,8 @@ func (theme *Theme) BorderStyle() lipgloss.Border {
 		return lipgloss.NormalBorder()
 	case "thick":
 		return lipgloss.ThickBorder()
+	case "block":
+		return lipgloss.BlockBorder()
 	case "rounded":
 		return lipgloss.RoundedBorder()
 	case "double":
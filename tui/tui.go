@@ -5,6 +5,7 @@ import (
 	"bellbird-notes/internal/interfaces"
 	"bellbird-notes/tui/components/application"
 	"bellbird-notes/tui/components/editor"
+	"bellbird-notes/tui/components/overlay"
 	"bellbird-notes/tui/keyinput"
 	"bellbird-notes/tui/message"
 	"bellbird-notes/tui/mode"
@@ -57,6 +58,8 @@ func InitialModel() *Model {
 		m.app.NotesList,
 		m.app.Editor,
 		m.app.BufferList,
+		m.app.TaskList,
+		m.app.SuggestionList,
 	}
 
 	m.vim.KeyMap = m.keyInput
@@ -81,7 +84,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// any key closes info overlays like the output of `:registers`
+		if m.app.InfoOverlayVisible() {
+			m.app.CloseInfoOverlay()
+			return m, nil
+		}
+
 		m.keyInput.AllowSequences = !m.app.StatusBar.Focused
+		// the buffer list uses numbers to select buffers
+		m.keyInput.AllowCount = !m.app.BufferList.Focused()
 		statusMsg := m.keyInput.HandleSequences(msg.Key())
 
 		if msg.Key().Code == 32 {
@@ -121,6 +132,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.app.NotesList.Update(msg)
 		m.app.Editor.Update(msg)
 		m.app.BufferList.Update(msg)
+		m.app.TaskList.Update(msg)
+		m.app.SuggestionList.Update(msg)
 
 		// Convert WindowSizeMsg to BubbleLayoutMsg.
 		return m, func() tea.Msg {
@@ -166,6 +179,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case editor.SwitchBufferMsg:
 		m.app.BufferList.Hide()
+		m.app.TaskList.Hide()
+		m.app.SuggestionList.Hide()
 
 	case shared.RefreshUiMsg:
 		m.RefreshUi()
@@ -173,14 +188,17 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// exit programme when `:q` is entered in command prompt
 	if m.app.ShouldQuit {
-		if err := m.app.State.Write(); err != nil {
+		if err := m.app.WriteState(); err != nil {
 			debug.LogErr(err)
 		}
 
 		return m, tea.Quit
 	}
 
-	if m.app.BufferList.Visible() {
+	if m.app.BufferList.Visible() ||
+		m.app.TaskList.Visible() ||
+		m.app.SuggestionList.Visible() {
+
 		m.vim.UnfocusAllColumns()
 	}
 
@@ -193,10 +211,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m Model) View() tea.View {
 	var view tea.View
 
+	dirTree := m.app.DirTree.Content()
+	notesList := m.app.NotesList.Content()
+
 	content := lipgloss.JoinVertical(lipgloss.Left,
 		lipgloss.JoinHorizontal(lipgloss.Top,
-			m.app.DirTree.Content(),
-			m.app.NotesList.Content(),
+			dirTree,
+			notesList,
 			m.app.Editor.Content(),
 		),
 		m.app.StatusBar.View(),
@@ -207,6 +228,24 @@ func (m Model) View() tea.View {
 		content = m.app.CurrentOverlay.String()
 	}
 
+	// the completions of insert mode are shown next to the cursor
+	if menu, x, y := m.app.Editor.CompletionMenu(); menu != "" {
+		ov := &overlay.Overlay{}
+		ov.SetBg(content)
+		ov.SetContent(menu)
+		ov.SetPosition(x+lipgloss.Width(dirTree)+lipgloss.Width(notesList), y)
+		content = ov.String()
+	}
+
+	// the completions of the command prompt are shown above the status bar
+	if menu := m.app.StatusBar.WildmenuView(); menu != "" {
+		ov := &overlay.Overlay{}
+		ov.SetBg(content)
+		ov.SetContent(menu)
+		ov.SetPosition(0, lipgloss.Height(content)-m.app.StatusBar.Height-1)
+		content = ov.String()
+	}
+
 	view.AltScreen = true
 	view.SetContent(content)
 
@@ -237,6 +276,9 @@ func (m *Model) componentsInit() {
 
 	m.keyInput.FetchKeyMap(true)
 	m.app.StatusBar.Commands = m.vim.CmdRegistry()
+	m.app.StatusBar.PromptCommands = m.vim.PromptRegistry()
+	m.app.StatusBar.Completer = m.vim.CompleteCmdline
+	m.vim.ApplySettings()
 }
 
 func (m *Model) RefreshUi() {
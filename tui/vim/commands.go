@@ -1,22 +1,41 @@
 package vim
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bellbird-notes/app"
+	"bellbird-notes/app/notes"
+	"bellbird-notes/app/utils"
 	"bellbird-notes/tui/components/statusbar"
 	"bellbird-notes/tui/message"
 	"bellbird-notes/tui/shared"
+	"bellbird-notes/tui/theme"
 )
 
 type Commands = statusbar.Commands
 
+// CmdRegistry returns the commands of the command prompt.
+// Commands with a backwards range ask whether to swap it
 func (vim *Vim) CmdRegistry() Commands {
+	cmds := vim.cmdRegistry()
+	for name, fn := range cmds {
+		cmds[name] = vim.confirmBackwardsRange(fn)
+	}
+	return cmds
+}
+
+func (vim *Vim) cmdRegistry() Commands {
 	return Commands{
-		message.CmdPrompt.Yes:       vim.statusBarConfirm,
-		message.CmdPrompt.No:        vim.statusBarCancel,
 		message.CmdPrompt.Quit:      vim.shouldQuit,
 		message.CmdPrompt.WriteBuf:  vim.writeBuffer,
 		message.CmdPrompt.WriteQuit: vim.writeBufferAndQuit,
 
 		message.CmdPrompt.Set:       vim.cmdSet,
+		message.CmdPrompt.SetLocal:  vim.cmdSetLocal,
+		"setl":                      vim.cmdSetLocal,
 		message.CmdPrompt.Open:      vim.cmdOpen,
 		message.CmdPrompt.Reload:    vim.cmdReload,
 		message.CmdPrompt.CheckTime: vim.cmdCheckTime,
@@ -28,6 +47,35 @@ func (vim *Vim) CmdRegistry() Commands {
 
 		message.CmdPrompt.New: vim.cmdNewScratchBuffer,
 
+		message.CmdPrompt.Registers: vim.listRegisters,
+		"reg":                       vim.listRegisters,
+		message.CmdPrompt.Marks:     vim.listMarks,
+		message.CmdPrompt.Jumps:     vim.listJumps,
+		message.CmdPrompt.Tasks:     vim.cmdTasks,
+		message.CmdPrompt.Match:     vim.cmdMatch,
+
+		message.CmdPrompt.Substitute: vim.substitute,
+		"substitute":                 vim.substitute,
+		message.CmdPrompt.Global:     vim.global,
+		"global":                     vim.global,
+		message.CmdPrompt.VGlobal:    vim.vglobal,
+		"vglobal":                    vim.vglobal,
+
+		message.CmdPrompt.Delete:   vim.exDelete,
+		"delete":                   vim.exDelete,
+		message.CmdPrompt.Yank:     vim.exYank,
+		"yank":                     vim.exYank,
+		message.CmdPrompt.Move:     vim.exMove,
+		"move":                     vim.exMove,
+		message.CmdPrompt.Copy:     vim.exCopy,
+		"co":                       vim.exCopy,
+		"copy":                     vim.exCopy,
+		message.CmdPrompt.Join:     vim.exJoin,
+		"join":                     vim.exJoin,
+		message.CmdPrompt.Normal:   vim.exNormal,
+		"norm":                     vim.exNormal,
+		message.CmdPrompt.GoToLine: vim.exGoToLine,
+
 		"ToggleFolders": func(_ ...string) StatusBarMsg {
 			return vim.app.DirTree.Toggle()
 		},
@@ -40,10 +88,12 @@ func (vim *Vim) CmdRegistry() Commands {
 	}
 }
 
-func (vim *Vim) cmdSetRegistry() Commands {
+// PromptRegistry returns the answers to a prompt of the status bar,
+// e.g. to the confirmation of a deletion
+func (vim *Vim) PromptRegistry() Commands {
 	return Commands{
-		"number":   vim.setNumber,
-		"nonumber": vim.setNoNumber,
+		message.CmdPrompt.Yes: vim.statusBarConfirm,
+		message.CmdPrompt.No:  vim.statusBarCancel,
 	}
 }
 
@@ -62,20 +112,29 @@ func (vim *Vim) cmdReloadRegistry() Commands {
 	}
 }
 
-func (vim *Vim) cmdSet(args ...string) StatusBarMsg {
-	fns := vim.cmdSetRegistry()
+// cmdOpen opens the config, the keymap or the note at the given
+// path relative to the notes directory
+func (vim *Vim) cmdOpen(args ...string) StatusBarMsg {
+	fns := vim.cmdOpenRegistry()
 	if fn, ok := fns[args[0]]; ok {
 		return fn()
 	}
-	return StatusBarMsg{}
+	return vim.openNote(args[0])
 }
 
-func (vim *Vim) cmdOpen(args ...string) StatusBarMsg {
-	fns := vim.cmdOpenRegistry()
-	if fn, ok := fns[args[0]]; ok {
-		return fn()
+// openNote opens the note at the given path relative to the notes directory
+func (vim *Vim) openNote(relPath string) StatusBarMsg {
+	root, _ := app.NotesRootDir()
+	path := filepath.Join(root, filepath.FromSlash(relPath))
+
+	if info, err := os.Stat(path); err != nil || info.IsDir() || !notes.IsNote(path) {
+		return StatusBarMsg{
+			Content: fmt.Sprintf(message.StatusBar.NoteNotFound, relPath),
+			Type:    message.Error,
+		}
 	}
-	return StatusBarMsg{}
+
+	return vim.app.Editor.OpenBuffer(path)
 }
 
 func (vim *Vim) cmdReload(args ...string) StatusBarMsg {
@@ -86,6 +145,22 @@ func (vim *Vim) cmdReload(args ...string) StatusBarMsg {
 	return StatusBarMsg{}
 }
 
+// cmdMatch highlights the matches of a pattern until the app is closed,
+// e.g. `:match red,bold /ABC-\d+/`. `:match none` or `:match` without
+// arguments removes these highlights
+func (vim *Vim) cmdMatch(args ...string) StatusBarMsg {
+	arg := ""
+	if len(args) > 0 {
+		arg = strings.TrimSpace(args[len(args)-1])
+	}
+
+	if arg == "" || arg == "none" {
+		return vim.app.Editor.ClearMatches()
+	}
+
+	return vim.app.Editor.AddMatch(arg)
+}
+
 func (vim *Vim) cmdCheckTime(_ ...string) StatusBarMsg {
 	vim.app.Editor.CheckTime()
 	return StatusBarMsg{}
@@ -155,16 +230,6 @@ func (vim *Vim) writeBufferAndQuit(_ ...string) StatusBarMsg {
 	return StatusBarMsg{}
 }
 
-func (vim *Vim) setNumber(_ ...string) StatusBarMsg {
-	vim.app.Editor.SetNumbers()
-	return StatusBarMsg{}
-}
-
-func (vim *Vim) setNoNumber(_ ...string) StatusBarMsg {
-	vim.app.Editor.SetNoNumbers()
-	return StatusBarMsg{}
-}
-
 func (vim *Vim) openDefaultKeyMap(_ ...string) StatusBarMsg {
 	statusMsg := vim.app.Editor.NewScratchBuffer(
 		"Default Keymap",
@@ -182,6 +247,99 @@ func (vim *Vim) listBuffers(_ ...string) StatusBarMsg {
 	return StatusBarMsg{}
 }
 
+// listRegisters shows the content of all non-empty registers in an overlay
+func (vim *Vim) listRegisters(_ ...string) StatusBarMsg {
+	termW, _ := theme.TerminalSize()
+	lines := []string{"Type Name Content"}
+
+	for _, reg := range vim.app.Editor.Registers.All() {
+		content := strings.ReplaceAll(reg.Content, "\n", "^J")
+		line := fmt.Sprintf("  %s  \"%c   %s", reg.Type(), reg.Name, content)
+		lines = append(lines, utils.TruncateText(line, termW*2/3-4))
+	}
+
+	vim.app.ShowInfoOverlay("Registers", lines)
+	return StatusBarMsg{}
+}
+
+// listMarks shows the marks of the current note and all global
+// marks in an overlay. Local marks show the text of their line,
+// global marks the note they have been set in
+func (vim *Vim) listMarks(_ ...string) StatusBarMsg {
+	termW, _ := theme.TerminalSize()
+	lines := []string{"Mark  Line   Col  File/Text"}
+
+	editor := vim.app.Editor
+	path := editor.CurrentBuffer.Path(false)
+	content := editor.Textarea.Val()
+
+	for _, mark := range editor.Marks.All(path) {
+		text := ""
+
+		switch {
+		case mark.Path != path:
+			text = utils.RelativePath(mark.Path, true)
+		case mark.Pos.Row < len(content):
+			text = strings.TrimSpace(string(content[mark.Pos.Row]))
+		}
+
+		line := fmt.Sprintf(
+			"  %c  %5d  %4d  %s",
+			mark.Name,
+			mark.Pos.Row+1,
+			mark.Pos.ColumnOffset,
+			text,
+		)
+		lines = append(lines, utils.TruncateText(line, termW*2/3-4))
+	}
+
+	vim.app.ShowInfoOverlay("Marks", lines)
+	return StatusBarMsg{}
+}
+
+// listJumps shows the jump list in an overlay.
+// The jump we're currently at is marked with `>`
+func (vim *Vim) listJumps(_ ...string) StatusBarMsg {
+	termW, _ := theme.TerminalSize()
+	lines := []string{"   Line   Col  File/Text"}
+
+	editor := vim.app.Editor
+	path := editor.CurrentBuffer.Path(false)
+	content := editor.Textarea.Val()
+
+	for i, jump := range editor.Jumps.All() {
+		marker := " "
+		if i == editor.Jumps.Index() {
+			marker = ">"
+		}
+
+		text := ""
+
+		switch {
+		case jump.Path != path:
+			text = utils.RelativePath(jump.Path, true)
+		case jump.Pos.Row < len(content):
+			text = strings.TrimSpace(string(content[jump.Pos.Row]))
+		}
+
+		line := fmt.Sprintf(
+			" %s %5d  %4d  %s",
+			marker,
+			jump.Pos.Row+1,
+			jump.Pos.ColumnOffset,
+			text,
+		)
+		lines = append(lines, utils.TruncateText(line, termW*2/3-4))
+	}
+
+	if editor.Jumps.Index() >= len(editor.Jumps.All()) {
+		lines = append(lines, " >")
+	}
+
+	vim.app.ShowInfoOverlay("Jumps", lines)
+	return StatusBarMsg{}
+}
+
 func (vim *Vim) cmdNewScratchBuffer(_ ...string) StatusBarMsg {
 	statusMsg := vim.app.Editor.NewScratchBuffer("Scratch", "")
 	vim.app.Editor.Textarea.SetValue("")
@@ -0,0 +1,62 @@
+package vim
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/message"
+)
+
+func TestYankCommand(t *testing.T) {
+	vim, app := createTestApp(t, "a\nb\nc")
+
+	// `:y` is a command of its own
+	vim.CmdRegistry()[message.CmdPrompt.Yank]("1,2", "")
+
+	if reg, _ := app.Editor.Registers.Get(); reg.Content != "a\nb\n" {
+		t.Fatalf("Expected yanked lines in register, got %q", reg.Content)
+	}
+
+	// confirming a prompt without a pending deletion doesn't yank
+	vim.PromptRegistry()[message.CmdPrompt.Yes]("3", "")
+
+	if reg, _ := app.Editor.Registers.Get(); reg.Content != "a\nb\n" {
+		t.Fatalf("Expected the register to be unchanged, got %q", reg.Content)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+	ta := &app.Editor.Textarea
+
+	configured := len(ta.HighlightRules())
+
+	if msg := vim.cmdMatch("", " red,bold /ABC-\\d+/"); msg.Type == message.Error {
+		t.Fatalf("expected the match to be added, got %q", msg.Content)
+	}
+
+	rules := ta.HighlightRules()
+	if len(rules) != configured+1 {
+		t.Fatalf("expected %d rules, got %d", configured+1, len(rules))
+	}
+
+	if re := rules[len(rules)-1].Regex; !re.MatchString("see ABC-123") {
+		t.Errorf("expected %s to match the ticket", re)
+	}
+
+	for _, arg := range []string{" nocolour /x/", " red /(/", " red"} {
+		if msg := vim.cmdMatch("", arg); msg.Type != message.Error {
+			t.Errorf("%q: expected an error", arg)
+		}
+	}
+
+	// the matches are kept when the config is reloaded
+	app.Editor.RefreshTextAreaStyles()
+	if n := len(ta.HighlightRules()); n != configured+1 {
+		t.Errorf("expected %d rules after reloading, got %d", configured+1, n)
+	}
+
+	vim.cmdMatch("none")
+	if n := len(ta.HighlightRules()); n != configured {
+		t.Errorf("expected the matches to be removed, got %d rules", n)
+	}
+}
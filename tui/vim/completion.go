@@ -0,0 +1,154 @@
+package vim
+
+import (
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"bellbird-notes/app"
+	"bellbird-notes/app/config"
+	"bellbird-notes/app/notes"
+	"bellbird-notes/tui/components/statusbar"
+)
+
+// argCmd matches the commands whose arguments are completed
+var argCmd = regexp.MustCompile(`^\s*(set|setl|setlocal|open|reload)\s+`)
+
+// CompleteCmdline completes the last word of the command line.
+// The first word completes to command names, the arguments of `:set` to
+// setting names and the values of enum settings, the arguments of
+// `:open` and `:reload` to the names of their sub-commands. `:open`
+// also completes the paths of notes and folders within the notes
+// directory. Returns the offset of the word and the candidates
+func (vim *Vim) CompleteCmdline(cmdline string) (int, []string) {
+	if loc := argCmd.FindStringSubmatchIndex(cmdline); loc != nil {
+		cmd := cmdline[loc[2]:loc[3]]
+		start := loc[1]
+		word := cmdline[start:]
+
+		switch cmd {
+		case "set", "setl", "setlocal":
+			// every word is an option of its own
+			start += strings.LastIndex(word, " ") + 1
+			return start, settingCompletions(cmdline[start:])
+
+		case "reload":
+			return start, completions(vim.cmdReloadRegistry(), word)
+
+		case "open":
+			candidates := completions(vim.cmdOpenRegistry(), word)
+			return start, append(candidates, notePaths(word)...)
+		}
+	}
+
+	_, name, rest, ok := statusbar.ParseRangeCmd(cmdline)
+	if rest != "" || (!ok && cmdline != "") {
+		return 0, nil
+	}
+
+	candidates := []string{}
+
+	for _, cmd := range completions(vim.CmdRegistry(), name) {
+		// only commands that are valid names, e.g. not `%bd`
+		if _, cmdName, cmdRest, _ := statusbar.ParseRangeCmd(cmd); cmdName == cmd && cmdRest == "" {
+			candidates = append(candidates, cmd)
+		}
+	}
+
+	return len(cmdline) - len(name), candidates
+}
+
+// completions returns the sorted names of the commands that
+// start with the given prefix
+func completions(cmds Commands, prefix string) []string {
+	names := []string{}
+
+	for _, name := range slices.Sorted(maps.Keys(cmds)) {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// settingCompletions returns the sorted setting names that start with
+// the given prefix. Boolean settings are also completed with `no`.
+// After `=` the values of enum settings are completed
+func settingCompletions(prefix string) []string {
+	candidates := []string{}
+
+	if name, value, ok := strings.Cut(prefix, "="); ok {
+		s, found := config.LookupSetting(name)
+		if !found {
+			return nil
+		}
+
+		for _, v := range s.Values {
+			if strings.HasPrefix(v, value) {
+				candidates = append(candidates, name+"="+v)
+			}
+		}
+
+		return candidates
+	}
+
+	names := []string{"all"}
+	for _, s := range config.Settings {
+		names = append(names, s.Name)
+
+		if s.Type == config.BoolSetting {
+			names = append(names, "no"+s.Name)
+		}
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			candidates = append(candidates, name)
+		}
+	}
+
+	return candidates
+}
+
+// notePaths returns the notes and folders within the notes directory
+// whose relative path starts with the given prefix.
+// Folders end with a `/` so that their content can be completed next
+func notePaths(prefix string) []string {
+	root, err := app.NotesRootDir()
+	if err != nil {
+		return nil
+	}
+
+	dir, base := path.Split(filepath.ToSlash(prefix))
+
+	entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(dir)))
+	if err != nil {
+		return nil
+	}
+
+	paths := []string{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if strings.HasPrefix(name, ".") || !strings.HasPrefix(name, base) {
+			continue
+		}
+
+		switch {
+		case entry.IsDir():
+			paths = append(paths, dir+name+"/")
+		case notes.IsNote(name):
+			paths = append(paths, dir+name)
+		}
+	}
+
+	return paths
+}
@@ -0,0 +1,32 @@
+package vim
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCmdlineCompletion(t *testing.T) {
+	vim, _ := createTestApp(t, testNote)
+
+	tests := []struct {
+		cmdline    string
+		wantStart  int
+		wantResult []string
+	}{
+		{"subs", 0, []string{"substitute"}},
+		{"%subs", 1, []string{"substitute"}},
+		{"set nu", 4, []string{"number"}},
+		{"set number nonu", 11, []string{"nonumber"}},
+		{"reload k", 7, []string{"keymap"}},
+		{"open def", 5, []string{"defaultkeymap"}},
+		{"s/a/b", 0, nil},
+	}
+
+	for _, tt := range tests {
+		start, candidates := vim.CompleteCmdline(tt.cmdline)
+		if start != tt.wantStart || !slices.Equal(candidates, tt.wantResult) {
+			t.Errorf("%q: expected %d %v, got %d %v",
+				tt.cmdline, tt.wantStart, tt.wantResult, start, candidates)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package vim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	ki "bellbird-notes/tui/keyinput"
+	"bellbird-notes/tui/message"
+	"bellbird-notes/tui/mode"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// exArgs returns the range and the arguments of a command called
+// with or without a line range
+func exArgs(args []string) (string, string) {
+	if len(args) == 2 {
+		return args[0], args[1]
+	}
+	return "", ""
+}
+
+// invalidRange is returned by commands with an invalid line range
+var invalidRange = StatusBarMsg{
+	Content: message.StatusBar.InvalidRange,
+	Type:    message.Error,
+}
+
+// exRange resolves the line range of a command like `:d` whose arguments
+// may end with a count, e.g. `:d a 3`. With a count the range starts at
+// its last line and spans `count` lines.
+// withRegister allows a register name as the first argument, which is
+// selected for the next yank or delete.
+// Returns false if the range or the arguments are invalid
+func (vim *Vim) exRange(rangeSpec string, arg string, withRegister bool) (int, int, bool) {
+	start, end, ok := vim.lineRange(rangeSpec)
+	if !ok {
+		return 0, 0, false
+	}
+
+	fields := strings.Fields(arg)
+	register := rune(0)
+
+	if withRegister && len(fields) > 0 {
+		if name := []rune(fields[0]); len(name) == 1 && !unicode.IsDigit(name[0]) {
+			register = name[0]
+			fields = fields[1:]
+		}
+	}
+
+	switch len(fields) {
+	case 0:
+	case 1:
+		count, err := strconv.Atoi(fields[0])
+		if err != nil || count < 1 {
+			return 0, 0, false
+		}
+
+		last := vim.app.Editor.Textarea.LineCount() - 1
+		start, end = end, min(end+count-1, last)
+	default:
+		return 0, 0, false
+	}
+
+	if register != 0 && !vim.app.Editor.Registers.Select(register) {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
+
+// exDelete deletes a range of lines into a register, e.g. `:.,+2d a`
+func (vim *Vim) exDelete(args ...string) StatusBarMsg {
+	rangeSpec, arg := exArgs(args)
+
+	start, end, ok := vim.exRange(rangeSpec, arg, true)
+	if !ok {
+		return invalidRange
+	}
+
+	return vim.app.Editor.DeleteRows(start, end)
+}
+
+// exYank copies a range of lines into a register, e.g. `:%y A`
+func (vim *Vim) exYank(args ...string) StatusBarMsg {
+	rangeSpec, arg := exArgs(args)
+
+	start, end, ok := vim.exRange(rangeSpec, arg, true)
+	if !ok {
+		return invalidRange
+	}
+
+	editor := vim.app.Editor
+	return editor.Yank(editor.Textarea.LinesStr(start, end))
+}
+
+// exDestination resolves the address lines are moved or copied below.
+// `0` is the address above the first line
+func (vim *Vim) exDestination(arg string) (int, bool) {
+	if arg = strings.TrimSpace(arg); arg == "0" {
+		return -1, true
+	}
+
+	return vim.lineAddress(arg, vim.app.Editor.Textarea.Line())
+}
+
+// exMove moves a range of lines below the line of the given address,
+// e.g. `:m$` moves the current line to the end and `:m0` to the top
+func (vim *Vim) exMove(args ...string) StatusBarMsg {
+	rangeSpec, arg := exArgs(args)
+
+	start, end, ok := vim.lineRange(rangeSpec)
+	if !ok {
+		return invalidRange
+	}
+
+	dest, ok := vim.exDestination(arg)
+	if !ok {
+		return invalidRange
+	}
+
+	return vim.app.Editor.MoveRows(start, end, dest)
+}
+
+// exCopy copies a range of lines below the line of the given address,
+// e.g. `:t.` duplicates the current line
+func (vim *Vim) exCopy(args ...string) StatusBarMsg {
+	rangeSpec, arg := exArgs(args)
+
+	start, end, ok := vim.lineRange(rangeSpec)
+	if !ok {
+		return invalidRange
+	}
+
+	dest, ok := vim.exDestination(arg)
+	if !ok {
+		return invalidRange
+	}
+
+	return vim.app.Editor.CopyRows(start, end, dest)
+}
+
+// exJoin joins a range of lines like `J`, `:j!` joins them without
+// changing any white space. A single line is joined with the next line
+func (vim *Vim) exJoin(args ...string) StatusBarMsg {
+	rangeSpec, arg := exArgs(args)
+
+	spaces := !strings.HasPrefix(arg, "!")
+	arg = strings.TrimPrefix(arg, "!")
+
+	start, end, ok := vim.exRange(rangeSpec, arg, false)
+	if !ok {
+		return invalidRange
+	}
+
+	return vim.app.Editor.JoinRows(start, end, spaces)
+}
+
+// exGoToLine moves the cursor to the last line of the range,
+// e.g. `:10` or `:$`
+func (vim *Vim) exGoToLine(args ...string) StatusBarMsg {
+	rangeSpec, arg := exArgs(args)
+
+	_, end, ok := vim.lineRange(rangeSpec)
+	if !ok {
+		return invalidRange
+	}
+
+	if strings.TrimSpace(arg) != "" {
+		return notACommand(rangeSpec + arg)
+	}
+
+	return vim.app.Editor.GoToLine(end + 1)
+}
+
+// exNormal executes the given keys in normal mode as if they were typed,
+// e.g. `:normal A;`. Key names like `<esc>` are supported.
+// With a line range the keys are executed at the beginning of every line
+// of the range. Unfinished commands are cancelled like with `esc`
+func (vim *Vim) exNormal(args ...string) StatusBarMsg {
+	rangeSpec, arg := exArgs(args)
+
+	arg = strings.TrimPrefix(arg, "!")
+	keys := ki.DecodeKeys(strings.TrimPrefix(arg, " "))
+
+	if len(keys) == 0 {
+		return StatusBarMsg{}
+	}
+
+	if strings.TrimSpace(rangeSpec) == "" {
+		return vim.normal(keys)
+	}
+
+	start, end, ok := vim.lineRange(rangeSpec)
+	if !ok {
+		return invalidRange
+	}
+
+	rows := make([]int, 0, end-start+1)
+	for row := start; row <= end; row++ {
+		rows = append(rows, row)
+	}
+
+	return vim.app.Editor.RunOnLines(rows, func() StatusBarMsg {
+		return vim.normal(keys)
+	})
+}
+
+// normal replays the keys in the editor's normal mode. The command
+// prompt is put aside while the keys are replayed
+func (vim *Vim) normal(keys []tea.Key) StatusBarMsg {
+	app := vim.app
+	sb := app.StatusBar
+
+	focused, appMode := sb.Focused, app.Mode.Current
+	sb.Focused = false
+	app.Mode.Current = mode.Normal
+	app.Editor.Mode.Current = mode.Normal
+
+	defer func() {
+		sb.Focused = focused
+		app.Mode.Current = appMode
+	}()
+
+	vim.KeyMap.Replay(keys, vim.handleKey)
+
+	if app.Editor.Mode.Current != mode.Normal || vim.KeyMap.KeySequence != "" {
+		vim.KeyMap.Replay([]tea.Key{{Code: tea.KeyEscape}}, vim.handleKey)
+	}
+
+	return StatusBarMsg{}
+}
+
+// notACommand is returned for unknown commands
+func notACommand(cmd string) StatusBarMsg {
+	return StatusBarMsg{
+		Content: fmt.Sprintf(message.StatusBar.NotACommand, cmd),
+		Type:    message.Error,
+	}
+}
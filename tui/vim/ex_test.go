@@ -0,0 +1,100 @@
+package vim
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/message"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestExRanges(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	ta := &app.Editor.Textarea
+	original := "TEST1\nTest2\nTest3\ntest4\ntes5t"
+
+	tests := []struct {
+		name string
+		cmd  func() StatusBarMsg
+		want string
+	}{
+		{
+			name: "delete",
+			cmd:  func() StatusBarMsg { return vim.exDelete("2,3", "") },
+			want: "TEST1\ntest4\ntes5t",
+		},
+		{
+			name: "delete with register and count",
+			cmd:  func() StatusBarMsg { return vim.exDelete("1", "a 2") },
+			want: "Test3\ntest4\ntes5t",
+		},
+		{
+			name: "copy",
+			cmd:  func() StatusBarMsg { return vim.exCopy("1", "$") },
+			want: original + "\nTEST1",
+		},
+		{
+			name: "move to the top",
+			cmd:  func() StatusBarMsg { return vim.exMove("4,$", "0") },
+			want: "test4\ntes5t\nTEST1\nTest2\nTest3",
+		},
+		{
+			name: "move down",
+			cmd:  func() StatusBarMsg { return vim.exMove("1,2", "4") },
+			want: "Test3\ntest4\nTEST1\nTest2\ntes5t",
+		},
+		{
+			name: "join",
+			cmd:  func() StatusBarMsg { return vim.exJoin("1;+2", "") },
+			want: "TEST1 Test2 Test3\ntest4\ntes5t",
+		},
+		{
+			name: "join without spaces",
+			cmd:  func() StatusBarMsg { return vim.exJoin("$-1", "!") },
+			want: "TEST1\nTest2\nTest3\ntest4tes5t",
+		},
+	}
+
+	for _, tt := range tests {
+		ta.MoveCursor(0, 0, 0)
+		tt.cmd()
+
+		if got := ta.Value(); got != tt.want {
+			t.Fatalf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+
+		typeKeys(app, textKeys("u")...)
+
+		if got := ta.Value(); got != original {
+			t.Fatalf("%s: expected changes to be undone, got %q", tt.name, got)
+		}
+	}
+
+	if reg, _ := app.Editor.Registers.Register('a'); reg.Content != "TEST1\nTest2\n" {
+		t.Fatalf("Expected deleted lines in register a, got %q", reg.Content)
+	}
+
+	if msg := vim.exMove("1,3", "2"); msg.Type != message.Error {
+		t.Fatalf("Expected an error when moving lines into themselves")
+	}
+
+	vim.exGoToLine("$-1", "")
+	if row := ta.Line(); row != 3 {
+		t.Fatalf("Expected cursor on row 3, got %d", row)
+	}
+
+	// the command prompt of visual mode starts with the selected lines
+	typeKeys(app, textKeys("ggjVj:")...)
+
+	if got := app.StatusBar.Prompt.Value(); got != "'<,'>" {
+		t.Fatalf("Expected the prompt to contain the visual range, got %q", got)
+	}
+
+	typeKeys(app, tea.Key{Code: tea.KeyEscape})
+	vim.exDelete("'<,'>", "")
+
+	if got := ta.Value(); got != "TEST1\ntest4\ntes5t" {
+		t.Fatalf("Expected the selected lines to be deleted, got %q", got)
+	}
+}
@@ -0,0 +1,167 @@
+package vim
+
+import (
+	"fmt"
+	"strings"
+
+	"bellbird-notes/tui/components/statusbar"
+	"bellbird-notes/tui/components/textarea"
+	"bellbird-notes/tui/message"
+)
+
+// global executes a command on every line within a range that matches
+// a pattern, e.g. `:g/TODO/d` or `:g/^#/normal A!`.
+// The range defaults to the whole note. All changes are a single undo
+// step. `:g!` executes the command on the lines that don't match.
+// Without a command the matching lines are listed
+func (vim *Vim) global(args ...string) StatusBarMsg {
+	rangeSpec, cmd := exArgs(args)
+
+	if strings.HasPrefix(cmd, "!") {
+		return vim.runGlobal(rangeSpec, cmd[1:], true)
+	}
+
+	return vim.runGlobal(rangeSpec, cmd, false)
+}
+
+// vglobal executes a command on every line that doesn't match
+// a pattern, e.g. `:v/\S/d` deletes all blank lines
+func (vim *Vim) vglobal(args ...string) StatusBarMsg {
+	rangeSpec, cmd := exArgs(args)
+	return vim.runGlobal(rangeSpec, cmd, true)
+}
+
+func (vim *Vim) runGlobal(rangeSpec string, cmd string, invert bool) StatusBarMsg {
+	editor := vim.app.Editor
+
+	if strings.TrimSpace(rangeSpec) == "" {
+		rangeSpec = "%"
+	}
+
+	startRow, endRow, ok := vim.lineRange(rangeSpec)
+	if !ok {
+		return invalidRange
+	}
+
+	cmd = strings.TrimSpace(cmd)
+	if cmd == "" {
+		return StatusBarMsg{
+			Content: fmt.Sprintf(message.StatusBar.InvalidPattern, cmd),
+			Type:    message.Error,
+		}
+	}
+
+	pattern, subCmd, ok := splitPattern(cmd)
+	if !ok {
+		return StatusBarMsg{
+			Content: fmt.Sprintf(message.StatusBar.InvalidPattern, cmd),
+			Type:    message.Error,
+		}
+	}
+
+	if pattern == "" {
+		pattern = editor.LastPattern()
+	}
+
+	search := textarea.Search{
+		Query:      pattern,
+		IgnoreCase: editor.SearchIgnoreCase(),
+		SmartCase:  editor.SearchSmartCase(),
+		Regex:      true,
+	}
+
+	re, err := search.Regexp()
+	if pattern == "" || err != nil {
+		return StatusBarMsg{
+			Content: fmt.Sprintf(message.StatusBar.InvalidPattern, pattern),
+			Type:    message.Error,
+		}
+	}
+
+	// commands like `:s//x/` use the pattern of `:g`
+	editor.SetLastPattern(pattern)
+
+	rows := []int{}
+	for row, line := range editor.Textarea.Val()[startRow : endRow+1] {
+		if re.MatchString(string(line)) != invert {
+			rows = append(rows, startRow+row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return StatusBarMsg{
+			Content: fmt.Sprintf(message.StatusBar.PatternNotFound, pattern),
+			Type:    message.Error,
+		}
+	}
+
+	subCmd = strings.TrimSpace(subCmd)
+	if subCmd == "" {
+		return vim.listLines(rows)
+	}
+
+	subRange, name, subArgs, ok := statusbar.ParseRangeCmd(subCmd)
+	if name == "" {
+		name = message.CmdPrompt.GoToLine
+	}
+
+	// backwards ranges are swapped without asking like in vim
+	fn, found := vim.cmdRegistry()[name]
+
+	if !ok || !found {
+		return notACommand(subCmd)
+	}
+
+	return editor.RunOnLines(rows, func() StatusBarMsg {
+		return fn(subRange, subArgs)
+	})
+}
+
+// splitPattern splits the arguments of `:g` like `/pattern/cmd` at the
+// delimiter, which is the first character. Delimiters are the same as
+// the ones of `:s`, escaped delimiters are part of the pattern.
+// Returns false if the delimiter is invalid
+func splitPattern(cmd string) (string, string, bool) {
+	runes := []rune(cmd)
+	delim := runes[0]
+
+	if !validDelimiter(delim) {
+		return "", "", false
+	}
+
+	var pattern strings.Builder
+
+	for i := 1; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\\' && i+1 < len(runes) && runes[i+1] == delim:
+			pattern.WriteRune(delim)
+			i++
+		case r == '\\' && i+1 < len(runes):
+			pattern.WriteRune(r)
+			pattern.WriteRune(runes[i+1])
+			i++
+		case r == delim:
+			return pattern.String(), string(runes[i+1:]), true
+		default:
+			pattern.WriteRune(r)
+		}
+	}
+
+	// the closing delimiter is optional
+	return pattern.String(), "", true
+}
+
+// listLines shows the given rows and their text in an overlay
+func (vim *Vim) listLines(rows []int) StatusBarMsg {
+	lines := []string{}
+	content := vim.app.Editor.Textarea.Val()
+
+	for _, row := range rows {
+		lines = append(lines, fmt.Sprintf("%4d %s", row+1, string(content[row])))
+	}
+
+	vim.app.ShowInfoOverlay("Lines", lines)
+	return StatusBarMsg{}
+}
@@ -0,0 +1,113 @@
+package vim
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/message"
+)
+
+func TestGlobalIdenticalLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		cmd     string
+		vglobal bool
+		want    string
+	}{
+		{"delete identical lines", "a\na\na", "/a/d", false, ""},
+		{"delete in normal mode", "x\nx\nx\ny", "/x/normal dd", false, "y"},
+		{"delete empty lines", "a\n\n\nb\n\n", "/^$/d", false, "a\nb"},
+		{"delete blank lines", "a\n \n\t\nb", `/\S/d`, true, "a\nb"},
+		{"move identical lines", "a\nb\na\nb", "/a/m$", false, "b\nb\na\na"},
+		{"join identical lines", "a\na\na\na", "/a/j", false, "a a\na a"},
+	}
+
+	for _, tt := range tests {
+		vim, app := createTestApp(t, tt.content)
+
+		if tt.vglobal {
+			vim.vglobal("", tt.cmd)
+		} else {
+			vim.global("", tt.cmd)
+		}
+
+		if got := app.Editor.Textarea.Value(); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestGlobalCommand(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	ta := &app.Editor.Textarea
+	original := "TEST1\nTest2\nTest3\ntest4\ntes5t"
+
+	tests := []struct {
+		name string
+		cmd  func() StatusBarMsg
+		want string
+	}{
+		{
+			name: "reverse lines",
+			cmd:  func() StatusBarMsg { return vim.global("", `/\d$/m0`) },
+			want: "test4\nTest3\nTest2\nTEST1\ntes5t",
+		},
+		{
+			name: "delete non-matching lines",
+			cmd:  func() StatusBarMsg { return vim.vglobal("", "/5/d") },
+			want: "tes5t",
+		},
+		{
+			name: "normal",
+			cmd:  func() StatusBarMsg { return vim.global("", "/est/normal A!") },
+			want: "TEST1!\nTest2!\nTest3!\ntest4!\ntes5t",
+		},
+		{
+			name: "substitute with the pattern of :g",
+			cmd:  func() StatusBarMsg { return vim.global("2,4", "/t/s//_/g") },
+			want: "TEST1\nTes_2\nTes_3\n_es_4\ntes5t",
+		},
+	}
+
+	for _, tt := range tests {
+		tt.cmd()
+
+		if got := ta.Value(); got != tt.want {
+			t.Fatalf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+
+		// all changes are undone at once
+		typeKeys(app, textKeys("u")...)
+
+		if got := ta.Value(); got != original {
+			t.Fatalf("%s: expected changes to be undone, got %q", tt.name, got)
+		}
+	}
+
+	if msg := vim.global("", "/nothing/d"); msg.Type != message.Error {
+		t.Fatalf("Expected an error if the pattern is not found")
+	}
+}
+
+func TestSplitPattern(t *testing.T) {
+	tests := []struct {
+		cmd, pattern, rest string
+		ok                 bool
+	}{
+		{"/a/d", "a", "d", true},
+		{`,a\,b,s/x/y/`, "a,b", "s/x/y/", true},
+		{`/\d/d`, `\d`, "d", true},
+		{"/a", "a", "", true},
+		{"adbd", "", "", false},
+	}
+
+	for _, tt := range tests {
+		pattern, rest, ok := splitPattern(tt.cmd)
+
+		if ok != tt.ok || pattern != tt.pattern || rest != tt.rest {
+			t.Errorf("%q: expected %q %q %v, got %q %q %v",
+				tt.cmd, tt.pattern, tt.rest, tt.ok, pattern, rest, ok)
+		}
+	}
+}
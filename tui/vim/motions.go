@@ -1,10 +1,15 @@
 package vim
 
 import (
+	"fmt"
+	"slices"
 	"strconv"
 	"unicode"
 
+	tea "github.com/charmbracelet/bubbletea/v2"
+
 	"bellbird-notes/app/config"
+	"bellbird-notes/app/registers"
 	"bellbird-notes/app/utils"
 	"bellbird-notes/internal/interfaces"
 	"bellbird-notes/tui/components/editor"
@@ -22,10 +27,12 @@ func (vim *Vim) FnRegistry() ki.MotionRegistry {
 		// Movement/Navigation
 		"LineDown":       vim.lineDown,
 		"LineUp":         vim.lineUp,
-		"DownHalfPage":   bind(vim.app.Editor.DownHalfPage),
-		"UpHalfPage":     bind(vim.app.Editor.UpHalfPage),
-		"CharacterLeft":  bind(vim.app.Editor.MoveCharacterLeft),
-		"CharacterRight": bind(vim.app.Editor.MoveCharacterRight),
+		"DownHalfPage":   repeat(vim.app.Editor.DownHalfPage),
+		"UpHalfPage":     repeat(vim.app.Editor.UpHalfPage),
+		"ScrollLeft":     repeat(vim.app.Editor.ScrollLeft),
+		"ScrollRight":    repeat(vim.app.Editor.ScrollRight),
+		"CharacterLeft":  repeat(vim.app.Editor.MoveCharacterLeft),
+		"CharacterRight": repeat(vim.app.Editor.MoveCharacterRight),
 		"GoToTop":        vim.goToTop,
 		"GoToBottom":     vim.goToBottom,
 
@@ -56,6 +63,10 @@ func (vim *Vim) FnRegistry() ki.MotionRegistry {
 		"EnterCommand":         vim.enterCmdMode,
 		"ShowBufferList":       vim.showBufferList,
 		"CloseBufferList":      vim.closeBufferList,
+		"ShowTasks":            vim.showTasks,
+		"CloseTaskList":        vim.closeTaskList,
+		"SuggestSpelling":      vim.suggestSpelling,
+		"CloseSuggestionList":  vim.closeSuggestionList,
 		"ConfirmAction":        vim.confirmAction,
 		"CancelAction":         vim.cancelAction,
 		"CloseNote":            bind(vim.app.Editor.CloseCurrentBuffer),
@@ -73,8 +84,8 @@ func (vim *Vim) FnRegistry() ki.MotionRegistry {
 		"ToggleVisualLine":  vim.toggleVisualLine,
 		"ToggleVisualBlock": vim.toggleVisualBlock,
 
-		"Undo": bind(vim.app.Editor.Undo),
-		"Redo": bind(vim.app.Editor.Redo),
+		"Undo": repeat(vim.app.Editor.Undo),
+		"Redo": repeat(vim.app.Editor.Redo),
 
 		"InsertBefore":     vim.enterInsertMode,
 		"InsertAfter":      bind(vim.app.Editor.InsertAfter),
@@ -82,20 +93,36 @@ func (vim *Vim) FnRegistry() ki.MotionRegistry {
 		"InsertAbove":      vim.insertAbove,
 		"InsertBeforeLine": bind(vim.app.Editor.InsertLineStart),
 		"InsertAfterLine":  bind(vim.app.Editor.InsertLineEnd),
+		"InsertBlock":      vim.insertBlock,
 
 		"SelectWord":             vim.selectWord,
+		"SelectSentence":         vim.selectSentence,
+		"SelectParagraph":        vim.selectParagraph,
+		"SelectQuoted":           vim.selectQuoted,
+		"SelectBrackets":         vim.selectBrackets,
 		"NextWord":               vim.nextWord,
 		"PrevWord":               vim.prevWord,
+		"NextParagraph":          vim.nextParagraph,
+		"PrevParagraph":          repeat(vim.app.Editor.ParagraphBack),
 		"FindCharacter":          vim.findCharacter,
 		"FindWordUnderCursor":    vim.findWordUnderCursor,
 		"Find":                   vim.find,
 		"MoveToMatch":            vim.moveToMatch,
+		"NextMisspelled":         vim.nextMisspelled,
+		"AddSpellWord":           vim.addSpellWord,
 		"GoToFirstNonWhiteSpace": bind(vim.app.Editor.GoToInputStart),
 		"GoToLineStart":          bind(vim.app.Editor.GoToLineStart),
-		"GoToLineEnd":            bind(vim.app.Editor.GoToLineEnd),
-		"MergeLines":             bind(vim.app.Editor.MergeLineBelow),
-
-		"DeleteLine":             bind(vim.app.Editor.DeleteLine),
+		"GoToLineEnd":            vim.goToLineEnd,
+		"MergeLines":             vim.mergeLines,
+		"RepeatChange":           vim.repeatChange,
+		"RecordMacro":            vim.recordMacro,
+		"SetMark":                vim.setMark,
+		"JumpToMark":             vim.jumpToMark,
+		"JumpBack":               vim.jumpBack,
+		"JumpForward":            vim.jumpForward,
+		"PlayMacro":              vim.playMacro,
+
+		"DeleteLine":             vim.deleteLine,
 		"DeleteWord":             vim.deleteWord,
 		"DeleteAfterCursor":      vim.deleteAfterCursor,
 		"DeleteSelection":        vim.deleteSelection,
@@ -107,17 +134,24 @@ func (vim *Vim) FnRegistry() ki.MotionRegistry {
 		"ChangeLine":        vim.changeLine,
 		"ChangeWord":        vim.changeWord,
 
-		"YankAfterCursor":   bind(vim.app.Editor.YankAfterCursor),
+		"YankAfterCursor":   vim.yankAfterCursor,
 		"YankSelection":     vim.yankSelection,
-		"YankLine":          bind(vim.app.Editor.YankLine),
+		"YankLine":          vim.yankLine,
 		"YankWord":          vim.yankWord,
 		"Paste":             vim.paste,
 		"ChangeToLowerCase": vim.changeToLowerCase,
 		"ChangeToUpperCase": vim.changeToUpperCase,
+		"ShiftRight":        vim.shiftSelection(false),
+		"ShiftLeft":         vim.shiftSelection(true),
+		"MakeList":          vim.makeList,
+		"FormatSelection":   vim.formatSelection,
+		"ToggleTask":        bind(vim.app.Editor.ToggleTask),
 
 		// Command
 		"CmdHistoryBack":    bind(vim.app.StatusBar.PromptHistoryBack),
 		"CmdHistoryForward": bind(vim.app.StatusBar.PromptHistoryForward),
+		"CmdCompleteNext":   bind(vim.app.StatusBar.CompleteNext),
+		"CmdCompletePrev":   bind(vim.app.StatusBar.CompletePrev),
 
 		// Search
 		"CmdSearchHistoryBack":    bind(vim.app.StatusBar.SearchHistoryBack),
@@ -133,6 +167,20 @@ func bind(fn func() StatusBarMsg) ki.Motion {
 	}
 }
 
+// repeat is like bind but executes fn as often as the
+// count prefix of the key binding demands
+func repeat(fn func() StatusBarMsg) ki.Motion {
+	return func(opts ki.Options) func() StatusBarMsg {
+		return func() StatusBarMsg {
+			msg := StatusBarMsg{}
+			for range opts.Count() {
+				msg = fn()
+			}
+			return msg
+		}
+	}
+}
+
 ///
 /// Keyboard shortcut delegations
 ///
@@ -144,7 +192,9 @@ func (vim *Vim) lineDown(opts ki.Options) func() StatusBarMsg {
 		msg := StatusBarMsg{}
 
 		if f := vim.focusedComponent(); f != nil {
-			f.LineDown()
+			for range opts.Count() {
+				f.LineDown()
+			}
 
 			if f == vim.app.DirTree {
 				msg = vim.app.DirTree.ContentInfo()
@@ -152,8 +202,12 @@ func (vim *Vim) lineDown(opts ki.Options) func() StatusBarMsg {
 		}
 
 		if vim.app.Editor.Focused() {
+			row := vim.app.Editor.Textarea.Line()
 			multiline := opts.GetBool(ki.Args.MultiLine)
-			msg = vim.app.Editor.LineDown(multiline)
+			for range opts.Count() {
+				msg = vim.app.Editor.LineDown(multiline)
+			}
+			vim.failIfNotMoved(opts, row)
 		}
 
 		return msg
@@ -167,7 +221,9 @@ func (vim *Vim) lineUp(opts ki.Options) func() StatusBarMsg {
 		msg := StatusBarMsg{}
 
 		if f := vim.focusedComponent(); f != nil {
-			f.LineUp()
+			for range opts.Count() {
+				f.LineUp()
+			}
 
 			if f == vim.app.DirTree {
 				msg = vim.app.DirTree.ContentInfo()
@@ -175,17 +231,35 @@ func (vim *Vim) lineUp(opts ki.Options) func() StatusBarMsg {
 		}
 
 		if vim.app.Editor.Focused() {
+			row := vim.app.Editor.Textarea.Line()
 			multiline := opts.GetBool(ki.Args.MultiLine)
-			msg = vim.app.Editor.LineUp(multiline)
+			for range opts.Count() {
+				msg = vim.app.Editor.LineUp(multiline)
+			}
+			vim.failIfNotMoved(opts, row)
 		}
 
 		return msg
 	}
 }
 
-// goToTop moves the current item of focused list to its first item
-func (vim *Vim) goToTop(_ ki.Options) func() StatusBarMsg {
+// failIfNotMoved marks the motion as failed if it's linewise, an operator
+// is pending and the cursor is still in the line `row`, so that e.g. `dj`
+// on the last line doesn't delete it
+func (vim *Vim) failIfNotMoved(opts ki.Options, row int) {
+	if opts.GetBool(ki.Args.PendingOperator) && opts.GetBool(ki.Args.Linewise) {
+		vim.motionFailed = vim.app.Editor.Textarea.Line() == row
+	}
+}
+
+// goToTop moves the current item of focused list to its first item.
+// With a count it moves to the item or line with that number instead
+func (vim *Vim) goToTop(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
+		if opts.HasCount() {
+			return vim.goToLine(opts.Count())
+		}
+
 		if f := vim.focusedComponent(); f != nil {
 			return f.GoToTop()
 		}
@@ -198,9 +272,14 @@ func (vim *Vim) goToTop(_ ki.Options) func() StatusBarMsg {
 	}
 }
 
-// goToBottom moves the current item of the focused list to its last item
-func (vim *Vim) goToBottom(_ ki.Options) func() StatusBarMsg {
+// goToBottom moves the current item of the focused list to its last item.
+// With a count it moves to the item or line with that number instead
+func (vim *Vim) goToBottom(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
+		if opts.HasCount() {
+			return vim.goToLine(opts.Count())
+		}
+
 		if f := vim.focusedComponent(); f != nil {
 			return f.GoToBottom()
 		}
@@ -213,6 +292,24 @@ func (vim *Vim) goToBottom(_ ki.Options) func() StatusBarMsg {
 	}
 }
 
+// goToLine moves the focused list to the item with number `line`
+// or the editor's cursor to the line with number `line`
+func (vim *Vim) goToLine(line int) StatusBarMsg {
+	if f := vim.focusedComponent(); f != nil {
+		msg := f.GoToTop()
+		for range line - 1 {
+			msg = f.LineDown()
+		}
+		return msg
+	}
+
+	if vim.app.Editor.Focused() {
+		return vim.app.Editor.GoToLine(line)
+	}
+
+	return StatusBarMsg{}
+}
+
 // focusNextColumn selects and highlights the respectivley next of the
 // currently selected column.
 func (vim *Vim) focusNextColumn(opts ki.Options) func() StatusBarMsg {
@@ -385,12 +482,22 @@ func (vim *Vim) createNote(_ ki.Options) func() StatusBarMsg {
 	}
 }
 
+// enterCmdMode opens the command prompt. In visual mode the prompt
+// starts with the range of the selected lines, `'<,'>`
 func (vim *Vim) enterCmdMode(_ ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
-		if vim.app.Mode.Current != mode.Normal {
+		visual := vim.app.Mode.IsAnyVisual() && vim.app.Editor.Focused()
+
+		if vim.app.Mode.Current != mode.Normal && !visual {
 			return StatusBarMsg{}
 		}
 
+		if visual {
+			vim.app.Editor.EnterNormalMode(true)
+			vim.app.StatusBar.Prompt.SetValue("'<,'>")
+			vim.app.StatusBar.Prompt.CursorEnd()
+		}
+
 		vim.app.Editor.Mode.Current = mode.Command
 		vim.app.Mode.Current = mode.Command
 		vim.app.StatusBar.Focused = true
@@ -433,8 +540,17 @@ func (vim *Vim) confirmAction(opts ki.Options) func() StatusBarMsg {
 		f := vim.focusedComponent()
 
 		if vim.app.StatusBar.Focused {
+			searching := vim.app.Mode.Current == mode.SearchPrompt
+
 			statusMsg = vim.app.StatusBar.ConfirmAction(statusMsg.Sender, f)
 			vim.app.Editor.Mode.Current = mode.Normal
+
+			if searching && vim.app.Editor.Focused() {
+				if msg := vim.app.Editor.ConfirmSearch(); msg.Content != "" {
+					statusMsg.Content = msg.Content
+					statusMsg.Type = msg.Type
+				}
+			}
 		}
 
 		if vim.app.Mode.Current != mode.Normal &&
@@ -472,6 +588,12 @@ func (vim *Vim) confirmAction(opts ki.Options) func() StatusBarMsg {
 
 				vim.app.BufferList.Blur()
 				vim.app.CurrentOverlay = nil
+
+			case vim.app.TaskList:
+				statusMsg = vim.openSelectedTask()
+
+			case vim.app.SuggestionList:
+				statusMsg = vim.replaceWithSuggestion()
 			}
 		}
 
@@ -486,10 +608,16 @@ func (vim *Vim) confirmAction(opts ki.Options) func() StatusBarMsg {
 // and cancels pending actions in the focused component.
 func (vim *Vim) cancelAction(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
+		searching := vim.app.Mode.Current == mode.SearchPrompt
+
 		vim.app.Mode.Current = mode.Normal
 		vim.app.StatusBar.Focused = false
 
 		if vim.app.StatusBar.Prompt.Focused() {
+			if searching && vim.app.Editor.Focused() {
+				vim.app.Editor.CancelSearch()
+			}
+
 			vim.app.StatusBar.CancelAction(func() {})
 			return vim.enterNormalMode(opts)()
 		} else {
@@ -583,6 +711,14 @@ func (vim *Vim) toggleVisualBlock(opts ki.Options) func() StatusBarMsg {
 	}
 }
 
+// insertBlock inserts text before the block selection
+// or appends it to the block if `end` is set
+func (vim *Vim) insertBlock(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		return vim.app.Editor.InsertBlock(opts.GetBool(ki.Args.End))
+	}
+}
+
 func (vim *Vim) enterInsertMode(_ ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
 		return vim.app.Editor.EnterInsertMode(true)
@@ -608,17 +744,337 @@ func (vim *Vim) selectWord(opts ki.Options) func() StatusBarMsg {
 	}
 }
 
+func (vim *Vim) selectSentence(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		outer := opts.GetBool(ki.Args.Outer)
+
+		return vim.app.Editor.SelectTextObject(func() bool {
+			return vim.app.Editor.Textarea.SelectSentence(outer)
+		})
+	}
+}
+
+func (vim *Vim) selectParagraph(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		outer := opts.GetBool(ki.Args.Outer)
+
+		return vim.app.Editor.SelectTextObject(func() bool {
+			return vim.app.Editor.Textarea.SelectParagraph(outer)
+		})
+	}
+}
+
+// selectQuoted selects the text within the quote character
+// given with the `quote` option
+func (vim *Vim) selectQuoted(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		outer := opts.GetBool(ki.Args.Outer)
+		quote := []rune(opts.GetString(ki.Args.Quote))
+
+		if len(quote) != 1 {
+			return StatusBarMsg{}
+		}
+
+		return vim.app.Editor.SelectTextObject(func() bool {
+			return vim.app.Editor.Textarea.SelectQuoted(quote[0], outer)
+		})
+	}
+}
+
+// selectBrackets selects the text within the pair of brackets
+// given with the `brackets` option, e.g. "()"
+func (vim *Vim) selectBrackets(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		outer := opts.GetBool(ki.Args.Outer)
+		brackets := []rune(opts.GetString(ki.Args.Brackets))
+
+		if len(brackets) != 2 {
+			return StatusBarMsg{}
+		}
+
+		return vim.app.Editor.SelectTextObject(func() bool {
+			return vim.app.Editor.Textarea.SelectBrackets(brackets[0], brackets[1], outer)
+		})
+	}
+}
+
 func (vim *Vim) nextWord(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
+		ta := &vim.app.Editor.Textarea
 		end := opts.GetBool(ki.Args.End)
-		return vim.app.Editor.WordForward(end)
+		msg := StatusBarMsg{}
+		pos := ta.AbsCursorPos()
+
+		for range opts.Count() {
+			pos = ta.AbsCursorPos()
+			msg = vim.app.Editor.WordForward(end)
+		}
+
+		if !end && opts.GetBool(ki.Args.PendingOperator) {
+			newPos := ta.AbsCursorPos()
+			line := ta.Val()[newPos.Row]
+			col := newPos.ColumnOffset
+
+			// Operators like `dw` on the last word of a line are only
+			// applied until the end of the line, not until the next word.
+			// On an empty line they are applied to the line break
+			if newPos.Row > pos.Row {
+				if n := ta.LineLength(pos.Row); n > 0 {
+					ta.MoveCursor(pos.Row, 0, n)
+				} else {
+					ta.MoveCursor(pos.Row+1, 0, 0)
+				}
+				return msg
+			}
+
+			// There's no next word after the last word of the buffer, so
+			// operators like `dw` are applied until the end of the buffer
+			atWordStart := col > 0 && col <= len(line) && unicode.IsSpace(line[col-1])
+
+			if newPos == pos || !atWordStart {
+				vim.overshootBufferEnd()
+			}
+		}
+
+		return msg
 	}
 }
 
 func (vim *Vim) prevWord(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
 		end := opts.GetBool(ki.Args.End)
-		return vim.app.Editor.WordBack(end)
+		msg := StatusBarMsg{}
+		for range opts.Count() {
+			msg = vim.app.Editor.WordBack(end)
+		}
+		return msg
+	}
+}
+
+// nextParagraph moves the cursor to the empty line after the current
+// paragraph or `count` paragraphs
+func (vim *Vim) nextParagraph(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		msg := StatusBarMsg{}
+		for range opts.Count() {
+			msg = vim.app.Editor.ParagraphForward()
+		}
+
+		if opts.GetBool(ki.Args.PendingOperator) {
+			vim.overshootBufferEnd()
+		}
+
+		return msg
+	}
+}
+
+// overshootBufferEnd moves the cursor past the last character if it's
+// on the last character of the buffer.
+// Exclusive motions exclude the character under the cursor, this makes
+// sure that pending operators still reach the end of the buffer
+func (vim *Vim) overshootBufferEnd() {
+	ta := &vim.app.Editor.Textarea
+	pos := ta.AbsCursorPos()
+	lineLen := ta.LineLength(pos.Row)
+
+	if pos.Row == ta.LineCount()-1 && pos.ColumnOffset == lineLen-1 {
+		ta.SetCursorColumn(lineLen)
+	}
+}
+
+// goToLineEnd moves the cursor to the end of the line.
+// With a count it moves to the end of the line `count - 1` lines below
+func (vim *Vim) goToLineEnd(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		for range opts.Count() - 1 {
+			vim.app.Editor.LineDown(false)
+		}
+		return vim.app.Editor.GoToLineEnd()
+	}
+}
+
+// mergeLines merges the current line with the line below.
+// With a count it merges `count` lines
+func (vim *Vim) mergeLines(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		return vim.app.Editor.MergeLineBelow(opts.Count())
+	}
+}
+
+// repeatChange repeats the last change at the cursor.
+// A count replaces the count of the repeated change
+func (vim *Vim) repeatChange(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		count := 0
+		if opts.HasCount() {
+			count = opts.Count()
+		}
+
+		vim.KeyMap.RepeatChange(count, vim.handleKey)
+		return StatusBarMsg{}
+	}
+}
+
+// recordMacro starts recording keys into the register typed after `q`.
+// If a macro is already being recorded it stops the recording and
+// stores the keys as text in the register
+func (vim *Vim) recordMacro(_ ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		if name, keys, ok := vim.KeyMap.StopMacro(); ok {
+			vim.app.Editor.Registers.Store(name, ki.EncodeKeys(keys))
+			return StatusBarMsg{Content: "", Column: sbc.General}
+		}
+
+		vim.KeyMap.AwaitKey(func(key tea.Key) StatusBarMsg {
+			name, ok := registerName(key)
+			if !ok || name == registers.BlackHole {
+				return StatusBarMsg{Content: "", Column: sbc.KeyInfo}
+			}
+
+			vim.KeyMap.StartMacro(name)
+
+			return StatusBarMsg{
+				Content: fmt.Sprintf(message.StatusBar.RecordingMacro, name),
+				Column:  sbc.General,
+			}
+		})
+
+		return StatusBarMsg{}
+	}
+}
+
+// playMacro plays the macro stored in the register typed after `@`
+// `count` times. `@@` plays the last played macro again
+func (vim *Vim) playMacro(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		vim.KeyMap.AwaitKey(func(key tea.Key) StatusBarMsg {
+			name, ok := registerName(key)
+			if !ok && key.Text != "@" {
+				return StatusBarMsg{Content: "", Column: sbc.KeyInfo}
+			}
+
+			if key.Text == "@" {
+				name = vim.lastMacro
+			}
+
+			reg, ok := vim.app.Editor.Registers.Register(name)
+			if !ok {
+				return StatusBarMsg{Content: "", Column: sbc.KeyInfo}
+			}
+
+			vim.lastMacro = name
+			keys := ki.DecodeKeys(reg.Content)
+			vim.KeyMap.Replay(slices.Repeat(keys, opts.Count()), vim.handleKey)
+
+			return StatusBarMsg{Content: "", Column: sbc.KeyInfo}
+		})
+
+		return StatusBarMsg{}
+	}
+}
+
+// setMark sets the mark typed after the binding, e.g. the `a` in `ma`,
+// to the cursor position
+func (vim *Vim) setMark(_ ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		if name, ok := vim.awaitedRune(); ok {
+			return vim.app.Editor.SetMark(name)
+		}
+		return StatusBarMsg{}
+	}
+}
+
+// jumpToMark moves the cursor to the mark typed after the binding.
+// With the `exact` option it moves to the exact position of the mark,
+// otherwise to the first non-blank character of its line
+func (vim *Vim) jumpToMark(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		name, ok := vim.awaitedRune()
+		if !ok {
+			return StatusBarMsg{}
+		}
+
+		editor := vim.app.Editor
+
+		// operators can't be applied across notes or to marks
+		// that are not set
+		if opts.GetBool(ki.Args.PendingOperator) {
+			mark, ok := editor.Marks.Get(name, editor.CurrentBuffer.Path(false))
+			if !ok || mark.Path != editor.CurrentBuffer.Path(false) {
+				vim.motionFailed = true
+				return StatusBarMsg{}
+			}
+		}
+
+		return editor.JumpToMark(name, opts.GetBool(ki.Args.Exact))
+	}
+}
+
+func (vim *Vim) jumpBack(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		return vim.app.Editor.JumpBack(opts.Count())
+	}
+}
+
+func (vim *Vim) jumpForward(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		return vim.app.Editor.JumpForward(opts.Count())
+	}
+}
+
+// awaitedRune returns the character typed after the binding
+// of an action that awaits input
+func (vim *Vim) awaitedRune() (rune, bool) {
+	action := vim.KeyMap.AwaitInputAction
+	if action == nil {
+		return 0, false
+	}
+
+	r := []rune(vim.KeyMap.KeySequence[len(action.Binding()):])
+	if len(r) != 1 {
+		return 0, false
+	}
+
+	return r[0], true
+}
+
+// registerName returns the register name typed as the given key
+func registerName(key tea.Key) (rune, bool) {
+	name := []rune(key.Text)
+	if len(name) != 1 || !registers.Valid(name[0]) {
+		return 0, false
+	}
+	return name[0], true
+}
+
+// handleKey handles a replayed key just like the application handles
+// a typed key. Deferred commands, like leaving visual mode after a yank,
+// are executed right away so that the following keys work as expected
+func (vim *Vim) handleKey(key tea.Key) {
+	msg := tea.KeyPressMsg(key)
+
+	vim.KeyMap.AllowSequences = !vim.app.StatusBar.Focused
+	statusMsgs := vim.KeyMap.HandleSequences(key)
+
+	vim.app.StatusBar.Update(statusMsgs, msg)
+	vim.app.UpdateComponents(msg)
+
+	for _, m := range statusMsgs {
+		runCmd(m.Cmd)
+	}
+}
+
+// runCmd executes the given command and all commands it batches
+// and waits until they are done
+func runCmd(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, c := range batch {
+			runCmd(c)
+		}
 	}
 }
 
@@ -628,10 +1084,17 @@ func (vim *Vim) findCharacter(opts ki.Options) func() StatusBarMsg {
 		binding := []rune(action.Binding())
 
 		prev := action.Opts().GetBool(ki.Args.Prev)
+		till := action.Opts().GetBool(ki.Args.Till)
 		input := action.Opts().GetBool(ki.Args.Insert)
 		char := vim.KeyMap.KeySequence[len(binding):]
 
-		vim.app.Editor.FindCharacter(char, prev)
+		for i := range opts.Count() {
+			if till {
+				vim.app.Editor.TillCharacter(char, prev, i > 0)
+			} else {
+				vim.app.Editor.FindCharacter(char, prev)
+			}
+		}
 
 		if input {
 			vim.app.Editor.EnterInsertMode(true)
@@ -659,13 +1122,13 @@ func (vim *Vim) findWordUnderCursor(opts ki.Options) func() StatusBarMsg {
 
 			vim.app.Editor.Textarea.Search = textarea.Search{
 				IgnoreCase: opts.GetBool(ki.Args.IgnoreCase),
-				Matches:    make(map[int][]int, 1),
 				Query:      word,
 				ExactWord:  true,
 			}
 
 			vim.app.Editor.Mode.Current = mode.SearchPrompt
 			vim.app.Mode.Current = mode.SearchPrompt
+			vim.app.StatusBar.SearchBackward = false
 
 			return StatusBarMsg{
 				Type:   message.Prompt,
@@ -676,16 +1139,17 @@ func (vim *Vim) findWordUnderCursor(opts ki.Options) func() StatusBarMsg {
 	}
 }
 
+// find opens the search prompt. With the `prev` option it searches
+// backward like `?`
 func (vim *Vim) find(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
-		vim.app.Editor.Textarea.Search = textarea.Search{
-			IgnoreCase: opts.GetBool(ki.Args.IgnoreCase),
-			Matches:    make(map[int][]int, 1),
-		}
+		backward := opts.GetBool(ki.Args.Prev)
+		vim.app.Editor.StartSearch(backward)
 
 		vim.app.Editor.Mode.Current = mode.SearchPrompt
 		vim.app.Mode.Current = mode.SearchPrompt
 		vim.app.StatusBar.Focused = true
+		vim.app.StatusBar.SearchBackward = backward
 
 		return StatusBarMsg{
 			Type:   message.Prompt,
@@ -696,12 +1160,7 @@ func (vim *Vim) find(opts ki.Options) func() StatusBarMsg {
 
 func (vim *Vim) moveToMatch(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
-		if opts.GetBool("prev") {
-			vim.app.Editor.Textarea.FindPrevMatch()
-		} else {
-			vim.app.Editor.Textarea.FindNextMatch()
-		}
-		return StatusBarMsg{}
+		return vim.app.Editor.MoveToMatch(opts.GetBool(ki.Args.Prev))
 	}
 }
 
@@ -710,7 +1169,7 @@ func (vim *Vim) deleteWord(opts ki.Options) func() StatusBarMsg {
 		outer := opts.GetBool(ki.Args.Outer)
 
 		if opts.GetBool(ki.Args.Remaining) {
-			return vim.app.Editor.DeleteWordRight()
+			return vim.app.Editor.DeleteWordRight(opts.Count())
 		}
 
 		return vim.app.Editor.DeleteWord(outer, false)
@@ -723,8 +1182,9 @@ func (vim *Vim) deleteAfterCursor(opts ki.Options) func() StatusBarMsg {
 	}
 }
 
-func (vim *Vim) deleteSelection(_ ki.Options) func() StatusBarMsg {
+func (vim *Vim) deleteSelection(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
+		vim.useRegister(opts)
 		if vim.app.Mode.IsAnyVisual() {
 			return vim.app.Editor.DeleteRune(false, true, false)
 		}
@@ -732,9 +1192,18 @@ func (vim *Vim) deleteSelection(_ ki.Options) func() StatusBarMsg {
 	}
 }
 
-func (vim *Vim) deleteCharacter(_ ki.Options) func() StatusBarMsg {
+func (vim *Vim) deleteCharacter(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
-		return vim.app.Editor.DeleteRune(false, true, false)
+		vim.useRegister(opts)
+		return vim.app.Editor.DeleteCharacters(opts.Count())
+	}
+}
+
+// deleteLine deletes the current line or `count` lines
+func (vim *Vim) deleteLine(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		vim.useRegister(opts)
+		return vim.app.Editor.DeleteLines(opts.Count())
 	}
 }
 
@@ -763,7 +1232,13 @@ func (vim *Vim) deleteFromCursorToChar(opts ki.Options) func() StatusBarMsg {
 
 func (vim *Vim) substituteText(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
-		msg := vim.app.Editor.DeleteRune(false, true, false)
+		vim.useRegister(opts)
+
+		if vim.app.Editor.Mode.Current == mode.VisualBlock {
+			return vim.app.Editor.ChangeBlock()
+		}
+
+		msg := vim.app.Editor.DeleteCharacters(opts.Count())
 
 		if opts.GetBool(ki.Args.NewLine) {
 			vim.app.Editor.Textarea.EmptyLineAbove()
@@ -800,7 +1275,7 @@ func (vim *Vim) changeWord(opts ki.Options) func() StatusBarMsg {
 		outer := opts.GetBool(ki.Args.Outer)
 
 		if opts.GetBool(ki.Args.Remaining) {
-			vim.app.Editor.DeleteWordRight()
+			vim.app.Editor.DeleteWordRight(opts.Count())
 			return vim.app.Editor.EnterInsertMode(false)
 		}
 
@@ -808,14 +1283,31 @@ func (vim *Vim) changeWord(opts ki.Options) func() StatusBarMsg {
 	}
 }
 
-func (vim *Vim) yankSelection(_ ki.Options) func() StatusBarMsg {
+func (vim *Vim) yankSelection(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
+		vim.useRegister(opts)
 		return vim.app.Editor.YankSelection(false)
 	}
 }
 
+// yankLine copies the current line or `count` lines
+func (vim *Vim) yankLine(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		vim.useRegister(opts)
+		return vim.app.Editor.YankLines(opts.Count())
+	}
+}
+
+func (vim *Vim) yankAfterCursor(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		vim.useRegister(opts)
+		return vim.app.Editor.YankAfterCursor()
+	}
+}
+
 func (vim *Vim) yankWord(opts ki.Options) func() StatusBarMsg {
 	return func() StatusBarMsg {
+		vim.useRegister(opts)
 		outer := opts.GetBool("outer")
 		return vim.app.Editor.YankWord(outer)
 	}
@@ -833,7 +1325,8 @@ func (vim *Vim) paste(opts ki.Options) func() StatusBarMsg {
 			vim.app.Editor.MoveCharacterLeft()
 		}
 
-		msg := vim.app.Editor.Paste()
+		vim.useRegister(opts)
+		msg := vim.app.Editor.Paste(opts.Count())
 
 		vim.selectWord(opts)
 		return msg
@@ -852,6 +1345,43 @@ func (vim *Vim) changeToUpperCase(_ ki.Options) func() StatusBarMsg {
 	}
 }
 
+// shiftSelection shifts the selected lines by the shift width, a count
+// shifts them several times, e.g. `3>`
+func (vim *Vim) shiftSelection(left bool) ki.Motion {
+	return func(opts ki.Options) func() StatusBarMsg {
+		return func() StatusBarMsg {
+			return vim.app.Editor.ShiftSelection(opts.Count(), left)
+		}
+	}
+}
+
+// formatSelection wraps the selected lines at textwidth. With the
+// `keep_cursor` option the cursor stays where it is like with `gw`
+func (vim *Vim) formatSelection(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		return vim.app.Editor.FormatSelection(opts.GetBool(ki.Args.KeepCursor))
+	}
+}
+
+// makeList turns the selected lines into the list given with the
+// `list` option, `bullet`, `numbered` or `checkbox`
+func (vim *Vim) makeList(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		listType := editor.ListType(opts.GetString(ki.Args.List))
+		return vim.app.Editor.MakeList(listType)
+	}
+}
+
+// useRegister selects the register given with `"x` for the next
+// yank, delete or paste
+func (vim *Vim) useRegister(opts ki.Options) {
+	vim.app.Editor.Registers.Deselect()
+
+	if r := opts.Register(); r != 0 {
+		vim.app.Editor.Registers.Select(r)
+	}
+}
+
 func (vim *Vim) OverlayOpenBuffers() {
 	ov := vim.app.BufferList.Overlay
 
@@ -904,6 +1434,14 @@ func (vim *Vim) focusedComponent() interfaces.Focusable {
 		return vim.app.BufferList
 	}
 
+	if vim.app.TaskList.Focused() {
+		return vim.app.TaskList
+	}
+
+	if vim.app.SuggestionList.Focused() {
+		return vim.app.SuggestionList
+	}
+
 	return nil
 }
 
@@ -1,21 +1,31 @@
 package vim
 
 import (
+	"bellbird-notes/internal/testutil"
 	"bellbird-notes/tui/components/application"
+	"bellbird-notes/tui/components/editor"
 	"bellbird-notes/tui/keyinput"
 	"bellbird-notes/tui/mode"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
-func createTestApp(t *testing.T) (*Vim, *application.App) {
-	// create a file to test editor line down
+// testNote is the content of the note most tests start with
+const testNote = "TEST1\nTest2\nTest3\ntest4\ntes5t"
+
+// createTestApp creates an app whose editor handles typed keys
+// just like the running application and opens a note with the
+// given content
+func createTestApp(t *testing.T, content string) (*Vim, *application.App) {
+	testutil.TempHome(t)
+
 	tmp := t.TempDir()
 	path := filepath.Join(tmp, "test_note.txt")
 
-	content := "TEST1\nTest2\nTest3\ntest4\ntes5t"
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		t.Fatalf("Write failed: %v", err)
 	}
@@ -31,6 +41,8 @@ func createTestApp(t *testing.T) (*Vim, *application.App) {
 		app.NotesList,
 		app.Editor,
 		app.BufferList,
+		app.TaskList,
+		app.SuggestionList,
 	}
 	vim.KeyMap = app.KeyInput
 
@@ -43,12 +55,35 @@ func createTestApp(t *testing.T) (*Vim, *application.App) {
 	// Create a buffer
 	editor.NewBuffer(path)
 	editor.Buffers = app.Editor.Buffers
+	editor.LastOpenNoteLoaded = true
+	editor.Size.Width = 80
+	editor.Size.Height = 20
+
+	app.KeyInput.FetchKeyMap(true)
 
 	return vim, app
 }
 
+// typeKeys passes the given keys to the key input and the
+// components just as if they have been typed
+func typeKeys(app *application.App, keys ...tea.Key) {
+	for _, key := range keys {
+		app.KeyInput.HandleSequences(key)
+		app.UpdateComponents(tea.KeyPressMsg(key))
+	}
+}
+
+// textKeys returns the keys for typing the given text
+func textKeys(text string) []tea.Key {
+	keys := []tea.Key{}
+	for _, r := range text {
+		keys = append(keys, tea.Key{Code: r, Text: string(r)})
+	}
+	return keys
+}
+
 func TestLineUpDown(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 
 	buf := app.Editor.CurrentBuffer
 	row := buf.CursorPos.Row
@@ -80,8 +115,37 @@ func TestLineUpDown(t *testing.T) {
 
 }
 
+func TestLineDownCount(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	buf := app.Editor.CurrentBuffer
+	vim.lineDown(keyinput.Options{keyinput.Args.Count: 3})()
+
+	if buf.CursorPos.Row != 3 {
+		t.Fatalf("Expected line to be index 3, but is %d", buf.CursorPos.Row)
+	}
+
+	// counts exceeding the buffer stop at the last line
+	vim.lineDown(keyinput.Options{keyinput.Args.Count: 10})()
+
+	if buf.CursorPos.Row != 4 {
+		t.Fatalf("Expected line to be index 4, but is %d", buf.CursorPos.Row)
+	}
+}
+
+func TestGoToLineCount(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	buf := app.Editor.CurrentBuffer
+	vim.goToBottom(keyinput.Options{keyinput.Args.Count: 2})()
+
+	if buf.CursorPos.Row != 1 {
+		t.Fatalf("Expected line to be index 1, but is %d", buf.CursorPos.Row)
+	}
+}
+
 func TestGoToTopBottom(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 
 	buf := app.Editor.CurrentBuffer
 	var opts keyinput.Options
@@ -110,7 +174,7 @@ func TestGoToTopBottom(t *testing.T) {
 }
 
 func TestFocusColumn(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 
 	var opts keyinput.Options
 
@@ -149,7 +213,7 @@ func TestFocusColumn(t *testing.T) {
 //func TestCreateNote(t *testing.T) {}
 
 func TestEnterCmdMode(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 	vim.enterCmdMode(keyinput.Options{})()
 
 	if app.Mode.Current != mode.Command {
@@ -161,7 +225,7 @@ func TestEnterCmdMode(t *testing.T) {
 }
 
 func TestOpenCloseBufferList(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 
 	// -- TEST OPEN BUFFER LIST
 
@@ -184,7 +248,7 @@ func TestOpenCloseBufferList(t *testing.T) {
 //func TestCancelAction(t *testing.T) {}
 
 func TestNewScratchBuffer(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 	vim.newScratchBuffer(keyinput.Options{})()
 
 	if !app.Editor.CurrentBuffer.IsScratch {
@@ -193,7 +257,7 @@ func TestNewScratchBuffer(t *testing.T) {
 }
 
 func TestEnterNormalMode(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 	vim.enterNormalMode(keyinput.Options{})()
 
 	if app.Mode.Current != mode.Normal {
@@ -205,7 +269,7 @@ func TestEnterNormalMode(t *testing.T) {
 }
 
 func TestEnterToggleVisualMode(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 
 	// -- TEST ENTER VISUAL MODE
 
@@ -231,7 +295,7 @@ func TestEnterToggleVisualMode(t *testing.T) {
 }
 
 func TestEnterToggleVisualLineMode(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 	vim.toggleVisualLine(keyinput.Options{})()
 
 	if app.Editor.Mode.Current != mode.VisualLine {
@@ -252,7 +316,7 @@ func TestEnterToggleVisualLineMode(t *testing.T) {
 }
 
 //func TestEnterToggleVisualBlockMode(t *testing.T) {
-//	vim, app := createTestApp(t)
+//	vim, app := createTestApp(t, testNote)
 //	vim.toggleVisualBlock(keyinput.Options{})()
 //
 //	if app.Editor.Mode.Current != mode.VisualBlock {
@@ -273,7 +337,7 @@ func TestEnterToggleVisualLineMode(t *testing.T) {
 //}
 
 func TestEnterInsertMode(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 	vim.enterInsertMode(keyinput.Options{})()
 
 	if app.Editor.Mode.Current != mode.Insert {
@@ -285,7 +349,7 @@ func TestEnterInsertMode(t *testing.T) {
 }
 
 func TestInsertBelow(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 
 	buf := app.Editor.CurrentBuffer
 	ta := app.Editor.Textarea
@@ -301,7 +365,7 @@ func TestInsertBelow(t *testing.T) {
 }
 
 func TestInsertAbove(t *testing.T) {
-	vim, app := createTestApp(t)
+	vim, app := createTestApp(t, testNote)
 
 	buf := app.Editor.CurrentBuffer
 	ta := app.Editor.Textarea
@@ -334,3 +398,246 @@ func TestChangeLine(t *testing.T)             {}
 func TestChangeWord(t *testing.T)             {}
 func TestYankSelection(t *testing.T)          {}
 func TestPaste(t *testing.T)                  {}
+
+func TestDeleteLineCount(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	vim.deleteLine(keyinput.Options{keyinput.Args.Count: 2})()
+
+	if got := app.Editor.Textarea.Value(); got != "Test3\ntest4\ntes5t" {
+		t.Fatalf("Expected two lines to be deleted, but content is %q", got)
+	}
+
+	// a single undo restores all deleted lines
+	app.Editor.Undo()
+
+	if got := app.Editor.Textarea.Value(); got != "TEST1\nTest2\nTest3\ntest4\ntes5t" {
+		t.Fatalf("Expected undo to restore deleted lines, but content is %q", got)
+	}
+}
+
+func TestPasteFromRegister(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	// "ayy
+	vim.yankLine(keyinput.Options{keyinput.Args.Register: "a"})()
+	app.Editor.EnterNormalMode(false)
+
+	// dd overwrites the unnamed register but not register a
+	vim.deleteLine(keyinput.Options{})()
+
+	// "ap
+	vim.paste(keyinput.Options{keyinput.Args.Register: "a"})()
+
+	if got := app.Editor.Textarea.Value(); got != "Test2\nTEST1\nTest3\ntest4\ntes5t" {
+		t.Fatalf("Expected register a to be pasted, but content is %q", got)
+	}
+
+	if reg, _ := app.Editor.Registers.Register('1'); reg.Content != "TEST1\n" {
+		t.Fatalf("Expected deleted line in register 1, got %q", reg.Content)
+	}
+}
+
+func TestRepeatChange(t *testing.T) {
+	_, app := createTestApp(t, testNote)
+
+	esc := tea.Key{Code: tea.KeyEscape}
+
+	typeKeys(app, textKeys("ciwfoo")...)
+	typeKeys(app, esc)
+	typeKeys(app, textKeys("j0.")...)
+
+	if got := app.Editor.Textarea.Value(); got != "foo1\nfoo2\nTest3\ntest4\ntes5t" {
+		t.Fatalf("Expected change to be repeated, but content is %q", got)
+	}
+
+	// a count replaces the count of the repeated change
+	typeKeys(app, textKeys("j0x2.")...)
+
+	if got := app.Editor.Textarea.Value(); got != "foo1\nfoo2\nt3\ntest4\ntes5t" {
+		t.Fatalf("Expected change to be repeated with count, but content is %q", got)
+	}
+
+	// motions don't replace the last change
+	typeKeys(app, textKeys("jl.")...)
+
+	if got := app.Editor.Textarea.Value(); got != "foo1\nfoo2\nt3\ntst4\ntes5t" {
+		t.Fatalf("Expected last change to be repeated, but content is %q", got)
+	}
+}
+
+func TestMacro(t *testing.T) {
+	_, app := createTestApp(t, testNote)
+
+	esc := tea.Key{Code: tea.KeyEscape}
+
+	// qaA!<esc>jq
+	typeKeys(app, textKeys("qaA!")...)
+	typeKeys(app, esc)
+	typeKeys(app, textKeys("jq")...)
+
+	if reg, _ := app.Editor.Registers.Register('a'); reg.Content != "A!<esc>j" {
+		t.Fatalf("Expected macro to be stored as text, got %q", reg.Content)
+	}
+
+	typeKeys(app, textKeys("2@a@@")...)
+
+	if got := app.Editor.Textarea.Value(); got != "TEST1!\nTest2!\nTest3!\ntest4!\ntes5t" {
+		t.Fatalf("Expected macro to be played, but content is %q", got)
+	}
+}
+
+func TestMarks(t *testing.T) {
+	_, app := createTestApp(t, testNote)
+
+	ta := &app.Editor.Textarea
+
+	typeKeys(app, textKeys("jjllmagg")...)
+
+	// lines inserted above the mark move it down
+	typeKeys(app, textKeys("Onew")...)
+	typeKeys(app, tea.Key{Code: tea.KeyEscape})
+	typeKeys(app, textKeys("gg`a")...)
+
+	if pos := ta.AbsCursorPos(); pos.Row != 3 || pos.ColumnOffset != 2 {
+		t.Fatalf("Expected cursor at mark (3, 2), got (%d, %d)", pos.Row, pos.ColumnOffset)
+	}
+
+	// deleting the line removes the mark
+	typeKeys(app, textKeys("dd")...)
+
+	if _, ok := app.Editor.Marks.Get('a', app.Editor.CurrentBuffer.Path(false)); ok {
+		t.Fatal("Expected mark to be removed with its line")
+	}
+
+	// marks can be restored from the state file
+	marks := editor.NewMarks()
+	marks.Restore(app.Editor.Marks.Serialize())
+
+	if len(marks.All(app.Editor.CurrentBuffer.Path(false))) != 0 {
+		t.Fatal("Expected removed mark not to be restored")
+	}
+}
+
+func TestJumpList(t *testing.T) {
+	_, app := createTestApp(t, testNote)
+
+	ta := &app.Editor.Textarea
+
+	typeKeys(app, textKeys("jlG")...)
+
+	if row := ta.AbsCursorPos().Row; row != 4 {
+		t.Fatalf("Expected cursor in last line, got %d", row)
+	}
+
+	typeKeys(app, tea.Key{Code: 'o', Mod: tea.ModCtrl})
+
+	if pos := ta.AbsCursorPos(); pos.Row != 1 || pos.ColumnOffset != 1 {
+		t.Fatalf("Expected cursor at (1, 1), got (%d, %d)", pos.Row, pos.ColumnOffset)
+	}
+
+	typeKeys(app, tea.Key{Code: tea.KeyTab})
+
+	if row := ta.AbsCursorPos().Row; row != 4 {
+		t.Fatalf("Expected cursor back in last line, got %d", row)
+	}
+
+	// there's nothing newer to jump to
+	typeKeys(app, tea.Key{Code: tea.KeyTab})
+
+	if row := ta.AbsCursorPos().Row; row != 4 {
+		t.Fatalf("Expected cursor to stay in last line, got %d", row)
+	}
+}
+
+func TestVisualBlock(t *testing.T) {
+	_, app := createTestApp(t, testNote)
+
+	ta := &app.Editor.Textarea
+	esc := tea.Key{Code: tea.KeyEscape}
+	ctrlV := tea.Key{Code: 'v', Mod: tea.ModCtrl}
+
+	typeKeys(app, tea.Key{Code: 'l', Text: "l"}, ctrlV)
+	typeKeys(app, textKeys("jld")...)
+
+	if got := ta.Value(); got != "TT1\nTt2\nTest3\ntest4\ntes5t" {
+		t.Fatalf("Expected block to be deleted, but content is %q", got)
+	}
+
+	if reg, _ := app.Editor.Registers.Get(); reg.Content != "ES\nes" || !reg.Blockwise {
+		t.Fatalf("Expected deleted block in register, got %q", reg.Content)
+	}
+
+	// the block is pasted below each other after the cursor
+	typeKeys(app, textKeys("p")...)
+
+	if got := ta.Value(); got != "TTES1\nTtes2\nTest3\ntest4\ntes5t" {
+		t.Fatalf("Expected block to be pasted, but content is %q", got)
+	}
+
+	// `I` inserts the text into every line of the block
+	typeKeys(app, textKeys("gg0")...)
+	typeKeys(app, ctrlV)
+	typeKeys(app, textKeys("jjI- ")...)
+	typeKeys(app, esc)
+
+	if got := ta.Value(); got != "- TTES1\n- Ttes2\n- Test3\ntest4\ntes5t" {
+		t.Fatalf("Expected text to be inserted, but content is %q", got)
+	}
+
+	// `$` extends the block to the end of every line
+	typeKeys(app, textKeys("ugg0jj")...)
+	typeKeys(app, ctrlV)
+	typeKeys(app, textKeys("j$A!")...)
+	typeKeys(app, esc)
+
+	if got := ta.Value(); got != "TTES1\nTtes2\nTest3!\ntest4!\ntes5t" {
+		t.Fatalf("Expected text to be appended, but content is %q", got)
+	}
+}
+
+func TestTextObjects(t *testing.T) {
+	_, app := createTestApp(t, testNote)
+
+	ta := &app.Editor.Textarea
+	esc := tea.Key{Code: tea.KeyEscape}
+
+	tests := []struct {
+		content, keys, expected string
+	}{
+		{"say \"hello there\" now", "fedi\"", "say \"\" now"},
+		{"say \"hello there\" now", "fhda\"", "say now"},
+		{"fn(a, (b), c)", "fadi(", "fn()"},
+		{"fn(a, (b), c)", "fbda)", "fn(a, , c)"},
+		{"if {\n    foo\n    bar\n}", "jdi{", "if {\n}"},
+		{"if {\n    foo\n}\nend", "jda{", "if \nend"},
+		{"<b>bold</b>", "ldi<", "<>bold</b>"},
+		{"One. Two\nlines. Three.", "fTdis", "One.  Three."},
+		{"One. Two. Three.", "fTdas", "One. Three."},
+		{"first\nparagraph\n\nsecond", "dap", "second"},
+		{"first\n\nsecond\nparagraph", "jjdip", "first\n"},
+	}
+
+	for _, tt := range tests {
+		ta.SetValue(tt.content)
+		ta.MoveCursor(0, 0, 0)
+		app.Editor.CurrentBuffer.CursorPos = ta.CursorPos()
+
+		typeKeys(app, textKeys(tt.keys)...)
+		typeKeys(app, esc)
+
+		if got := ta.Value(); got != tt.expected {
+			t.Errorf("%q on %q: expected %q, got %q", tt.keys, tt.content, tt.expected, got)
+		}
+	}
+
+	// text objects can be selected in visual mode
+	ta.SetValue("a (b c) d")
+	ta.MoveCursor(0, 0, 4)
+
+	typeKeys(app, textKeys("vi(y")...)
+
+	if reg, _ := app.Editor.Registers.Get(); reg.Content != "b c" {
+		t.Fatalf("Expected selected brackets to be yanked, got %q", reg.Content)
+	}
+}
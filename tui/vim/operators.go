@@ -0,0 +1,161 @@
+package vim
+
+import (
+	"unicode"
+
+	"bellbird-notes/tui/components/editor"
+	"bellbird-notes/tui/components/textarea"
+	ki "bellbird-notes/tui/keyinput"
+	sbc "bellbird-notes/tui/types/statusbar_column"
+)
+
+func (vim *Vim) OperatorRegistry() ki.OperatorRegistry {
+	return ki.OperatorRegistry{
+		"OperatorDelete":           vim.operator(editor.OperatorDelete),
+		"OperatorChange":           vim.operator(editor.OperatorChange),
+		"OperatorYank":             vim.operator(editor.OperatorYank),
+		"OperatorLowerCase":        vim.operator(editor.OperatorLowerCase),
+		"OperatorUpperCase":        vim.operator(editor.OperatorUpperCase),
+		"OperatorToggleCase":       vim.operator(editor.OperatorToggleCase),
+		"OperatorIndent":           vim.operator(editor.OperatorIndent),
+		"OperatorOutdent":          vim.operator(editor.OperatorOutdent),
+		"OperatorFormat":           vim.operator(editor.OperatorFormat),
+		"OperatorFormatKeepCursor": vim.operator(editor.OperatorFormatKeepCursor),
+	}
+}
+
+// operator returns an operator that applies op to the text
+// the motion moves over or the text object selects
+func (vim *Vim) operator(op editor.Operator) ki.Operator {
+	return func(opts ki.Options, motion ki.OperatorMotion) func() StatusBarMsg {
+		return func() StatusBarMsg {
+			vim.useRegister(opts)
+			r, ok := vim.operatorRange(op, motion)
+
+			if !ok {
+				return StatusBarMsg{Content: "", Column: sbc.KeyInfo}
+			}
+
+			return vim.app.Editor.ApplyOperator(op, r)
+		}
+	}
+}
+
+// operatorRange executes the motion and returns the range between
+// the cursor position before and after the motion.
+// Returns false if the range is empty.
+func (vim *Vim) operatorRange(
+	op editor.Operator,
+	motion ki.OperatorMotion,
+) (editor.OperatorRange, bool) {
+	ta := &vim.app.Editor.Textarea
+	cursor := ta.AbsCursorPos()
+
+	// repeated operators like `dd` apply to `count` lines
+	if motion.Exec == nil {
+		end := cursor
+		end.Row = min(cursor.Row+motion.Count-1, ta.LineCount()-1)
+
+		return editor.OperatorRange{
+			Start:    cursor,
+			End:      end,
+			Linewise: true,
+			Cursor:   cursor,
+		}, true
+	}
+
+	ta.ResetSelection()
+	vim.motionFailed = false
+	motion.Exec()
+
+	// the operator is cancelled if the motion can't move, e.g. `dk`
+	// on the first line
+	if vim.motionFailed {
+		ta.MoveCursor(cursor.Row, 0, cursor.ColumnOffset)
+		return editor.OperatorRange{}, false
+	}
+
+	r := editor.OperatorRange{
+		Start:    cursor,
+		End:      ta.AbsCursorPos(),
+		Linewise: motion.Linewise,
+		Cursor:   cursor,
+	}
+
+	// text objects select the text the operator is applied to
+	if sel := ta.Selection; sel.StartRow > -1 {
+		r.Start = textarea.CursorPos{
+			Row:          sel.StartRow,
+			ColumnOffset: sel.StartCol,
+		}
+		r.Linewise = sel.Mode == textarea.SelectVisualLine
+		ta.ResetSelection()
+
+		// selections always include the last character
+		motion.Inclusive = true
+	}
+
+	if r.Start.GreaterThan(r.End) {
+		r.Start, r.End = r.End, r.Start
+	}
+
+	if r.Linewise {
+		return r, true
+	}
+
+	if motion.Inclusive {
+		r.End.ColumnOffset++
+		return r, true
+	}
+
+	// An exclusive motion that ends at the beginning of a line
+	// doesn't include the line break before, e.g. `d}` only deletes
+	// until the end of the paragraph.
+	// If it starts at or before the first non-blank character as well
+	// it becomes linewise, so `d}` at the start of a paragraph
+	// deletes its lines
+	if r.End.Row > r.Start.Row && r.End.ColumnOffset == 0 {
+		r.End.Row--
+		r.End.ColumnOffset = ta.LineLength(r.End.Row)
+
+		if r.Start.ColumnOffset <= len(ta.Indentation(r.Start.Row)) {
+			r.Linewise = true
+			return r, true
+		}
+	}
+
+	// `cw` doesn't change the white space after the word
+	if op == editor.OperatorChange {
+		r.End = vim.trimTrailingSpace(r.Start, r.End)
+	}
+
+	if r.Start == r.End {
+		ta.MoveCursor(cursor.Row, 0, cursor.ColumnOffset)
+		return r, false
+	}
+
+	return r, true
+}
+
+// trimTrailingSpace moves `end` back to the last non-white space
+// character between `start` and `end`.
+// If there is nothing but white space `end` is returned unchanged
+func (vim *Vim) trimTrailingSpace(start, end textarea.CursorPos) textarea.CursorPos {
+	if start.Row != end.Row {
+		return end
+	}
+
+	line := vim.app.Editor.Textarea.Val()[end.Row]
+	col := min(end.ColumnOffset, len(line))
+
+	for col > start.ColumnOffset && unicode.IsSpace(line[col-1]) {
+		col--
+	}
+
+	if col == start.ColumnOffset {
+		return end
+	}
+
+	end.ColumnOffset = col
+	return end
+}
@@ -0,0 +1,126 @@
+package vim
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/components/editor"
+	"bellbird-notes/tui/keyinput"
+)
+
+func TestOperatorExclusiveLinewise(t *testing.T) {
+	tests := []struct {
+		content, keys, expected string
+	}{
+		// the motion becomes linewise at the start of the line
+		{"a\nb\n\nc", "d}", "\nc"},
+		{"a\nb\n\nc", "Gd{", "a\nb\nc"},
+		{"  a\nb\n\nc", "d}", "\nc"},
+
+		// otherwise the line break before the end isn't included
+		{"ab\nc\n\nd", "ld}", "a\n\nd"},
+
+		// the last word of a line is deleted until the end of the line
+		{"a\n  b", "dw", "\n  b"},
+		{"ab cd\n\nef", "w2dw", "ab \nef"},
+		{"ab\n\ncd", "jdw", "ab\ncd"},
+	}
+
+	for _, tt := range tests {
+		_, app := createTestApp(t, tt.content)
+		typeKeys(app, textKeys(tt.keys)...)
+
+		if got := app.Editor.Textarea.Value(); got != tt.expected {
+			t.Errorf("%q on %q: expected %q, got %q", tt.keys, tt.content, tt.expected, got)
+		}
+	}
+}
+
+func TestOperatorFailedMotion(t *testing.T) {
+	tests := []struct {
+		keys, expected string
+	}{
+		{"dk", "a\nb\nc"},
+		{"Gdj", "a\nb\nc"},
+		{"d'x", "a\nb\nc"},
+		{"jd5j", "a"},
+		{"Gdgg", ""},
+	}
+
+	for _, tt := range tests {
+		_, app := createTestApp(t, "a\nb\nc")
+		typeKeys(app, textKeys(tt.keys)...)
+
+		if got := app.Editor.Textarea.Value(); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.keys, tt.expected, got)
+		}
+	}
+}
+
+func TestOperatorLinewiseMotion(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	// dj
+	vim.operator(editor.OperatorDelete)(nil, keyinput.OperatorMotion{
+		Exec:     vim.lineDown(keyinput.Options{}),
+		Count:    1,
+		Linewise: true,
+	})()
+
+	if got := app.Editor.Textarea.Value(); got != "Test3\ntest4\ntes5t" {
+		t.Fatalf("Expected two lines to be deleted, but content is %q", got)
+	}
+
+	app.Editor.Undo()
+
+	if got := app.Editor.Textarea.Value(); got != "TEST1\nTest2\nTest3\ntest4\ntes5t" {
+		t.Fatalf("Expected undo to restore deleted lines, but content is %q", got)
+	}
+}
+
+func TestOperatorCharwiseMotion(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	// de
+	vim.operator(editor.OperatorDelete)(nil, keyinput.OperatorMotion{
+		Exec:      vim.nextWord(keyinput.Options{keyinput.Args.End: true}),
+		Count:     1,
+		Inclusive: true,
+	})()
+
+	if got := app.Editor.Textarea.Value(); got != "\nTest2\nTest3\ntest4\ntes5t" {
+		t.Fatalf("Expected first word to be deleted, but content is %q", got)
+	}
+}
+
+func TestOperatorRepeat(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	// 2g~~
+	vim.operator(editor.OperatorToggleCase)(nil, keyinput.OperatorMotion{
+		Count: 2,
+	})()
+
+	if got := app.Editor.Textarea.Value(); got != "test1\ntEST2\nTest3\ntest4\ntes5t" {
+		t.Fatalf("Expected case of two lines to be toggled, but content is %q", got)
+	}
+}
+
+func TestOperatorTabs(t *testing.T) {
+	tests := []struct {
+		content, keys, expected string
+	}{
+		// tabs separate words just like spaces
+		{"a\tb", "dw", "b"},
+		{"ab\tcd", "cwx", "x\tcd"},
+		{"ab \tcd", "cwx", "x \tcd"},
+	}
+
+	for _, tt := range tests {
+		_, app := createTestApp(t, tt.content)
+		typeKeys(app, textKeys(tt.keys)...)
+
+		if got := app.Editor.Textarea.Value(); got != tt.expected {
+			t.Errorf("%q on %q: expected %q, got %q", tt.keys, tt.content, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package vim
+
+import (
+	"strconv"
+	"strings"
+
+	"bellbird-notes/tui/message"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// lineRange resolves a line range like `%`, `5,10`, `.,+3` or `'a,'b`
+// to the first and the last row.
+// An empty range is the current line, a single address is a range
+// of one line. If the addresses are separated by `;` the second one
+// is relative to the first one.
+// Returns false if the range is invalid
+func (vim *Vim) lineRange(spec string) (int, int, bool) {
+	start, end, ok := vim.resolveRange(spec)
+	return min(start, end), max(start, end), ok
+}
+
+// resolveRange resolves a line range like lineRange but keeps the order
+// of the addresses, e.g. `5,1` is the range from row 4 to row 0
+func (vim *Vim) resolveRange(spec string) (int, int, bool) {
+	ta := &vim.app.Editor.Textarea
+	cur := ta.Line()
+	last := ta.LineCount() - 1
+
+	spec = strings.TrimSpace(spec)
+	if spec == "%" {
+		return 0, last, true
+	}
+
+	sep := strings.IndexAny(spec, ",;")
+	if sep < 0 {
+		row, ok := vim.lineAddress(spec, cur)
+		return row, row, ok
+	}
+
+	start, ok := vim.lineAddress(spec[:sep], cur)
+	if !ok {
+		return 0, 0, false
+	}
+
+	if spec[sep] == ';' {
+		cur = start
+	}
+
+	end, ok := vim.lineAddress(spec[sep+1:], cur)
+	if !ok {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
+
+// confirmBackwardsRange asks for confirmation before fn is called with
+// a backwards range like `:5,1d`, which is then swapped like vim does.
+// Any other key than `y` cancels the command
+func (vim *Vim) confirmBackwardsRange(fn func(...string) StatusBarMsg) func(...string) StatusBarMsg {
+	return func(args ...string) StatusBarMsg {
+		rangeSpec, _ := exArgs(args)
+
+		if start, end, ok := vim.resolveRange(rangeSpec); !ok || start <= end {
+			return fn(args...)
+		}
+
+		vim.KeyMap.AwaitKey(func(key tea.Key) StatusBarMsg {
+			if key.String() != "y" {
+				return StatusBarMsg{}
+			}
+			return fn(args...)
+		})
+
+		return StatusBarMsg{
+			Content: message.StatusBar.BackwardsRange,
+			Type:    message.Prompt,
+		}
+	}
+}
+
+// lineAddress resolves a single line address like `5`, `.`, `$`, `'a`
+// followed by any number of offsets like `+2` or `-`.
+// Addresses without a line are relative to `cur`, addresses outside of
+// the note are moved to its first or last line, e.g. `:0` and `:999`.
+// Returns false if the address is invalid
+func (vim *Vim) lineAddress(addr string, cur int) (int, bool) {
+	editor := vim.app.Editor
+	last := editor.Textarea.LineCount() - 1
+	row := cur
+
+	addr = strings.TrimSpace(addr)
+	digits := func(s string) string {
+		return s[:len(s)-len(strings.TrimLeft(s, "0123456789"))]
+	}
+
+	switch {
+	case addr == "":
+		return row, true
+
+	case addr[0] == '.':
+		addr = addr[1:]
+
+	case addr[0] == '$':
+		row = last
+		addr = addr[1:]
+
+	case addr[0] == '\'':
+		if len(addr) < 2 {
+			return 0, false
+		}
+
+		path := editor.CurrentBuffer.Path(false)
+		mark, ok := editor.Marks.Get(rune(addr[1]), path)
+		if !ok || mark.Path != path {
+			return 0, false
+		}
+
+		row = mark.Pos.Row
+		addr = addr[2:]
+
+	case digits(addr) != "":
+		n := digits(addr)
+		line, _ := strconv.Atoi(n)
+		row = line - 1
+		addr = addr[len(n):]
+	}
+
+	for addr != "" {
+		sign := 1
+
+		switch addr[0] {
+		case '+':
+		case '-':
+			sign = -1
+		default:
+			return 0, false
+		}
+
+		addr = addr[1:]
+		n := 1
+
+		if d := digits(addr); d != "" {
+			n, _ = strconv.Atoi(d)
+			addr = addr[len(d):]
+		}
+
+		row += sign * n
+	}
+
+	return min(max(row, 0), last), true
+}
@@ -0,0 +1,68 @@
+package vim
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/message"
+)
+
+func TestLineAddressClamp(t *testing.T) {
+	vim, app := createTestApp(t, "a\nb\nc")
+	ta := &app.Editor.Textarea
+
+	tests := []struct {
+		spec       string
+		start, end int
+	}{
+		{"0", 0, 0},
+		{"99", 2, 2},
+		{"0,$", 0, 2},
+		{"2,99", 1, 2},
+		{".-5", 0, 0},
+	}
+
+	for _, tt := range tests {
+		start, end, ok := vim.lineRange(tt.spec)
+		if !ok || start != tt.start || end != tt.end {
+			t.Errorf("%q: expected %d,%d, got %d,%d (%v)", tt.spec, tt.start, tt.end, start, end, ok)
+		}
+	}
+
+	vim.CmdRegistry()[message.CmdPrompt.GoToLine]("99", "")
+
+	if got := ta.Line(); got != 2 {
+		t.Errorf("expected `:99` to go to the last line, got row %d", got)
+	}
+}
+
+func TestBackwardsRange(t *testing.T) {
+	vim, app := createTestApp(t, "a\nb\nc\nd")
+	ta := &app.Editor.Textarea
+	del := vim.CmdRegistry()[message.CmdPrompt.Delete]
+
+	msg := del("3,2", "")
+
+	if msg.Content != message.StatusBar.BackwardsRange {
+		t.Fatalf("expected a confirmation of the backwards range, got %q", msg.Content)
+	}
+
+	typeKeys(app, textKeys("n")...)
+
+	if got := ta.Value(); got != "a\nb\nc\nd" {
+		t.Fatalf("expected the command to be cancelled, got %q", got)
+	}
+
+	del("3,2", "")
+	typeKeys(app, textKeys("y")...)
+
+	if got := ta.Value(); got != "a\nd" {
+		t.Fatalf("expected the swapped range to be deleted, got %q", got)
+	}
+
+	// :g swaps the range without asking
+	vim.global("", "/d/.,-1d")
+
+	if got := ta.Value(); got != "" {
+		t.Fatalf("expected the backwards range of :g to be swapped, got %q", got)
+	}
+}
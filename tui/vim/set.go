@@ -0,0 +1,244 @@
+package vim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bellbird-notes/app/config"
+	"bellbird-notes/tui/message"
+	"bellbird-notes/tui/shared"
+)
+
+// cmdSet changes settings, e.g. `:set nu ic`, `:set border=rounded`,
+// `:set ic!` or `:set tm?`. Buffer-local settings are changed for the
+// current buffer and become the default of every other buffer.
+// Without arguments or with `all` every setting is listed
+func (vim *Vim) cmdSet(args ...string) StatusBarMsg {
+	return vim.set(cmdArg(args), false)
+}
+
+// cmdSetLocal is like cmdSet but buffer-local settings are only
+// changed for the current buffer
+func (vim *Vim) cmdSetLocal(args ...string) StatusBarMsg {
+	return vim.set(cmdArg(args), true)
+}
+
+// cmdArg returns the arguments of a command that has been called
+// with or without a line range
+func cmdArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(args[len(args)-1])
+}
+
+func (vim *Vim) set(arg string, local bool) StatusBarMsg {
+	fields := strings.Fields(arg)
+
+	if len(fields) == 0 || (len(fields) == 1 && fields[0] == "all") {
+		return vim.listSettings()
+	}
+
+	shown := []string{}
+	changed := false
+
+	for _, field := range fields {
+		s, value, query, ok := vim.parseSetArg(field)
+
+		if !ok {
+			if _, found := lookupSetArg(field); !found {
+				return StatusBarMsg{
+					Content: fmt.Sprintf(message.StatusBar.UnknownOption, field),
+					Type:    message.Error,
+				}
+			}
+
+			return StatusBarMsg{
+				Content: fmt.Sprintf(message.StatusBar.InvalidArgument, field),
+				Type:    message.Error,
+			}
+		}
+
+		if query {
+			shown = append(shown, s.Format(vim.app.Editor.Setting(s)))
+			continue
+		}
+
+		vim.assignSetting(s, value, local)
+		changed = true
+
+		if err := vim.applySetting(s); err != nil {
+			return StatusBarMsg{
+				Content: err.Error(),
+				Type:    message.Error,
+				Cmd:     shared.SendRefreshUiMsg(),
+			}
+		}
+	}
+
+	statusMsg := StatusBarMsg{Content: strings.Join(shown, "  ")}
+
+	if changed {
+		statusMsg.Cmd = shared.SendRefreshUiMsg()
+	}
+
+	return statusMsg
+}
+
+// parseSetArg parses an argument of `:set` and returns the setting
+// and its new value. query is true if the value should be shown instead,
+// e.g. `ic?` or `border`. Returns false for unknown settings
+// and invalid values
+func (vim *Vim) parseSetArg(arg string) (config.Setting, string, bool, bool) {
+	if name, value, ok := strings.Cut(arg, "="); ok {
+		s, found := config.LookupSetting(name)
+		if !found {
+			return s, "", false, false
+		}
+
+		value, err := s.Parse(value)
+		return s, value, false, err == nil
+	}
+
+	if name, ok := strings.CutSuffix(arg, "?"); ok {
+		s, found := config.LookupSetting(name)
+		return s, "", true, found
+	}
+
+	if name, ok := strings.CutSuffix(arg, "!"); ok {
+		s, found := config.LookupSetting(name)
+		if !found || s.Type != config.BoolSetting {
+			return s, "", false, false
+		}
+
+		return s, vim.toggledSetting(s), false, true
+	}
+
+	if s, found := config.LookupSetting(arg); found {
+		// only boolean settings are switched on, others are shown
+		if s.Type != config.BoolSetting {
+			return s, "", true, true
+		}
+
+		return s, "true", false, true
+	}
+
+	if name, ok := strings.CutPrefix(arg, "no"); ok {
+		s, found := config.LookupSetting(name)
+		return s, "false", false, found && s.Type == config.BoolSetting
+	}
+
+	if name, ok := strings.CutPrefix(arg, "inv"); ok {
+		s, found := config.LookupSetting(name)
+		if !found || s.Type != config.BoolSetting {
+			return s, "", false, false
+		}
+
+		return s, vim.toggledSetting(s), false, true
+	}
+
+	return config.Setting{}, "", false, false
+}
+
+// lookupSetArg returns the setting an argument of `:set` refers to
+func lookupSetArg(arg string) (config.Setting, bool) {
+	name := strings.TrimRight(arg, "?!")
+	name, _, _ = strings.Cut(name, "=")
+
+	if s, ok := config.LookupSetting(name); ok {
+		return s, true
+	}
+
+	for _, prefix := range []string{"no", "inv"} {
+		if s, ok := config.LookupSetting(strings.TrimPrefix(name, prefix)); ok {
+			return s, true
+		}
+	}
+
+	return config.Setting{}, false
+}
+
+// toggledSetting returns the opposite value of a boolean setting
+func (vim *Vim) toggledSetting(s config.Setting) string {
+	value, _ := strconv.ParseBool(vim.app.Editor.Setting(s))
+	return strconv.FormatBool(!value)
+}
+
+// assignSetting sets the value of a setting. Global values are written
+// to the config file if PersistSettings is enabled, otherwise they
+// last until the app is closed
+func (vim *Vim) assignSetting(s config.Setting, value string, local bool) {
+	conf := vim.app.Conf
+
+	if s.Scope == config.BufferScope {
+		vim.app.Editor.SetLocalSetting(s, value)
+
+		if local {
+			return
+		}
+	}
+
+	if conf.PersistSettings() {
+		conf.SetValue(s.Section, s.Option, value)
+	} else {
+		conf.Override(s.Section, s.Option, value)
+	}
+}
+
+// applySetting passes the value of a setting to the components
+// that don't read it from the config whenever it's needed
+func (vim *Vim) applySetting(s config.Setting) error {
+	value := config.Value{Value: vim.app.Editor.Setting(s)}
+
+	switch s.Option {
+	case config.TimeoutLen:
+		if ms, err := value.GetInt(); err == nil {
+			vim.app.KeyInput.SetSequenceTimeOut(ms)
+		}
+
+	case config.FlushDelay:
+		if ms, err := value.GetInt(); err == nil {
+			vim.app.Conf.SetFlushDelay(time.Duration(ms) * time.Millisecond)
+		}
+
+	case config.Spell, config.SpellLang:
+		return vim.app.Editor.RefreshSpell()
+	}
+
+	return nil
+}
+
+// ApplySettings passes the values of all settings to the components
+// that keep their own copy
+func (vim *Vim) ApplySettings() {
+	for _, s := range config.Settings {
+		vim.applySetting(s)
+	}
+}
+
+// listSettings shows every setting and its value in an overlay.
+// Buffer-local values of the current buffer are marked with `*`
+func (vim *Vim) listSettings() StatusBarMsg {
+	editor := vim.app.Editor
+	lines := []string{"  Option                Alias"}
+
+	for _, s := range config.Settings {
+		marker := " "
+		if s.Scope == config.BufferScope && editor.HasLocalSetting(s) {
+			marker = "*"
+		}
+
+		line := fmt.Sprintf(
+			"%s %-21s %s",
+			marker,
+			s.Format(editor.Setting(s)),
+			s.Alias,
+		)
+		lines = append(lines, line)
+	}
+
+	vim.app.ShowInfoOverlay("Settings", lines)
+	return StatusBarMsg{}
+}
@@ -0,0 +1,62 @@
+package vim
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/message"
+)
+
+func TestSetCommand(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	editor := app.Editor
+
+	vim.cmdSet("nu ic")
+	if !editor.LineNumbers() || !editor.SearchIgnoreCase() {
+		t.Error("expected line numbers and ignorecase to be enabled")
+	}
+
+	vim.cmdSet("nonu noic")
+	if editor.LineNumbers() || editor.SearchIgnoreCase() {
+		t.Error("expected line numbers and ignorecase to be disabled")
+	}
+
+	vim.cmdSet("number!")
+	if !editor.LineNumbers() {
+		t.Error("expected line numbers to be toggled on")
+	}
+
+	vim.cmdSet("tm=500 border=rounded")
+	if msg := vim.cmdSet("tm? border?"); msg.Content != "timeoutlen=500  border=rounded" {
+		t.Errorf("expected the values to be shown, got %q", msg.Content)
+	}
+
+	if msg := vim.cmdSet("border=round"); msg.Type != message.Error {
+		t.Error("expected an error for an invalid enum value")
+	}
+
+	if msg := vim.cmdSet("tm=abc"); msg.Type != message.Error {
+		t.Error("expected an error for an invalid number")
+	}
+
+	if msg := vim.cmdSet("noborder"); msg.Type != message.Error {
+		t.Error("expected an error for a non-boolean setting")
+	}
+
+	if msg := vim.cmdSet("foo"); msg.Type != message.Error {
+		t.Error("expected an error for an unknown setting")
+	}
+
+	// buffer-local settings only change the current buffer
+	vim.cmdSetLocal("nonu")
+	if editor.LineNumbers() {
+		t.Error("expected line numbers to be disabled locally")
+	}
+
+	editor.NewScratchBuffer("Scratch", "")
+	editor.SetContent()
+
+	if !editor.LineNumbers() || !editor.Textarea.ShowLineNumbers {
+		t.Error("expected other buffers to use the global value")
+	}
+}
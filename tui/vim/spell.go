@@ -0,0 +1,78 @@
+package vim
+
+import (
+	ki "bellbird-notes/tui/keyinput"
+)
+
+// nextMisspelled moves the cursor to the next or previous
+// misspelled word
+func (vim *Vim) nextMisspelled(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		return vim.app.Editor.NextMisspelled(
+			opts.GetBool(ki.Args.Prev),
+			opts.Count(),
+		)
+	}
+}
+
+// addSpellWord adds the word at the cursor to the good words
+// or marks it as wrong
+func (vim *Vim) addSpellWord(opts ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		return vim.app.Editor.AddSpellWord(opts.GetBool(ki.Args.Bad))
+	}
+}
+
+// suggestSpelling shows the suggestions for the word
+// at the cursor in an overlay
+func (vim *Vim) suggestSpelling(_ ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		word, suggestions, statusMsg := vim.app.Editor.SpellSuggestions()
+		if len(suggestions) == 0 {
+			return statusMsg
+		}
+
+		list := vim.app.SuggestionList
+
+		list.Load(word, suggestions)
+		list.Show()
+		list.Focus()
+		vim.app.CurrentOverlay = list.Overlay
+		vim.app.UpdateComponents(false)
+
+		return statusMsg
+	}
+}
+
+// closeSuggestionList closes the overlay of the spelling suggestions
+// without changing the word
+func (vim *Vim) closeSuggestionList(_ ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		if vim.app.SuggestionList.Focused() {
+			vim.hideSuggestionList()
+		}
+		return StatusBarMsg{}
+	}
+}
+
+// replaceWithSuggestion replaces the misspelled word
+// with the selected suggestion
+func (vim *Vim) replaceWithSuggestion() StatusBarMsg {
+	suggestion, ok := vim.app.SuggestionList.SelectedSuggestion()
+	vim.hideSuggestionList()
+
+	if !ok {
+		return StatusBarMsg{}
+	}
+
+	return vim.app.Editor.ReplaceMisspelled(suggestion)
+}
+
+func (vim *Vim) hideSuggestionList() {
+	vim.app.SuggestionList.Hide()
+	vim.app.SuggestionList.Blur()
+	vim.app.CurrentOverlay = nil
+
+	// suggestions are only made for words of the editor
+	vim.FocusColumn(3)
+}
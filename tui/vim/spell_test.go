@@ -0,0 +1,52 @@
+package vim
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bellbird-notes/tui/components/editor"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestSpellSuggestions(t *testing.T) {
+	vim, app := createTestApp(t, "hello wrld\nnote helo")
+
+	dir, err := editor.SpellDir()
+	if err != nil {
+		t.Fatalf("SpellDir failed: %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Mkdir failed: %v", err)
+	}
+
+	words := "hello\nworld\nnote\n"
+	if err := os.WriteFile(filepath.Join(dir, "en.txt"), []byte(words), 0644); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	ta := &app.Editor.Textarea
+	vim.cmdSetLocal("spell")
+
+	typeKeys(app, textKeys("2]sz=")...)
+
+	if !app.SuggestionList.Visible() {
+		t.Fatal("expected the suggestions to be shown")
+	}
+
+	if s, _ := app.SuggestionList.SelectedSuggestion(); s != "hello" {
+		t.Errorf("expected hello to be suggested, got %q", s)
+	}
+
+	typeKeys(app, tea.Key{Code: tea.KeyEnter})
+
+	if got := string(ta.Val()[1]); got != "note hello" {
+		t.Errorf("expected the word to be replaced, got %q", got)
+	}
+
+	if app.SuggestionList.Visible() {
+		t.Error("expected the suggestions to be hidden")
+	}
+}
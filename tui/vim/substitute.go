@@ -0,0 +1,218 @@
+package vim
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"bellbird-notes/tui/components/editor"
+	"bellbird-notes/tui/message"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// lastSubstitute holds the pattern and the replacement of the last
+// substitution so that `:s` without arguments can repeat it
+type lastSubstitute struct {
+	pattern, replacement string
+}
+
+// substitute replaces the matches of a regular expression within
+// a range of lines, e.g. `:%s/foo(\d)/bar\1/g`.
+// The flags are `g` to replace all matches of a line, `i` and `I` to
+// ignore or respect the case and `c` to confirm every replacement.
+// The replacement may refer to capture groups with `\1`-`\9`, `&` or `\0`
+// is the whole match and `\n` or `\r` insert a line break
+func (vim *Vim) substitute(args ...string) StatusBarMsg {
+	rangeSpec, cmd := "", ""
+	if len(args) == 2 {
+		rangeSpec, cmd = args[0], args[1]
+	}
+
+	startRow, endRow, ok := vim.lineRange(rangeSpec)
+	if !ok {
+		return StatusBarMsg{
+			Content: message.StatusBar.InvalidRange,
+			Type:    message.Error,
+		}
+	}
+
+	pattern, replacement, flags := "", "", ""
+
+	if cmd = strings.TrimSpace(cmd); cmd == "" {
+		pattern = vim.lastSubstitute.pattern
+		replacement = vim.lastSubstitute.replacement
+	} else {
+		if pattern, replacement, flags, ok = splitSubstitute(cmd); !ok {
+			return StatusBarMsg{
+				Content: fmt.Sprintf(message.StatusBar.InvalidPattern, cmd),
+				Type:    message.Error,
+			}
+		}
+
+		if pattern == "" {
+			pattern = vim.app.Editor.LastPattern()
+		}
+	}
+
+	if pattern == "" {
+		return StatusBarMsg{
+			Content: fmt.Sprintf(message.StatusBar.InvalidPattern, cmd),
+			Type:    message.Error,
+		}
+	}
+
+	vim.lastSubstitute = lastSubstitute{pattern, replacement}
+	vim.app.Editor.SetLastPattern(pattern)
+
+	sub := editor.Substitution{
+		Replacement: replacementTemplate(replacement),
+		StartRow:    startRow,
+		EndRow:      endRow,
+	}
+
+	expr := pattern
+
+	for _, flag := range flags {
+		switch flag {
+		case 'g':
+			sub.Global = true
+		case 'c':
+			sub.Confirm = true
+		case 'i':
+			expr = "(?i)" + pattern
+		case 'I':
+			expr = pattern
+		default:
+			return StatusBarMsg{
+				Content: fmt.Sprintf(message.StatusBar.InvalidPattern, cmd),
+				Type:    message.Error,
+			}
+		}
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return StatusBarMsg{
+			Content: fmt.Sprintf(message.StatusBar.InvalidPattern, pattern),
+			Type:    message.Error,
+		}
+	}
+	sub.Pattern = re
+
+	statusMsg, pending := vim.app.Editor.Substitute(sub)
+	if pending {
+		vim.awaitSubstituteConfirmation()
+	}
+
+	return statusMsg
+}
+
+// awaitSubstituteConfirmation passes the next key to the substitution
+// that waits for the confirmation of a replacement
+func (vim *Vim) awaitSubstituteConfirmation() {
+	vim.KeyMap.AwaitKey(func(key tea.Key) StatusBarMsg {
+		statusMsg, pending := vim.app.Editor.ConfirmSubstitution(key.String())
+		if pending {
+			vim.awaitSubstituteConfirmation()
+		}
+		return statusMsg
+	})
+}
+
+// splitSubstitute splits the arguments of `:s` like `/pattern/replacement/flags`
+// at the delimiter, which is the first character and can be any
+// character but letters, digits, white space, `\`, `"` and `|`.
+// Escaped delimiters are part of the pattern or the replacement.
+// Returns false if the delimiter is invalid
+func splitSubstitute(cmd string) (string, string, string, bool) {
+	runes := []rune(cmd)
+	delim := runes[0]
+
+	if !validDelimiter(delim) {
+		return "", "", "", false
+	}
+
+	fields := []string{}
+	var field strings.Builder
+
+	i := 1
+	for ; i < len(runes) && len(fields) < 2; i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\\' && i+1 < len(runes) && runes[i+1] == delim:
+			field.WriteRune(delim)
+			i++
+		case r == '\\' && i+1 < len(runes):
+			field.WriteRune(r)
+			field.WriteRune(runes[i+1])
+			i++
+		case r == delim:
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteRune(r)
+		}
+	}
+
+	// the closing delimiters are optional
+	if len(fields) < 2 {
+		fields = append(fields, field.String())
+	}
+	if len(fields) < 2 {
+		fields = append(fields, "")
+	}
+
+	flags := strings.TrimSpace(string(runes[i:]))
+
+	return fields[0], fields[1], flags, true
+}
+
+// validDelimiter returns whether the rune can delimit a pattern,
+// which is any character but letters, digits, white space, `\`, `"` and `|`
+func validDelimiter(delim rune) bool {
+	return !unicode.IsLetter(delim) &&
+		!unicode.IsDigit(delim) &&
+		!unicode.IsSpace(delim) &&
+		!strings.ContainsRune(`\"|`, delim)
+}
+
+// replacementTemplate converts the replacement of `:s` into
+// a template for regexp.Expand
+func replacementTemplate(replacement string) string {
+	var tmpl strings.Builder
+	runes := []rune(replacement)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+
+			switch next := runes[i]; {
+			case next >= '0' && next <= '9':
+				tmpl.WriteString("${" + string(next) + "}")
+			case next == 'n' || next == 'r':
+				tmpl.WriteRune('\n')
+			case next == 't':
+				tmpl.WriteRune('\t')
+			case next == '$':
+				tmpl.WriteString("$$")
+			default:
+				tmpl.WriteRune(next)
+			}
+
+		case r == '&':
+			tmpl.WriteString("${0}")
+		case r == '$':
+			tmpl.WriteString("$$")
+		default:
+			tmpl.WriteRune(r)
+		}
+	}
+
+	return tmpl.String()
+}
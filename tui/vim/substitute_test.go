@@ -0,0 +1,138 @@
+package vim
+
+import (
+	"testing"
+
+	"bellbird-notes/tui/components/editor"
+	"bellbird-notes/tui/message"
+	"bellbird-notes/tui/mode"
+)
+
+func TestSubstituteLastPattern(t *testing.T) {
+	vim, app := createTestApp(t, "foo bar\nbar foo\nbaz")
+	ta := &app.Editor.Textarea
+
+	if msg := vim.substitute("", "//x/"); msg.Content == "" {
+		t.Error("expected an error without a previous pattern")
+	}
+
+	// the pattern of the last search is used
+	app.Editor.Update(editor.SearchMsg{SearchTerm: "foo"})
+	app.Editor.Update(editor.SearchConfirmedMsg{})
+	app.Editor.Mode.Current = mode.Normal
+	app.Mode.Current = mode.Normal
+
+	msg := vim.substitute("%", "//x/")
+
+	if got := ta.Value(); got != "x bar\nbar x\nbaz" {
+		t.Errorf("expected the searched pattern to be replaced, got %q", got)
+	}
+
+	if msg.Content != "2 substitutions on 2 lines" {
+		t.Errorf("unexpected status message %q", msg.Content)
+	}
+
+	// `:s` itself sets the pattern of the next substitution
+	vim.substitute("1", "/bar/y/")
+	msg = vim.substitute("%", "//z/")
+
+	if got := ta.Value(); got != "x y\nz x\nbaz" {
+		t.Errorf("expected the last substituted pattern to be replaced, got %q", got)
+	}
+
+	if msg.Content != "1 substitution on 1 line" {
+		t.Errorf("unexpected status message %q", msg.Content)
+	}
+}
+
+func TestSubstituteCommand(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	ta := &app.Editor.Textarea
+
+	msg := vim.substitute("%", `/t(e)s(\d)?/[\2\1&]/gi`)
+
+	want := "[ETES]T1\n[eTes]t2\n[eTes]t3\n[etes]t4\n[5etes5]t"
+	if got := ta.Value(); got != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+
+	if msg.Content != "5 substitutions on 5 lines" {
+		t.Fatalf("Unexpected status message %q", msg.Content)
+	}
+
+	// all substitutions are undone at once
+	typeKeys(app, textKeys("u")...)
+
+	if got := ta.Value(); got != "TEST1\nTest2\nTest3\ntest4\ntes5t" {
+		t.Fatalf("Expected substitution to be undone, got %q", got)
+	}
+
+	// only the first match of the line without `g`, case sensitive by default
+	vim.substitute("2,$", "#t#_#")
+
+	want = "TEST1\nTes_2\nTes_3\n_est4\n_es5t"
+	if got := ta.Value(); got != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+
+	typeKeys(app, textKeys("u")...)
+
+	// confirm every substitution: replace, skip, replace and quit
+	vim.substitute("%", "/e/E/gc")
+	typeKeys(app, textKeys("nyyl")...)
+
+	want = "TEST1\nTest2\nTEst3\ntEst4\ntEs5t"
+	if got := ta.Value(); got != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+
+	if msg := vim.substitute("%", "/nothing/x/"); msg.Type != message.Error {
+		t.Fatalf("Expected an error if the pattern is not found")
+	}
+}
+
+func TestSplitSubstitute(t *testing.T) {
+	tests := []struct {
+		cmd                         string
+		pattern, replacement, flags string
+		ok                          bool
+	}{
+		{"/a/b/gi", "a", "b", "gi", true},
+		{"#a/b#c", "a/b", "c", "", true},
+		{`/a\/b/c\d/`, "a/b", `c\d`, "", true},
+		{"/a", "a", "", "", true},
+		{"/a/b", "a", "b", "", true},
+		{"xaxbx", "", "", "", false},
+		{`"a"b"`, "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		pattern, replacement, flags, ok := splitSubstitute(tt.cmd)
+
+		if ok != tt.ok || pattern != tt.pattern ||
+			replacement != tt.replacement || flags != tt.flags {
+
+			t.Errorf("%q: expected %q %q %q %v, got %q %q %q %v",
+				tt.cmd, tt.pattern, tt.replacement, tt.flags, tt.ok,
+				pattern, replacement, flags, ok)
+		}
+	}
+}
+
+func TestReplacementTemplate(t *testing.T) {
+	tests := []struct {
+		replacement, expected string
+	}{
+		{`[\2\1&]`, "[${2}${1}${0}]"},
+		{`a\nb\tc`, "a\nb\tc"},
+		{`\&$5\$`, "&$$5$$"},
+		{`\/`, "/"},
+	}
+
+	for _, tt := range tests {
+		if got := replacementTemplate(tt.replacement); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.replacement, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package vim
+
+import (
+	"bellbird-notes/app"
+	"bellbird-notes/app/debug"
+	"bellbird-notes/tui/components/editor"
+	ki "bellbird-notes/tui/keyinput"
+	"bellbird-notes/tui/message"
+)
+
+// cmdTasks opens an overlay listing the open tasks of all notes
+func (vim *Vim) cmdTasks(_ ...string) StatusBarMsg {
+	return vim.OverlayTasks()
+}
+
+// showTasks opens an overlay listing the open tasks of all notes
+func (vim *Vim) showTasks(_ ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		return vim.OverlayTasks()
+	}
+}
+
+// closeTaskList closes the overlay of the open tasks
+func (vim *Vim) closeTaskList(_ ki.Options) func() StatusBarMsg {
+	return func() StatusBarMsg {
+		if vim.app.TaskList.Focused() {
+			vim.hideTaskList()
+			vim.FocusColumn(vim.app.CurrColFocus)
+		}
+		return StatusBarMsg{}
+	}
+}
+
+// OverlayTasks scans the notes directory for open tasks
+// and shows them in an overlay
+func (vim *Vim) OverlayTasks() StatusBarMsg {
+	root, err := app.NotesRootDir()
+	if err == nil {
+		err = vim.app.TaskList.Load(root)
+	}
+
+	if err != nil {
+		debug.LogErr(err)
+		return StatusBarMsg{Content: err.Error(), Type: message.Error}
+	}
+
+	vim.app.TaskList.Show()
+	vim.app.TaskList.Focus()
+	vim.app.CurrentOverlay = vim.app.TaskList.Overlay
+	vim.app.UpdateComponents(false)
+
+	return StatusBarMsg{}
+}
+
+// openSelectedTask opens the note of the selected task
+// at the line of the task
+func (vim *Vim) openSelectedTask() StatusBarMsg {
+	task := vim.app.TaskList.SelectedTask()
+	if task == nil {
+		return StatusBarMsg{}
+	}
+
+	vim.hideTaskList()
+
+	statusMsg := vim.app.Editor.OpenBuffer(task.Path())
+	if !task.IsNote() {
+		vim.app.Editor.GoToLine(task.Line)
+	}
+
+	// the switch buffer msg tells the app to focus the editor
+	statusMsg.Cmd = editor.SendSwitchBufferMsg(task.Path(), true)
+
+	return statusMsg
+}
+
+func (vim *Vim) hideTaskList() {
+	vim.app.TaskList.Hide()
+	vim.app.TaskList.Blur()
+	vim.app.CurrentOverlay = nil
+}
@@ -0,0 +1,41 @@
+package vim
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenSelectedTask(t *testing.T) {
+	vim, app := createTestApp(t, testNote)
+
+	ta := &app.Editor.Textarea
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.txt")
+	content := "Tasks\n- [x] done\n- [ ] open\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if err := app.TaskList.Load(dir); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(app.TaskList.Items) != 2 || !app.TaskList.Items[0].IsNote() {
+		t.Fatalf("expected a note and its open task, got %d items", len(app.TaskList.Items))
+	}
+
+	// the task list jumps to the line of the selected task
+	app.TaskList.LineDown()
+	vim.openSelectedTask()
+
+	if got := app.Editor.CurrentBuffer.Path(false); got != path {
+		t.Errorf("expected %s to be opened, got %s", path, got)
+	}
+
+	if row := ta.CursorPos().Row; row != 2 {
+		t.Errorf("expected the cursor to be on the task, got row %d", row)
+	}
+}
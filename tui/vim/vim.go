@@ -11,12 +11,27 @@ type Vim struct {
 
 	// app holds the state and behaviour of all core components
 	app *application.App
+
+	// lastMacro is the register of the last played macro
+	// which is played again with `@@`
+	lastMacro rune
+
+	// lastSubstitute is repeated by `:s` without arguments
+	lastSubstitute lastSubstitute
+
+	// motionFailed is set by motions that can't move the cursor
+	// while an operator is pending, which cancels the operator
+	motionFailed bool
 }
 
 func (vim Vim) Mode() *mode.ModeInstance {
 	return vim.app.Mode
 }
 
+func (vim Vim) Changes() int {
+	return vim.app.Editor.Changes()
+}
+
 func New() *Vim { return &Vim{} }
 
 func (vim *Vim) SetApp(app *application.App) {